@@ -7,16 +7,24 @@ import (
 	"os"
 
 	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 )
 
 type Config struct {
-	App        AppConfig       `mapstructure:"app"`
-	Bot        BotConfig       `mapstructure:"bot"`
-	Database   DatabaseConfig  `mapstructure:"database"`
-	Migrations MigrationConfig `mapstructure:"migrations"`
-	Test       TestConfig      `mapstructure:"test"`
+	App           AppConfig       `mapstructure:"app"`
+	Bot           BotConfig       `mapstructure:"bot"`
+	Database      DatabaseConfig  `mapstructure:"database"`
+	Migrations    MigrationConfig `mapstructure:"migrations"`
+	Test          TestConfig      `mapstructure:"test"`
+	Variants      []VariantConfig `mapstructure:"variants"`
+	Reminders     ReminderConfig  `mapstructure:"reminders"`
+	Announcements AnnounceConfig  `mapstructure:"announcements"`
+	Ranks         []RankConfig    `mapstructure:"ranks"`
+	Norm          NormConfig      `mapstructure:"norm"`
+	AutoNorm      AutoNormConfig  `mapstructure:"auto_norm"`
+	GTG           GTGConfig       `mapstructure:"gtg"`
 }
 
 type BotConfig struct {
@@ -43,6 +51,75 @@ type AppConfig struct {
 	DebugMod bool   `mapstructure:"debug_mode"`
 }
 
+// VariantConfig описывает вариант отжиманий и его коэффициент сложности
+// относительно обычных отжиманий (1.0)
+type VariantConfig struct {
+	Code        string  `mapstructure:"code"`
+	Name        string  `mapstructure:"name"`
+	Coefficient float64 `mapstructure:"coefficient"`
+}
+
+// ReminderConfig настройки фоновых напоминаний
+type ReminderConfig struct {
+	Enabled             bool          `mapstructure:"enabled"`
+	CheckInterval       time.Duration `mapstructure:"check_interval"`
+	MaxTestIntervalDays int           `mapstructure:"max_test_interval_days"`
+	FromHour            int           `mapstructure:"from_hour"` // Не беспокоим раньше этого часа
+	ToHour              int           `mapstructure:"to_hour"`   // и позже этого часа
+}
+
+// AnnounceConfig настройки объявлений в общем чате
+type AnnounceConfig struct {
+	ChatID int64 `mapstructure:"chat_id"` // 0 — объявления выключены
+}
+
+// RankConfig описывает ступень лестницы рангов
+type RankConfig struct {
+	Threshold int    `mapstructure:"threshold"` // Минимальный максимум за подход для ранга
+	Emoji     string `mapstructure:"emoji"`
+	Name      string `mapstructure:"name"`
+	Frequency string `mapstructure:"frequency"` // Рекомендуемая частота тренировок
+}
+
+// NormConfig параметры формулы дневной нормы
+type NormConfig struct {
+	MinDaily        int               `mapstructure:"min_daily"`
+	MaxDaily        int               `mapstructure:"max_daily"`
+	MaxRepsCap      int               `mapstructure:"max_reps_cap"`
+	BaseCoefficient float64           `mapstructure:"base_coefficient"`
+	CoefficientStep float64           `mapstructure:"coefficient_step"`
+	MinCoefficient  float64           `mapstructure:"min_coefficient"`
+	Levels          []NormLevelConfig `mapstructure:"levels"`
+	TopBase         float64           `mapstructure:"top_base"`
+}
+
+// NormLevelConfig базовый коэффициент ACSM для уровня подготовки
+type NormLevelConfig struct {
+	MaxReps int     `mapstructure:"max_reps"`
+	Base    float64 `mapstructure:"base"`
+}
+
+// IsSet сообщает, задан ли раздел norm в конфигурации
+func (n NormConfig) IsSet() bool {
+	return n.MaxDaily > 0
+}
+
+// AutoNormConfig настройки ночной автоподстройки нормы
+type AutoNormConfig struct {
+	Enabled    bool    `mapstructure:"enabled"`
+	RunHour    int     `mapstructure:"run_hour"`    // Час запуска (раз в сутки)
+	WindowDays int     `mapstructure:"window_days"` // Сколько последних дней анализировать
+	RaiseRate  float64 `mapstructure:"raise_rate"`  // Доля выполненных дней для повышения нормы
+	LowerRate  float64 `mapstructure:"lower_rate"`  // Доля выполненных дней, ниже которой норма понижается
+	StepRatio  float64 `mapstructure:"step_ratio"`  // Шаг изменения нормы
+}
+
+// GTGConfig настройки режима grease-the-groove (напоминания о микроподходах)
+type GTGConfig struct {
+	Enabled       bool          `mapstructure:"enabled"`
+	CheckInterval time.Duration `mapstructure:"check_interval"` // Как часто проверять, кому пора напомнить
+}
+
 type TestConfig struct {
 	DBHost         string `mapstructure:"db_host"`
 	MigrationsPath string `mapstructure:"migrations_path"`
@@ -126,6 +203,103 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("min_conns cannot exceed max_conns")
 	}
 
+	// Проверка вариантов отжиманий
+	variantCodes := make(map[string]bool)
+	for _, v := range c.Variants {
+		if v.Code == "" || v.Name == "" {
+			return fmt.Errorf("variant code and name are required")
+		}
+		if variantCodes[v.Code] {
+			return fmt.Errorf("duplicate variant code: %s", v.Code)
+		}
+		if v.Coefficient <= 0 || v.Coefficient > 5 {
+			return fmt.Errorf("invalid coefficient for variant %s: %.2f", v.Code, v.Coefficient)
+		}
+		variantCodes[v.Code] = true
+	}
+
+	// Проверка напоминаний
+	if c.Reminders.Enabled {
+		if c.Reminders.CheckInterval < time.Minute {
+			return fmt.Errorf("reminders check_interval must be >= 1m")
+		}
+		if c.Reminders.MaxTestIntervalDays < 1 {
+			return fmt.Errorf("reminders max_test_interval_days must be >= 1")
+		}
+		if c.Reminders.FromHour < 0 || c.Reminders.ToHour > 24 || c.Reminders.FromHour >= c.Reminders.ToHour {
+			return fmt.Errorf("invalid reminders hours: %d-%d", c.Reminders.FromHour, c.Reminders.ToHour)
+		}
+	}
+
+	// Проверка лестницы рангов
+	for i, r := range c.Ranks {
+		if r.Name == "" {
+			return fmt.Errorf("rank name is required")
+		}
+		if i == 0 && r.Threshold != 0 {
+			return fmt.Errorf("first rank threshold must be 0, got %d", r.Threshold)
+		}
+		if i > 0 && r.Threshold <= c.Ranks[i-1].Threshold {
+			return fmt.Errorf("rank thresholds must be strictly ascending: %s", r.Name)
+		}
+	}
+
+	// Проверка автоподстройки нормы
+	if c.AutoNorm.Enabled {
+		if c.AutoNorm.RunHour < 0 || c.AutoNorm.RunHour > 23 {
+			return fmt.Errorf("invalid auto_norm run_hour: %d", c.AutoNorm.RunHour)
+		}
+		if c.AutoNorm.WindowDays < 1 {
+			return fmt.Errorf("auto_norm window_days must be >= 1")
+		}
+		if c.AutoNorm.LowerRate < 0 || c.AutoNorm.RaiseRate > 1 || c.AutoNorm.LowerRate >= c.AutoNorm.RaiseRate {
+			return fmt.Errorf("invalid auto_norm rates: lower %.2f, raise %.2f", c.AutoNorm.LowerRate, c.AutoNorm.RaiseRate)
+		}
+		if c.AutoNorm.StepRatio <= 0 || c.AutoNorm.StepRatio >= 1 {
+			return fmt.Errorf("auto_norm step_ratio must be in (0, 1)")
+		}
+	}
+
+	// Проверка режима grease-the-groove
+	if c.GTG.Enabled && c.GTG.CheckInterval < time.Minute {
+		return fmt.Errorf("gtg check_interval must be >= 1m")
+	}
+
+	// Проверка формулы нормы
+	if c.Norm.IsSet() {
+		if err := c.Norm.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Validate проверяет параметры формулы дневной нормы
+func (n NormConfig) Validate() error {
+	if n.MinDaily <= 0 || n.MaxDaily < n.MinDaily {
+		return fmt.Errorf("invalid norm daily limits: %d-%d", n.MinDaily, n.MaxDaily)
+	}
+	if n.MaxRepsCap <= 0 {
+		return fmt.Errorf("norm max_reps_cap must be > 0")
+	}
+	if n.MinCoefficient <= 0 || n.BaseCoefficient < n.MinCoefficient {
+		return fmt.Errorf("invalid norm coefficients: base %.2f, min %.2f", n.BaseCoefficient, n.MinCoefficient)
+	}
+	if n.CoefficientStep < 0 {
+		return fmt.Errorf("norm coefficient_step cannot be negative")
+	}
+	if n.TopBase <= 0 {
+		return fmt.Errorf("norm top_base must be > 0")
+	}
+	for i, level := range n.Levels {
+		if level.Base <= 0 {
+			return fmt.Errorf("norm level base must be > 0")
+		}
+		if level.MaxReps <= 0 || (i > 0 && level.MaxReps <= n.Levels[i-1].MaxReps) {
+			return fmt.Errorf("norm levels max_reps must be positive and ascending")
+		}
+	}
 	return nil
 }
 
@@ -0,0 +1,29 @@
+package hendler
+
+import (
+	"context"
+	"log"
+
+	ui "trackerbot/keyboard"
+	"trackerbot/model"
+	"trackerbot/presenter"
+)
+
+// handleAchievements показывает каталог достижений с прогрессом пользователя
+func (h *BotHandler) handleAchievements(ctx context.Context, userID int64, chatID int64) {
+	statuses, err := h.service.GetAchievements(ctx, userID)
+	if err != nil {
+		log.Printf("Ошибка получения достижений: %v", err)
+		h.sendError(chatID)
+		return
+	}
+
+	h.sendMarkdownMessage(chatID, presenter.FormatAchievements(statuses), ui.SettingsKeyboard())
+}
+
+// notifyAchievements отправляет уведомление о каждом новом достижении
+func (h *BotHandler) notifyAchievements(chatID int64, achievements []model.Achievement) {
+	for _, achievement := range achievements {
+		h.sendMarkdownMessage(chatID, presenter.FormatAchievementUnlocked(achievement), nil)
+	}
+}
@@ -0,0 +1,41 @@
+package hendler
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"trackerbot/config"
+	"trackerbot/presenter"
+)
+
+// RunAutoNorm раз в сутки (в cfg.RunHour) подстраивает нормы пользователей,
+// включивших автоподстройку. Блокируется до отмены ctx — запускать в отдельной горутине.
+func (h *BotHandler) RunAutoNorm(ctx context.Context, cfg config.AutoNormConfig) {
+	if !cfg.Enabled {
+		return
+	}
+
+	runPeriodically(ctx, time.Hour, func(ctx context.Context, now time.Time) {
+		if now.Hour() != cfg.RunHour {
+			return
+		}
+		h.adjustAutoNorms(ctx, cfg)
+	})
+}
+
+func (h *BotHandler) adjustAutoNorms(ctx context.Context, cfg config.AutoNormConfig) {
+	jobCtx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+	defer cancel()
+
+	adjustments, err := h.service.RunAutoNorm(jobCtx, cfg)
+	if err != nil {
+		log.Printf("RunAutoNorm error: %v", err)
+		return
+	}
+
+	for _, adjustment := range adjustments {
+		// В личном чате chatID совпадает с userID
+		h.sendMessage(adjustment.UserID, presenter.FormatNormAdjustment(adjustment), nil)
+	}
+}
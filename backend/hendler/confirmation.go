@@ -0,0 +1,85 @@
+package hendler
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+// pushupConfirmationTTL — сколько ждём подтверждения подозрительной записи
+const pushupConfirmationTTL = 15 * time.Minute
+
+// PendingPushups — запись, ожидающая нажатия кнопки пользователем
+// (подтверждение подозрительного подхода или «✅ Сделал» в напоминании GTG)
+type PendingPushups struct {
+	UserID  int64
+	Variant string
+	Count   int
+	expires time.Time
+}
+
+// ConfirmationManager хранит неподтверждённые записи на сервере: в кнопке лежит
+// только ID, и подтвердить запись можно ровно один раз — повторное нажатие или
+// старое сообщение её уже не найдут
+type ConfirmationManager struct {
+	mu      sync.Mutex
+	pending map[string]PendingPushups
+	ttl     time.Duration
+	now     func() time.Time
+}
+
+// NewConfirmationManager создаёт хранилище, в котором записи живут ttl
+func NewConfirmationManager(ttl time.Duration) *ConfirmationManager {
+	return &ConfirmationManager{
+		pending: make(map[string]PendingPushups),
+		ttl:     ttl,
+		now:     time.Now,
+	}
+}
+
+// Add сохраняет запись и возвращает ID для кнопки подтверждения
+func (m *ConfirmationManager) Add(userID int64, variant string, count int) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	for id, pending := range m.pending {
+		if now.After(pending.expires) {
+			delete(m.pending, id)
+		}
+	}
+
+	id := newConfirmationID()
+	m.pending[id] = PendingPushups{
+		UserID:  userID,
+		Variant: variant,
+		Count:   count,
+		expires: now.Add(m.ttl),
+	}
+	return id
+}
+
+// Take забирает запись пользователя: после этого ID больше не действует
+func (m *ConfirmationManager) Take(id string, userID int64) (PendingPushups, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	pending, ok := m.pending[id]
+	if !ok || pending.UserID != userID {
+		return PendingPushups{}, false
+	}
+
+	delete(m.pending, id)
+	if m.now().After(pending.expires) {
+		return PendingPushups{}, false
+	}
+	return pending, true
+}
+
+// newConfirmationID — случайный ID, который не повторится и после перезапуска бота
+func newConfirmationID() string {
+	buf := make([]byte, 8)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
@@ -0,0 +1,154 @@
+package hendler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	ui "trackerbot/keyboard"
+	"trackerbot/model"
+	"trackerbot/presenter"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// handleExercisePicker показывает каталог упражнений
+func (h *BotHandler) handleExercisePicker(ctx context.Context, chatID int64) {
+	exercises, err := h.service.GetExercises(ctx)
+	if err != nil {
+		log.Printf("GetExercises error: %v", err)
+		h.sendError(chatID)
+		return
+	}
+
+	h.sendMessage(chatID, "🏋️ Выберите упражнение:", ui.ExercisePickerInlineKeyboard(exercises))
+}
+
+// handleExerciseCallback обрабатывает выбор упражнения и действий с ним
+func (h *BotHandler) handleExerciseCallback(ctx context.Context, callback *tgbotapi.CallbackQuery) {
+	chatID := callback.Message.Chat.ID
+	userID := callback.From.ID
+
+	action, code, ok := strings.Cut(callback.Data, ":")
+	if !ok || code == "" {
+		h.answerCallback(callback.ID, "Некорректные данные")
+		return
+	}
+
+	exercise, err := h.service.GetExercise(ctx, code)
+	if err != nil {
+		log.Printf("GetExercise error: %v", err)
+		h.answerCallback(callback.ID, "Упражнение не найдено")
+		return
+	}
+
+	h.answerCallback(callback.ID, "")
+
+	switch action {
+	case "exercise":
+		text := fmt.Sprintf("%s\nЧто делаем?", presenter.FormatExerciseTitle(&exercise))
+		h.sendMessage(chatID, text, ui.ExerciseActionsInlineKeyboard(exercise.Code))
+
+	case "exercise_add":
+		h.requestInput(chatID, inputTypeExerciseReps, presenter.FormatExercisePrompt(&exercise, false), exercise.Code)
+
+	case "exercise_max":
+		h.requestInput(chatID, inputTypeExerciseMax, presenter.FormatExercisePrompt(&exercise, true), exercise.Code)
+
+	case "exercise_norm":
+		prompt := fmt.Sprintf("%s\n%s", presenter.FormatExerciseTitle(&exercise), h.numericConfigs[inputTypeExerciseNorm].prompt)
+		h.requestInput(chatID, inputTypeExerciseNorm, prompt, exercise.Code)
+
+	case "exercise_progress":
+		h.handleExerciseProgress(ctx, userID, chatID, exercise)
+	}
+}
+
+func (h *BotHandler) handleAddExerciseReps(ctx context.Context, userID int64, chatID int64, exercise string, count int) {
+	if exercise == "" {
+		exercise = model.ExercisePushups
+	}
+
+	if exercise == model.ExercisePushups {
+		h.handleAddPushups(ctx, userID, "", chatID, count)
+		return
+	}
+
+	vm, err := h.service.AddExerciseReps(ctx, userID, exercise, count)
+	if err != nil {
+		log.Printf("AddExerciseReps error: %v", err)
+		h.sendError(chatID)
+		return
+	}
+
+	h.sendMessage(chatID, presenter.FormatAddPushups(vm), ui.RestTimerInlineKeyboard(defaultRestSeconds))
+	h.notifyAchievements(chatID, vm.NewAchievements)
+}
+
+func (h *BotHandler) handleSetExerciseMax(ctx context.Context, userID int64, chatID int64, exercise string, count int) {
+	if exercise == "" {
+		exercise = model.ExercisePushups
+	}
+
+	vm, err := h.service.UpdateExerciseMax(ctx, userID, exercise, count)
+	if err != nil {
+		log.Printf("UpdateExerciseMax error: %v", err)
+		h.sendError(chatID)
+		return
+	}
+
+	h.sendMessage(chatID, presenter.FormatMaxReps(vm), ui.MainKeyboard())
+	h.notifyRankChange(chatID, vm.RankChange)
+	h.notifyAchievements(chatID, vm.NewAchievements)
+}
+
+func (h *BotHandler) handleSetExerciseNorm(ctx context.Context, userID int64, chatID int64, exercise string, dailyNorm int) {
+	if exercise == "" {
+		exercise = model.ExercisePushups
+	}
+
+	if err := h.service.SetExerciseNorm(ctx, userID, exercise, dailyNorm); err != nil {
+		log.Printf("SetExerciseNorm error: %v", err)
+		h.sendError(chatID)
+		return
+	}
+
+	h.sendMessage(chatID, fmt.Sprintf("✅ Дневная норма установлена: %d", dailyNorm), ui.MainKeyboard())
+}
+
+// handleExerciseProgress отправляет историю и график по упражнению
+func (h *BotHandler) handleExerciseProgress(ctx context.Context, userID int64, chatID int64, exercise model.Exercise) {
+	if exercise.Code == model.ExercisePushups {
+		h.handleProgressHistory(ctx, userID, chatID)
+		return
+	}
+
+	history, err := h.service.GetExerciseHistory(ctx, userID, exercise.Code)
+	if err != nil {
+		log.Printf("GetExerciseHistory error: %v", err)
+		h.sendError(chatID)
+		return
+	}
+
+	h.sendMessage(chatID, presenter.FormatExerciseHistory(&exercise, history), ui.MainKeyboard())
+
+	if len(history) == 0 {
+		return
+	}
+
+	image, err := h.service.BuildExerciseSchedule(ctx, exercise, history)
+	if err != nil {
+		log.Printf("Ошибка построения графика: %v", err)
+		return
+	}
+
+	photo := tgbotapi.NewPhoto(chatID, tgbotapi.FileBytes{
+		Name:  "schedule.png",
+		Bytes: image.Bytes(),
+	})
+
+	if _, err := h.bot.Send(photo); err != nil {
+		log.Printf("Ошибка отправки графика упражнения: %v", err)
+	}
+}
@@ -0,0 +1,141 @@
+package hendler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"trackerbot/config"
+	ui "trackerbot/keyboard"
+	"trackerbot/model"
+	"trackerbot/presenter"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// gtgPromptTTL — сколько действует кнопка «✅ Сделал» в напоминании
+const gtgPromptTTL = 12 * time.Hour
+
+// RunGTG рассылает напоминания о микроподходах тем, у кого подошло время.
+// Блокируется до отмены ctx — запускать в отдельной горутине.
+func (h *BotHandler) RunGTG(ctx context.Context, cfg config.GTGConfig) {
+	if !cfg.Enabled {
+		return
+	}
+
+	runPeriodically(ctx, cfg.CheckInterval, h.sendGTGPrompts)
+}
+
+func (h *BotHandler) sendGTGPrompts(ctx context.Context, now time.Time) {
+	jobCtx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+
+	prompts, err := h.service.GetDueGTGPrompts(jobCtx, now)
+	if err != nil {
+		log.Printf("GetDueGTGPrompts error: %v", err)
+		return
+	}
+
+	for _, prompt := range prompts {
+		// В личном чате chatID совпадает с userID
+		msg := tgbotapi.NewMessage(prompt.UserID, presenter.FormatGTGPrompt(prompt))
+		msg.ParseMode = tgbotapi.ModeHTML
+		promptID := h.gtgPrompts.Add(prompt.UserID, model.VariantStandard, prompt.SetSize)
+		msg.ReplyMarkup = ui.GTGPromptInlineKeyboard(promptID)
+
+		if _, err := h.bot.Send(msg); err != nil {
+			log.Printf("Ошибка отправки GTG-напоминания пользователю %d: %v", prompt.UserID, err)
+		}
+
+		// Следующее напоминание планируем и при ошибке отправки, чтобы не слать его каждую минуту
+		if err := h.service.MarkGTGPrompted(jobCtx, prompt, now); err != nil {
+			log.Printf("MarkGTGPrompted error: %v", err)
+		}
+	}
+}
+
+// handleGTG обрабатывает /gtg:
+//
+//	/gtg                     — показать настройки
+//	/gtg 10:00-18:00 60 [8]  — напоминать с 10 до 18 каждые 60 минут (по 8 повторений)
+//	/gtg off                 — выключить напоминания
+func (h *BotHandler) handleGTG(ctx context.Context, userID int64, chatID int64, args string) {
+	fields := strings.Fields(args)
+
+	switch {
+	case len(fields) == 0:
+		gtg, err := h.service.GetGTG(ctx, userID)
+		if err != nil {
+			log.Printf("GetGTG error: %v", err)
+			h.sendError(chatID)
+			return
+		}
+		h.sendMarkdownMessage(chatID, presenter.FormatGTGStatus(gtg), nil)
+
+	case fields[0] == "off":
+		if err := h.service.DisableGTG(ctx, userID); err != nil {
+			log.Printf("DisableGTG error: %v", err)
+			h.sendError(chatID)
+			return
+		}
+		h.sendMessage(chatID, "🔕 Напоминания о микроподходах выключены", nil)
+
+	default:
+		usage := "Использование: <code>/gtg 10:00-18:00 60</code> или <code>/gtg off</code>"
+		if len(fields) < 2 {
+			h.sendMarkdownMessage(chatID, usage, nil)
+			return
+		}
+
+		interval, err := strconv.Atoi(fields[1])
+		if err != nil {
+			h.sendMarkdownMessage(chatID, usage, nil)
+			return
+		}
+
+		setSize := 0
+		if len(fields) > 2 {
+			if setSize, err = strconv.Atoi(fields[2]); err != nil {
+				h.sendMessage(chatID, "Размер подхода должен быть числом", nil)
+				return
+			}
+		}
+
+		gtg, err := h.service.EnableGTG(ctx, userID, fields[0], interval, setSize)
+		if err != nil {
+			h.sendMessage(chatID, fmt.Sprintf("❌ %v", err), nil)
+			return
+		}
+		h.sendMarkdownMessage(chatID, presenter.FormatGTGStatus(gtg), nil)
+	}
+}
+
+// handleGTGCallback обрабатывает "gtg:done:<ID напоминания>" — записывает микроподход из напоминания.
+// Напоминание забирается из gtgPrompts до записи, поэтому двойное нажатие не запишет подход дважды
+func (h *BotHandler) handleGTGCallback(ctx context.Context, callback *tgbotapi.CallbackQuery) {
+	prompt, ok := h.gtgPrompts.Take(strings.TrimPrefix(callback.Data, "gtg:done:"), callback.From.ID)
+	if !ok {
+		h.answerCallback(callback.ID, "Подход уже записан или напоминание устарело")
+		h.editCallbackMessage(callback, "⌛ Подход уже записан или напоминание устарело")
+		return
+	}
+
+	chatID := callback.Message.Chat.ID
+	count := prompt.Count
+
+	vm, err := h.service.AddGTGSet(ctx, callback.From.ID, count)
+	if err != nil {
+		log.Printf("AddGTGSet error: %v", err)
+		h.answerCallback(callback.ID, "Ошибка")
+		h.sendError(chatID)
+		return
+	}
+
+	h.answerCallback(callback.ID, "Записано")
+	h.editCallbackMessage(callback, fmt.Sprintf("✅ Микроподход на %d записан", count))
+	h.sendMessage(chatID, presenter.FormatAddPushups(vm), ui.RestTimerInlineKeyboard(defaultRestSeconds))
+	h.notifyAchievements(chatID, vm.NewAchievements)
+}
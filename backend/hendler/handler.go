@@ -9,6 +9,7 @@ import (
 
 	"time"
 	ui "trackerbot/keyboard"
+	"trackerbot/model"
 	"trackerbot/presenter"
 	"trackerbot/service"
 
@@ -26,6 +27,10 @@ type numericConfig struct {
 	min         int
 	max         int
 	handler     func(ctx context.Context, userID int64, username string, chatID int64, value int)
+
+	// inputHandler используется вместо handler, когда обработчику нужен контекст ввода
+	// (упражнение, вариант отжиманий)
+	inputHandler func(ctx context.Context, userID int64, chatID int64, input PendingInput, value int)
 }
 
 type inputType int
@@ -33,7 +38,12 @@ type inputType int
 const (
 	inputDayLimit inputType = iota
 	inputTypeMaxReps
+	inputTypeMaxTest
+	inputTypeHistoryEdit
 	inputTypeCustomNorm
+	inputTypeExerciseReps
+	inputTypeExerciseMax
+	inputTypeExerciseNorm
 )
 const (
 	oneTimeEntryLimit    = 1000
@@ -42,19 +52,29 @@ const (
 )
 
 type BotHandler struct {
-	bot          TelegramBot
-	service      service.PushupService
-	inputManager *InputManager
+	bot           TelegramBot
+	service       service.PushupService
+	inputManager  *InputManager
+	confirmations *ConfirmationManager
+	gtgPrompts    *ConfirmationManager
+	restTimers    *RestTimerManager
+	inlineCache   *InlineCache
 
 	adminIDs       map[int64]bool
 	numericConfigs map[inputType]numericConfig
+
+	announceChatID int64 // Общий чат для объявлений о новых рангах (0 — выключено)
 }
 
 func NewBotHandler(bot TelegramBot, service service.PushupService) *BotHandler {
 	h := &BotHandler{
-		bot:          bot,
-		service:      service,
-		inputManager: NewInputManager(),
+		bot:           bot,
+		service:       service,
+		inputManager:  NewInputManager(),
+		confirmations: NewConfirmationManager(pushupConfirmationTTL),
+		gtgPrompts:    NewConfirmationManager(gtgPromptTTL),
+		restTimers:    NewRestTimerManager(),
+		inlineCache:   NewInlineCache(),
 		adminIDs: map[int64]bool{
 			1036193976: true,
 		},
@@ -66,7 +86,9 @@ func NewBotHandler(bot TelegramBot, service service.PushupService) *BotHandler {
 			placeholder: "Введите число",
 			min:         1,
 			max:         oneTimeEntryLimit,
-			handler:     h.handleAddPushups,
+			inputHandler: func(ctx context.Context, userID int64, chatID int64, input PendingInput, value int) {
+				h.handleAddPushupSet(ctx, userID, chatID, input.Variant, value)
+			},
 		},
 		inputTypeMaxReps: {
 			prompt:      "Введите максимальное количество отжиманий за один подход:",
@@ -75,6 +97,24 @@ func NewBotHandler(bot TelegramBot, service service.PushupService) *BotHandler {
 			max:         maxRepsLimit,
 			handler:     h.handleSetMaxReps,
 		},
+		inputTypeMaxTest: {
+			prompt:      "Введите результат — сколько отжиманий получилось за один подход:",
+			placeholder: "Введите число",
+			min:         1,
+			max:         maxRepsLimit,
+			inputHandler: func(ctx context.Context, userID int64, chatID int64, input PendingInput, value int) {
+				h.handleMaxTestResult(chatID, value)
+			},
+		},
+		inputTypeHistoryEdit: {
+			prompt:      "Введите исправленный результат за один подход:",
+			placeholder: "Введите число",
+			min:         1,
+			max:         maxRepsLimit,
+			inputHandler: func(ctx context.Context, userID int64, chatID int64, input PendingInput, value int) {
+				h.handleEditMaxRepsEntry(ctx, userID, chatID, input.RecordID, value)
+			},
+		},
 		inputTypeCustomNorm: {
 			prompt:      "Введите дневную норму отжиманий:",
 			placeholder: "Введите число",
@@ -84,6 +124,33 @@ func NewBotHandler(bot TelegramBot, service service.PushupService) *BotHandler {
 				h.handleSetCustomNorm(ctx, userID, chatID, value)
 			},
 		},
+		inputTypeExerciseReps: {
+			prompt:      "Введите количество:",
+			placeholder: "Введите число",
+			min:         1,
+			max:         oneTimeEntryLimit,
+			inputHandler: func(ctx context.Context, userID int64, chatID int64, input PendingInput, value int) {
+				h.handleAddExerciseReps(ctx, userID, chatID, input.Exercise, value)
+			},
+		},
+		inputTypeExerciseMax: {
+			prompt:      "Введите лучший результат за один подход:",
+			placeholder: "Введите число",
+			min:         1,
+			max:         maxRepsLimit,
+			inputHandler: func(ctx context.Context, userID int64, chatID int64, input PendingInput, value int) {
+				h.handleSetExerciseMax(ctx, userID, chatID, input.Exercise, value)
+			},
+		},
+		inputTypeExerciseNorm: {
+			prompt:      "Введите дневную норму:",
+			placeholder: "Введите число",
+			min:         1,
+			max:         oneTimeEntryLimit,
+			inputHandler: func(ctx context.Context, userID int64, chatID int64, input PendingInput, value int) {
+				h.handleSetExerciseNorm(ctx, userID, chatID, input.Exercise, value)
+			},
+		},
 	}
 
 	return h
@@ -95,6 +162,11 @@ func (h *BotHandler) HandleUpdate(update tgbotapi.Update) {
 		return
 	}
 
+	if update.InlineQuery != nil {
+		h.handleInlineQuery(update.InlineQuery)
+		return
+	}
+
 	if update.Message != nil {
 		h.handleMessage(update)
 	}
@@ -116,6 +188,25 @@ func (h *BotHandler) handleMessage(update tgbotapi.Update) {
 		return
 	}
 
+	// Команды с аргументами
+	switch update.Message.Command() {
+	case "week":
+		h.handleWeeklyGoal(ctx, userID, chatID, update.Message.CommandArguments())
+		return
+	case "gtg":
+		h.handleGTG(ctx, userID, chatID, update.Message.CommandArguments())
+		return
+	case "rest":
+		h.handleRestTimerCommand(chatID, update.Message.CommandArguments())
+		return
+	case "start":
+		// Из inline-режима приходят за карточкой: /start card
+		if update.Message.CommandArguments() == inlineCardStartParameter {
+			h.handleStartCard(ctx, chatID, userID, username)
+			return
+		}
+	}
+
 	// Команды
 	switch text {
 
@@ -126,14 +217,33 @@ func (h *BotHandler) handleMessage(update tgbotapi.Update) {
 		h.requestNumber(chatID, inputDayLimit)
 
 	case "🎯 Тест максимальных отжиманий":
-		h.requestNumber(chatID, inputTypeMaxReps)
+		h.handleMaxTestIntro(ctx, userID, chatID)
 
 	case "📝 Установить норму":
 		h.requestNumber(chatID, inputTypeCustomNorm)
 
+	case "/plan", "📋 План на сегодня":
+		h.handleDailyPlan(ctx, userID, chatID)
+
+	case "/workout", "▶️ Начать тренировку":
+		h.handleStartWorkout(ctx, userID, chatID)
+
+	case "/finish", "⏹ Завершить":
+		h.handleFinishWorkout(ctx, userID, chatID)
+
+	case "🏋️ Упражнения":
+		h.handleExercisePicker(ctx, chatID)
+
 	case "📊 Статистика":
 		h.handleFullStat(ctx, userID, chatID)
 		return
+
+	case "/heatmap":
+		h.handleHeatmap(ctx, userID, chatID)
+
+	case "/card":
+		h.handleStatsCard(ctx, userID, chatID)
+
 	case "⚙️ Дополнительно":
 		msg := tgbotapi.NewMessage(chatID, "Выберите действие:")
 		msg.ReplyMarkup = ui.SettingsKeyboard()
@@ -148,6 +258,24 @@ func (h *BotHandler) handleMessage(update tgbotapi.Update) {
 	case "📈 Мой прогресс":
 		h.handleProgressHistory(ctx, userID, chatID)
 
+	case "🧮 Стратегия нормы":
+		h.handleNormStrategies(ctx, userID, chatID)
+
+	case "📅 Недельная цель":
+		h.handleWeeklyGoal(ctx, userID, chatID, "")
+
+	case "/program", "🗓 Программы":
+		h.handlePrograms(ctx, userID, chatID)
+
+	case "🌴 Отдых":
+		h.handleRestStatus(ctx, userID, chatID)
+
+	case "/achievements", "🏅 Достижения":
+		h.handleAchievements(ctx, userID, chatID)
+
+	case "/review":
+		h.handleReviewFlags(ctx, userID, chatID)
+
 	case "⬅️ Назад":
 		msg := tgbotapi.NewMessage(chatID, "Главное меню:")
 		msg.ReplyMarkup = ui.MainKeyboard()
@@ -203,6 +331,11 @@ func (h *BotHandler) handlePendingInput(
 	// ✅ УСПЕХ — теперь очищаем
 	h.clearPendingInput(chatID)
 
+	if cfg.inputHandler != nil {
+		cfg.inputHandler(ctx, userID, chatID, input, value)
+		return
+	}
+
 	cfg.handler(ctx, userID, username, chatID, value)
 }
 
@@ -227,9 +360,15 @@ func (h *BotHandler) clearPendingInput(chatID int64) {
 }
 
 func (h *BotHandler) requestNumber(chatID int64, t inputType) {
+	h.requestInput(chatID, t, h.numericConfigs[t].prompt, "")
+}
+
+// requestInput запрашивает число с произвольным текстом запроса.
+// exercise — код упражнения, к которому относится ввод (пусто для отжиманий).
+func (h *BotHandler) requestInput(chatID int64, t inputType, prompt string, exercise string) {
 	cfg := h.numericConfigs[t]
 
-	msg := tgbotapi.NewMessage(chatID, cfg.prompt)
+	msg := tgbotapi.NewMessage(chatID, prompt)
 	msg.ReplyMarkup = tgbotapi.ForceReply{
 		ForceReply:            true,
 		InputFieldPlaceholder: cfg.placeholder,
@@ -242,12 +381,13 @@ func (h *BotHandler) requestNumber(chatID int64, t inputType) {
 		return
 	}
 
-	h.sendCancelButton(chatID, t, sentMsg.MessageID)
+	h.sendCancelButton(chatID, PendingInput{InputType: t, Exercise: exercise}, sentMsg.MessageID)
 }
 
 // sendCancelButton показывает inline-кнопку "Отменить" и сохраняет её ID в pendingInput.
 // Перед отправкой новой кнопки удаляет старую (если она была).
-func (h *BotHandler) sendCancelButton(chatID int64, inputType inputType, replyMsgID int) {
+// input — состояние ввода; MessageID и CancelMsgID в нём заменяются новыми
+func (h *BotHandler) sendCancelButton(chatID int64, input PendingInput, replyMsgID int) {
 	// 1) Если уже есть pendingInput — удаляем старое сообщение с кнопкой (чтобы не копилось)
 	if old, ok := h.inputManager.Get(chatID); ok {
 		if old.CancelMsgID != 0 {
@@ -258,8 +398,17 @@ func (h *BotHandler) sendCancelButton(chatID int64, inputType inputType, replyMs
 	}
 
 	// 2) Отправляем новое сообщение с inline-кнопкой "Отменить"
+	//    (для отжиманий — ещё и с выбором варианта)
 	cancelMsg := tgbotapi.NewMessage(chatID, "Если передумал — нажми Отменить:")
 	cancelMsg.ReplyMarkup = ui.CancelInlineKeyboard()
+	if input.InputType == inputDayLimit {
+		cancelMsg.Text = "Вариант отжиманий (по умолчанию — обычные). Если передумал — нажми Отменить:"
+		selected := input.Variant
+		if selected == "" {
+			selected = model.VariantStandard
+		}
+		cancelMsg.ReplyMarkup = ui.VariantCancelInlineKeyboard(h.service.GetPushupVariants(), selected)
+	}
 	sentCancelMsg, err := h.bot.Send(cancelMsg)
 	if err != nil {
 		log.Printf("sendCancelButton: ошибка отправки кнопки отмены: %v", err)
@@ -267,11 +416,9 @@ func (h *BotHandler) sendCancelButton(chatID int64, inputType inputType, replyMs
 	}
 
 	// 3) Сохраняем новое состояние (перезаписываем pendingInput для этого чата)
-	h.inputManager.Set(chatID, PendingInput{
-		InputType:   inputType,
-		MessageID:   replyMsgID,
-		CancelMsgID: sentCancelMsg.MessageID,
-	})
+	input.MessageID = replyMsgID
+	input.CancelMsgID = sentCancelMsg.MessageID
+	h.inputManager.Set(chatID, input)
 }
 
 func (h *BotHandler) handleAddPushups(
@@ -282,15 +429,38 @@ func (h *BotHandler) handleAddPushups(
 	count int,
 ) {
 
-	vm, err := h.service.AddPushups(ctx, userID, count)
+	h.handleAddPushupSet(ctx, userID, chatID, model.VariantStandard, count)
+}
+
+// handleAddPushupSet добавляет подход выбранного варианта отжиманий
+func (h *BotHandler) handleAddPushupSet(
+	ctx context.Context,
+	userID int64,
+	chatID int64,
+	variant string,
+	count int,
+) {
+	if variant == "" {
+		variant = model.VariantStandard
+	}
+
+	vm, err := h.service.AddPushupSet(ctx, userID, variant, count)
 	if err != nil {
 		h.sendError(chatID)
 		return
 	}
 
+	if vm.NeedsConfirmation {
+		response := presenter.FormatPushupsConfirmation(vm)
+		confirmationID := h.confirmations.Add(userID, variant, count)
+		h.sendMessage(chatID, response, ui.ConfirmPushupsInlineKeyboard(confirmationID))
+		return
+	}
+
 	response := presenter.FormatAddPushups(vm)
 
-	h.sendMessage(chatID, response, ui.MainKeyboard())
+	h.sendMessage(chatID, response, ui.RestTimerInlineKeyboard(defaultRestSeconds))
+	h.notifyAchievements(chatID, vm.NewAchievements)
 }
 
 func (h *BotHandler) handleSetMaxReps(
@@ -309,6 +479,8 @@ func (h *BotHandler) handleSetMaxReps(
 	response := presenter.FormatMaxReps(vm)
 
 	h.sendMessage(chatID, response, ui.MainKeyboard())
+	h.notifyRankChange(chatID, vm.RankChange)
+	h.notifyAchievements(chatID, vm.NewAchievements)
 }
 
 func (h *BotHandler) handleStart(ctx context.Context, chatID int64, userID int64, username string, perDayLimit inputType) {
@@ -377,41 +549,57 @@ func (h *BotHandler) handleProgressHistory(ctx context.Context, userID int64, ch
 		return
 	}
 
-	response := presenter.FormatProgressHistory(history)
-
-	h.sendMessage(chatID, response, ui.MainKeyboard())
+	var extras []string
 
-	if len(history) > 0 {
+	targets, err := h.service.GetWeeklyTargets(ctx, userID)
+	if err != nil {
+		log.Printf("Ошибка получения недельных целей: %v", err)
+	} else if block := presenter.FormatWeeklyTargets(targets); block != "" {
+		extras = append(extras, block)
+	}
 
-		image, err := h.service.BuildSchedule(ctx, userID, history)
-		if err != nil {
-			log.Println("Ошибка при отправке графика прогресса:", err)
-		}
+	rankHistory, err := h.service.GetRankHistory(ctx, userID)
+	if err != nil {
+		log.Printf("Ошибка получения истории рангов: %v", err)
+	} else if block := presenter.FormatRankHistory(rankHistory); block != "" {
+		extras = append(extras, block)
+	}
 
-		photo := tgbotapi.NewPhoto(chatID, tgbotapi.FileBytes{
-			Name:  "schedule.png",
-			Bytes: image.Bytes(),
-		})
+	if len(history) == 0 {
+		response := strings.Join(append([]string{presenter.FormatProgressHistory(history)}, extras...), "\n\n")
+		h.sendMessage(chatID, response, ui.MainKeyboard())
+		return
+	}
 
-		_, err = h.bot.Send(photo)
-		if err != nil {
-			log.Printf("Ошибка отправки сообщения handleProgressHistory: %v", err)
-			return
-		}
+	page, err := h.service.GetMaxRepsHistoryPage(ctx, userID, model.HistoryRangeAll, 0)
+	if err != nil {
+		log.Printf("Ошибка получения страницы истории: %v", err)
+		h.sendError(chatID)
+		return
+	}
 
+	h.sendMessage(chatID, presenter.FormatProgressHistoryPage(page), ui.MaxRepsHistoryInlineKeyboard(page))
+	if len(extras) > 0 {
+		h.sendMessage(chatID, strings.Join(extras, "\n\n"), ui.MainKeyboard())
 	}
+
+	h.sendProgressChart(ctx, userID, chatID, history, false)
 }
 
 // handleInfo отправляет инструкцию по использованию бота
 func (h *BotHandler) handleInfo(chatID int64) {
-	instruction := presenter.FormatInfoMessage()
+	instruction := presenter.FormatInfoMessage(h.service.GetRanks())
 	h.sendMarkdownMessage(chatID, instruction, ui.MainKeyboard())
 }
 
 func (h *BotHandler) handleCallback(update tgbotapi.Update) {
 	callback := update.CallbackQuery
 
-	if callback.Data == "cancel_input" {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	switch {
+	case callback.Data == "cancel_input":
 		chatID := callback.Message.Chat.ID
 
 		h.clearPendingInput(chatID)
@@ -443,6 +631,187 @@ func (h *BotHandler) handleCallback(update tgbotapi.Update) {
 			log.Printf("Ошибка отправки сообщения handleCallback(Ввод отменен): %v", err)
 		}
 
+	case strings.HasPrefix(callback.Data, "cancel_pushups:"):
+		h.confirmations.Take(strings.TrimPrefix(callback.Data, "cancel_pushups:"), callback.From.ID)
+		h.answerCallback(callback.ID, "Запись отменена")
+		h.editCallbackMessage(callback, "❌ Запись отменена")
+
+	case callback.Data == "start_max_test":
+		h.answerCallback(callback.ID, "")
+		h.handleMaxTestIntro(ctx, callback.From.ID, callback.Message.Chat.ID)
+
+	case strings.HasPrefix(callback.Data, "max_test:"):
+		h.handleMaxTestCallback(ctx, callback)
+
+	case strings.HasPrefix(callback.Data, "history:"):
+		h.handleMaxRepsHistoryCallback(ctx, callback)
+
+	case strings.HasPrefix(callback.Data, "volume:"):
+		h.handleVolumeChartCallback(ctx, callback)
+
+	case callback.Data == "heatmap":
+		h.handleHeatmapCallback(ctx, callback)
+
+	case callback.Data == "share_card":
+		h.handleStatsCardCallback(ctx, callback)
+
+	case strings.HasPrefix(callback.Data, "confirm_pushups:"):
+		h.handleConfirmPushups(ctx, callback)
+
+	case strings.HasPrefix(callback.Data, "variant:"):
+		h.handleVariantCallback(callback)
+
+	case strings.HasPrefix(callback.Data, "norm_strategy:"):
+		h.handleNormStrategyCallback(ctx, callback)
+
+	case strings.HasPrefix(callback.Data, "auto_norm:"):
+		h.handleAutoNormCallback(ctx, callback)
+
+	case strings.HasPrefix(callback.Data, "rest:"):
+		h.handleRestCallback(ctx, callback)
+
+	case strings.HasPrefix(callback.Data, "program:"):
+		h.handleProgramCallback(ctx, callback)
+
+	case strings.HasPrefix(callback.Data, "gtg:done:"):
+		h.handleGTGCallback(ctx, callback)
+
+	case strings.HasPrefix(callback.Data, "rest_timer:"):
+		h.handleRestTimerCallback(callback)
+
+	case strings.HasPrefix(callback.Data, "exercise"):
+		h.handleExerciseCallback(ctx, callback)
+
+	case strings.HasPrefix(callback.Data, "flag_approve:"),
+		strings.HasPrefix(callback.Data, "flag_exclude:"):
+		h.handleReviewCallback(ctx, callback)
+	}
+}
+
+// handleConfirmPushups сохраняет подозрительную запись после подтверждения пользователем.
+// Запись забирается из ConfirmationManager, поэтому повторное нажатие ничего не добавит
+func (h *BotHandler) handleConfirmPushups(ctx context.Context, callback *tgbotapi.CallbackQuery) {
+	chatID := callback.Message.Chat.ID
+	userID := callback.From.ID
+
+	pending, ok := h.confirmations.Take(strings.TrimPrefix(callback.Data, "confirm_pushups:"), userID)
+	if !ok {
+		h.answerCallback(callback.ID, "Подтверждение уже использовано или устарело")
+		h.editCallbackMessage(callback, "⌛ Подтверждение уже использовано или устарело")
+		return
+	}
+
+	vm, err := h.service.ConfirmPushups(ctx, userID, pending.Variant, pending.Count)
+	if err != nil {
+		log.Printf("ConfirmPushups error: %v", err)
+		h.answerCallback(callback.ID, "Ошибка")
+		h.sendError(chatID)
+		return
+	}
+
+	h.answerCallback(callback.ID, "Записано")
+	h.editCallbackMessage(callback, "✅ Запись подтверждена")
+	h.sendMessage(chatID, presenter.FormatAddPushups(vm), ui.RestTimerInlineKeyboard(defaultRestSeconds))
+	h.notifyAchievements(chatID, vm.NewAchievements)
+}
+
+// handleVariantCallback запоминает выбранный вариант отжиманий для ожидаемого ввода
+func (h *BotHandler) handleVariantCallback(callback *tgbotapi.CallbackQuery) {
+	chatID := callback.Message.Chat.ID
+	variant := strings.TrimPrefix(callback.Data, "variant:")
+
+	input, ok := h.inputManager.Get(chatID)
+	if !ok || input.InputType != inputDayLimit {
+		h.answerCallback(callback.ID, "Ввод уже завершён")
+		return
+	}
+
+	input.Variant = variant
+	h.inputManager.Set(chatID, input)
+
+	h.answerCallback(callback.ID, "Вариант выбран")
+
+	edit := tgbotapi.NewEditMessageReplyMarkup(
+		chatID,
+		callback.Message.MessageID,
+		ui.VariantCancelInlineKeyboard(h.service.GetPushupVariants(), variant),
+	)
+	if _, err := h.bot.Send(edit); err != nil {
+		log.Printf("Ошибка обновления клавиатуры вариантов: %v", err)
+	}
+}
+
+// handleReviewFlags показывает админу подозрительные записи, ожидающие проверки
+func (h *BotHandler) handleReviewFlags(ctx context.Context, userID int64, chatID int64) {
+	if !h.adminIDs[userID] {
+		h.sendMessage(chatID, "Неизвестная команда. Используйте меню.", ui.MainKeyboard())
+		return
+	}
+
+	flags, err := h.service.GetPendingFlags(ctx)
+	if err != nil {
+		log.Printf("GetPendingFlags error: %v", err)
+		h.sendError(chatID)
+		return
+	}
+
+	if len(flags) == 0 {
+		h.sendMessage(chatID, "✅ Нет записей на проверку", ui.MainKeyboard())
+		return
+	}
+
+	for _, flag := range flags {
+		h.sendMessage(chatID, presenter.FormatFlaggedEntry(flag), ui.ReviewFlagInlineKeyboard(flag.ID))
+	}
+}
+
+// handleReviewCallback применяет решение админа по подозрительной записи
+func (h *BotHandler) handleReviewCallback(ctx context.Context, callback *tgbotapi.CallbackQuery) {
+	if !h.adminIDs[callback.From.ID] {
+		h.answerCallback(callback.ID, "Недостаточно прав")
+		return
+	}
+
+	exclude := strings.HasPrefix(callback.Data, "flag_exclude:")
+	rawID := strings.TrimPrefix(strings.TrimPrefix(callback.Data, "flag_exclude:"), "flag_approve:")
+
+	flagID, err := strconv.ParseInt(rawID, 10, 64)
+	if err != nil {
+		h.answerCallback(callback.ID, "Некорректные данные")
+		return
+	}
+
+	if err := h.service.ReviewFlag(ctx, flagID, exclude); err != nil {
+		log.Printf("ReviewFlag error: %v", err)
+		h.answerCallback(callback.ID, "Ошибка")
+		return
+	}
+
+	result := "✅ Запись оставлена в рейтинге"
+	if exclude {
+		result = "🚫 Запись исключена из рейтинга и статистики"
+	}
+
+	h.answerCallback(callback.ID, result)
+	h.editCallbackMessage(callback, callback.Message.Text+"\n\n"+result)
+}
+
+// answerCallback отвечает на нажатие inline-кнопки
+func (h *BotHandler) answerCallback(callbackID string, text string) {
+	if _, err := h.bot.Request(tgbotapi.NewCallback(callbackID, text)); err != nil {
+		log.Printf("Ошибка ответа на callback: %v", err)
+	}
+}
+
+// editCallbackMessage заменяет текст сообщения с inline-кнопками (кнопки убираются)
+func (h *BotHandler) editCallbackMessage(callback *tgbotapi.CallbackQuery, text string) {
+	if callback.Message == nil {
+		return
+	}
+
+	edit := tgbotapi.NewEditMessageText(callback.Message.Chat.ID, callback.Message.MessageID, text)
+	if _, err := h.bot.Send(edit); err != nil {
+		log.Printf("Ошибка редактирования сообщения: %v", err)
 	}
 }
 
@@ -459,11 +828,10 @@ func (h *BotHandler) handleFullStat(
 		return
 	}
 
-
 	response := presenter.FormatFullStat(vm)
 
 	msg := tgbotapi.NewMessage(chatID, response)
-	msg.ReplyMarkup = ui.MainKeyboard()
+	msg.ReplyMarkup = ui.FullStatInlineKeyboard()
 
 	if _, err := h.bot.Send(msg); err != nil {
 		log.Printf("telegram send error: %v", err)
@@ -529,5 +897,9 @@ func (h *BotHandler) sendValidationError(chatID int64, t inputType, message stri
 		return
 	}
 
-	h.sendCancelButton(chatID, t, sentMsg.MessageID)
+	// Повторный запрос сохраняет всё состояние ввода: упражнение, вариант, исправляемую запись
+	input, _ := h.inputManager.Get(chatID)
+	input.InputType = t
+
+	h.sendCancelButton(chatID, input, sentMsg.MessageID)
 }
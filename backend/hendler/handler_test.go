@@ -3,8 +3,12 @@ package hendler
 import (
 	"bytes"
 	"context"
+	"errors"
 	"strconv"
+	"strings"
 	"testing"
+	"time"
+	"trackerbot/config"
 	"trackerbot/model"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
@@ -51,6 +55,33 @@ func (m *MockService) AddPushups(ctx context.Context, userID int64, count int) (
 	return nil, args.Error(1)
 }
 
+func (m *MockService) AddPushupSet(ctx context.Context, userID int64, variant string, count int) (*model.AddPushupsViewModel, error) {
+	args := m.Called(ctx, userID, variant, count)
+
+	if vm, ok := args.Get(0).(*model.AddPushupsViewModel); ok {
+		return vm, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockService) ConfirmPushups(ctx context.Context, userID int64, variant string, count int) (*model.AddPushupsViewModel, error) {
+	args := m.Called(ctx, userID, variant, count)
+
+	if vm, ok := args.Get(0).(*model.AddPushupsViewModel); ok {
+		return vm, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockService) GetPushupVariants() []model.PushupVariant {
+	args := m.Called()
+
+	if variants, ok := args.Get(0).([]model.PushupVariant); ok {
+		return variants
+	}
+	return nil
+}
+
 func (m *MockService) SetDailyNorm(ctx context.Context, userID int64, dailyNorm int) error {
 	args := m.Called(ctx, userID, dailyNorm)
 	return args.Error(0)
@@ -126,9 +157,10 @@ func (m *MockService) BuildSchedule(
 	ctx context.Context,
 	userID int64,
 	history []model.MaxRepsHistoryItem,
+	withNorm bool,
 ) (bytes.Buffer, error) {
 
-	args := m.Called(ctx, userID, history)
+	args := m.Called(ctx, userID, history, withNorm)
 
 	if buf, ok := args.Get(0).(bytes.Buffer); ok {
 		return buf, args.Error(1)
@@ -137,6 +169,349 @@ func (m *MockService) BuildSchedule(
 	return bytes.Buffer{}, args.Error(1)
 }
 
+func (m *MockService) GetPendingFlags(ctx context.Context) ([]model.FlaggedEntry, error) {
+	args := m.Called(ctx)
+
+	if flags, ok := args.Get(0).([]model.FlaggedEntry); ok {
+		return flags, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockService) ReviewFlag(ctx context.Context, flagID int64, exclude bool) error {
+	args := m.Called(ctx, flagID, exclude)
+	return args.Error(0)
+}
+
+func (m *MockService) GetExercises(ctx context.Context) ([]model.Exercise, error) {
+	args := m.Called(ctx)
+
+	if exercises, ok := args.Get(0).([]model.Exercise); ok {
+		return exercises, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockService) GetExercise(ctx context.Context, code string) (model.Exercise, error) {
+	args := m.Called(ctx, code)
+
+	if exercise, ok := args.Get(0).(model.Exercise); ok {
+		return exercise, args.Error(1)
+	}
+	return model.Exercise{}, args.Error(1)
+}
+
+func (m *MockService) AddExerciseReps(ctx context.Context, userID int64, code string, count int) (*model.AddPushupsViewModel, error) {
+	args := m.Called(ctx, userID, code, count)
+
+	if vm, ok := args.Get(0).(*model.AddPushupsViewModel); ok {
+		return vm, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockService) UpdateExerciseMax(ctx context.Context, userID int64, code string, count int) (*model.MaxRepsViewModel, error) {
+	args := m.Called(ctx, userID, code, count)
+
+	if vm, ok := args.Get(0).(*model.MaxRepsViewModel); ok {
+		return vm, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockService) SetExerciseNorm(ctx context.Context, userID int64, code string, dailyNorm int) error {
+	args := m.Called(ctx, userID, code, dailyNorm)
+	return args.Error(0)
+}
+
+func (m *MockService) GetExerciseHistory(ctx context.Context, userID int64, code string) ([]model.MaxRepsHistoryItem, error) {
+	args := m.Called(ctx, userID, code)
+
+	if history, ok := args.Get(0).([]model.MaxRepsHistoryItem); ok {
+		return history, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockService) BuildExerciseSchedule(
+	ctx context.Context,
+	exercise model.Exercise,
+	history []model.MaxRepsHistoryItem,
+) (bytes.Buffer, error) {
+
+	args := m.Called(ctx, exercise, history)
+
+	if buf, ok := args.Get(0).(bytes.Buffer); ok {
+		return buf, args.Error(1)
+	}
+	return bytes.Buffer{}, args.Error(1)
+}
+
+func (m *MockService) GetMaxTestReminders(ctx context.Context, interval time.Duration) ([]model.MaxTestReminder, error) {
+	args := m.Called(ctx, interval)
+
+	if reminders, ok := args.Get(0).([]model.MaxTestReminder); ok {
+		return reminders, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockService) MarkMaxTestReminded(ctx context.Context, userID int64) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (m *MockService) GetWeeklyTargets(ctx context.Context, userID int64) (*model.WeeklyTargetSummary, error) {
+	args := m.Called(ctx, userID)
+
+	if summary, ok := args.Get(0).(*model.WeeklyTargetSummary); ok {
+		return summary, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockService) GetAchievements(ctx context.Context, userID int64) ([]model.AchievementStatus, error) {
+	args := m.Called(ctx, userID)
+
+	if statuses, ok := args.Get(0).([]model.AchievementStatus); ok {
+		return statuses, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockService) GetRankHistory(ctx context.Context, userID int64) ([]model.RankChange, error) {
+	args := m.Called(ctx, userID)
+
+	if history, ok := args.Get(0).([]model.RankChange); ok {
+		return history, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockService) GetRanks() []model.RankInfo {
+	args := m.Called()
+
+	if ranks, ok := args.Get(0).([]model.RankInfo); ok {
+		return ranks
+	}
+	return nil
+}
+
+func (m *MockService) GetNormStrategies() []model.NormStrategyInfo {
+	args := m.Called()
+
+	if strategies, ok := args.Get(0).([]model.NormStrategyInfo); ok {
+		return strategies
+	}
+	return nil
+}
+
+func (m *MockService) GetUserNormStrategy(ctx context.Context, userID int64) (model.NormStrategyInfo, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(model.NormStrategyInfo), args.Error(1)
+}
+
+func (m *MockService) SetNormStrategy(ctx context.Context, userID int64, code string) (model.NormStrategyInfo, error) {
+	args := m.Called(ctx, userID, code)
+	return args.Get(0).(model.NormStrategyInfo), args.Error(1)
+}
+
+func (m *MockService) GetAutoNorm(ctx context.Context, userID int64) (bool, error) {
+	args := m.Called(ctx, userID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockService) SetAutoNorm(ctx context.Context, userID int64, enabled bool) error {
+	args := m.Called(ctx, userID, enabled)
+	return args.Error(0)
+}
+
+func (m *MockService) RunAutoNorm(ctx context.Context, cfg config.AutoNormConfig) ([]model.NormAdjustment, error) {
+	args := m.Called(ctx, cfg)
+	return args.Get(0).([]model.NormAdjustment), args.Error(1)
+}
+
+func (m *MockService) GetWeeklyGoal(ctx context.Context, userID int64) (model.WeeklyGoal, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(model.WeeklyGoal), args.Error(1)
+}
+
+func (m *MockService) GetWeeklyProgress(ctx context.Context, userID int64) (*model.WeeklyProgress, error) {
+	args := m.Called(ctx, userID)
+	progress, _ := args.Get(0).(*model.WeeklyProgress)
+	return progress, args.Error(1)
+}
+
+func (m *MockService) SetWeeklyGoal(ctx context.Context, userID int64, volume, days int) (model.WeeklyGoal, error) {
+	args := m.Called(ctx, userID, volume, days)
+	return args.Get(0).(model.WeeklyGoal), args.Error(1)
+}
+
+func (m *MockService) DisableWeeklyGoal(ctx context.Context, userID int64) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (m *MockService) GetActiveRest(ctx context.Context, userID int64) (*model.RestPeriod, error) {
+	args := m.Called(ctx, userID)
+	period, _ := args.Get(0).(*model.RestPeriod)
+	return period, args.Error(1)
+}
+
+func (m *MockService) StartRest(ctx context.Context, userID int64, kind string, days int) (model.RestPeriod, error) {
+	args := m.Called(ctx, userID, kind, days)
+	return args.Get(0).(model.RestPeriod), args.Error(1)
+}
+
+func (m *MockService) EndRest(ctx context.Context, userID int64) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (m *MockService) GetDailyPlan(ctx context.Context, userID int64) (*model.DailyPlan, error) {
+	args := m.Called(ctx, userID)
+	plan, _ := args.Get(0).(*model.DailyPlan)
+	return plan, args.Error(1)
+}
+
+func (m *MockService) GetPrograms() []model.Program {
+	args := m.Called()
+	return args.Get(0).([]model.Program)
+}
+
+func (m *MockService) EnrollProgram(ctx context.Context, userID int64, code string) (*model.ProgramWorkout, error) {
+	args := m.Called(ctx, userID, code)
+	workout, _ := args.Get(0).(*model.ProgramWorkout)
+	return workout, args.Error(1)
+}
+
+func (m *MockService) GetProgramWorkout(ctx context.Context, userID int64) (*model.ProgramWorkout, error) {
+	args := m.Called(ctx, userID)
+	workout, _ := args.Get(0).(*model.ProgramWorkout)
+	return workout, args.Error(1)
+}
+
+func (m *MockService) CompleteProgramSession(ctx context.Context, userID int64, week, day int, completed bool) (*model.ProgramSessionResult, error) {
+	args := m.Called(ctx, userID, week, day, completed)
+	result, _ := args.Get(0).(*model.ProgramSessionResult)
+	return result, args.Error(1)
+}
+
+func (m *MockService) LeaveProgram(ctx context.Context, userID int64) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+
+func (m *MockService) GetGTG(ctx context.Context, userID int64) (model.GTGPrompt, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(model.GTGPrompt), args.Error(1)
+}
+
+func (m *MockService) EnableGTG(ctx context.Context, userID int64, window string, intervalMinutes, setSize int) (model.GTGPrompt, error) {
+	args := m.Called(ctx, userID, window, intervalMinutes, setSize)
+	return args.Get(0).(model.GTGPrompt), args.Error(1)
+}
+
+func (m *MockService) DisableGTG(ctx context.Context, userID int64) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (m *MockService) GetDueGTGPrompts(ctx context.Context, now time.Time) ([]model.GTGPrompt, error) {
+	args := m.Called(ctx, now)
+	prompts, _ := args.Get(0).([]model.GTGPrompt)
+	return prompts, args.Error(1)
+}
+
+func (m *MockService) MarkGTGPrompted(ctx context.Context, prompt model.GTGPrompt, now time.Time) error {
+	args := m.Called(ctx, prompt, now)
+	return args.Error(0)
+}
+
+func (m *MockService) StartWorkout(ctx context.Context, userID int64) (model.WorkoutSession, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(model.WorkoutSession), args.Error(1)
+}
+
+func (m *MockService) FinishWorkout(ctx context.Context, userID int64) (*model.WorkoutSummary, error) {
+	args := m.Called(ctx, userID)
+	summary, _ := args.Get(0).(*model.WorkoutSummary)
+	return summary, args.Error(1)
+}
+
+func (m *MockService) GetMaxTestReadiness(ctx context.Context, userID int64) (model.MaxTestReadiness, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(model.MaxTestReadiness), args.Error(1)
+}
+
+func (m *MockService) CheckMaxTestResult(ctx context.Context, userID int64, count int) (model.MaxTestCheck, error) {
+	args := m.Called(ctx, userID, count)
+	return args.Get(0).(model.MaxTestCheck), args.Error(1)
+}
+
+func (m *MockService) RecordMaxTest(ctx context.Context, userID int64, count, rpe int) (*model.MaxRepsViewModel, error) {
+	args := m.Called(ctx, userID, count, rpe)
+	vm, _ := args.Get(0).(*model.MaxRepsViewModel)
+	return vm, args.Error(1)
+}
+
+func (m *MockService) EditMaxRepsEntry(ctx context.Context, userID int64, recordID int64, count int) (*model.MaxRepsHistoryChange, error) {
+	args := m.Called(ctx, userID, recordID, count)
+	change, _ := args.Get(0).(*model.MaxRepsHistoryChange)
+	return change, args.Error(1)
+}
+
+func (m *MockService) DeleteMaxRepsEntry(ctx context.Context, userID int64, recordID int64) (*model.MaxRepsHistoryChange, error) {
+	args := m.Called(ctx, userID, recordID)
+	change, _ := args.Get(0).(*model.MaxRepsHistoryChange)
+	return change, args.Error(1)
+}
+
+func (m *MockService) RecalculateNorm(ctx context.Context, userID int64) (int, error) {
+	args := m.Called(ctx, userID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockService) GetMaxRepsHistoryPage(ctx context.Context, userID int64, rangeCode string, page int) (*model.MaxRepsHistoryPage, error) {
+	args := m.Called(ctx, userID, rangeCode, page)
+	historyPage, _ := args.Get(0).(*model.MaxRepsHistoryPage)
+	return historyPage, args.Error(1)
+}
+
+func (m *MockService) BuildVolumeChart(ctx context.Context, userID int64, days int) (bytes.Buffer, error) {
+	args := m.Called(ctx, userID, days)
+	buf, _ := args.Get(0).(bytes.Buffer)
+	return buf, args.Error(1)
+}
+
+func (m *MockService) BuildHeatmap(ctx context.Context, userID int64) (bytes.Buffer, error) {
+	args := m.Called(ctx, userID)
+	buf, _ := args.Get(0).(bytes.Buffer)
+	return buf, args.Error(1)
+}
+
+func (m *MockService) GetStatsCard(ctx context.Context, userID int64) (*model.StatsCard, error) {
+	args := m.Called(ctx, userID)
+	card, _ := args.Get(0).(*model.StatsCard)
+	return card, args.Error(1)
+}
+
+func (m *MockService) BuildStatsCard(ctx context.Context, userID int64) (*model.StatsCard, bytes.Buffer, error) {
+	args := m.Called(ctx, userID)
+	card, _ := args.Get(0).(*model.StatsCard)
+	buf, _ := args.Get(1).(bytes.Buffer)
+	return card, buf, args.Error(2)
+}
+
+func (m *MockService) AddGTGSet(ctx context.Context, userID int64, count int) (*model.AddPushupsViewModel, error) {
+	args := m.Called(ctx, userID, count)
+
+	if vm, ok := args.Get(0).(*model.AddPushupsViewModel); ok {
+		return vm, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
 
 func TestHandleAddPushups(t *testing.T) {
 	mockService := new(MockService)
@@ -157,7 +532,7 @@ func TestHandleAddPushups(t *testing.T) {
 	}
 
 	mockService.
-		On("AddPushups", mock.Anything, int64(1), 10).
+		On("AddPushupSet", mock.Anything, int64(1), model.VariantStandard, 10).
 		Return(vm, nil).
 		Once()
 
@@ -317,13 +692,17 @@ func TestHandleProgressHistory(t *testing.T) {
 	fakeImage := []byte("fake image")
 
 	mockService.On("GetMaxRepsHistory", ctx, userID).Return(history, nil)
-	mockService.On("BuildSchedule", ctx, userID, history).Return(fakeImage, nil)
+	mockService.On("GetMaxRepsHistoryPage", ctx, userID, model.HistoryRangeAll, 0).
+		Return(&model.MaxRepsHistoryPage{Items: history, Range: model.HistoryRangeAll, Pages: 1, Total: 2}, nil)
+	mockService.On("BuildSchedule", ctx, userID, history, false).Return(fakeImage, nil)
+	mockService.On("GetWeeklyTargets", ctx, userID).Return(&model.WeeklyTargetSummary{}, nil)
+	mockService.On("GetRankHistory", ctx, userID).Return([]model.RankChange{}, nil)
 	mockBot.On("Send", mock.Anything).Return(tgbotapi.Message{}, nil)
 
 	handler.handleProgressHistory(ctx, userID, chatID)
 
 	mockService.AssertCalled(t, "GetMaxRepsHistory", ctx, userID)
-	mockService.AssertCalled(t, "BuildSchedule", ctx, userID, history)
+	mockService.AssertCalled(t, "BuildSchedule", ctx, userID, history, false)
 	mockBot.AssertCalled(t, "Send", mock.Anything)
 }
 
@@ -334,6 +713,7 @@ func TestHandleInfo(t *testing.T) {
 	handler := NewBotHandler(mockBot, mockService)
 
 	chatID := int64(123)
+	mockService.On("GetRanks").Return([]model.RankInfo{{Threshold: 0, Emoji: "💤", Name: "Сонная муха"}})
 	mockBot.On("Send", mock.Anything).Return(tgbotapi.Message{}, nil)
 
 	handler.handleInfo(chatID)
@@ -388,7 +768,7 @@ func TestHandlePendingInput_AllCases(t *testing.T) {
 			if !tt.wantError {
 				switch tt.inputType {
 				case inputDayLimit:
-					mockService.On("AddPushups", ctx, userID, mock.Anything).Return(
+					mockService.On("AddPushupSet", ctx, userID, model.VariantStandard, mock.Anything).Return(
 						&model.AddPushupsViewModel{AddedCount: 100, Total: 100, DailyNorm: 150}, nil)
 				case inputTypeMaxReps:
 					mockService.On("UpdateMaxReps", ctx, userID, mock.Anything).Return(
@@ -398,6 +778,10 @@ func TestHandlePendingInput_AllCases(t *testing.T) {
 				}
 			}
 
+			mockService.On("GetPushupVariants").Return([]model.PushupVariant{
+				{Code: model.VariantStandard, Name: "⚪ Обычные", Coefficient: 1},
+			}).Maybe()
+
 			// --- Настраиваем мок бота на любые вызовы Send ---
 			mockBot.On("Send", mock.MatchedBy(func(c tgbotapi.Chattable) bool { return true })).
 				Return(tgbotapi.Message{}, nil)
@@ -423,3 +807,1076 @@ func TestHandlePendingInput_AllCases(t *testing.T) {
 		})
 	}
 }
+
+func TestHandlePendingInput_KeepsVariantAfterInvalidInput(t *testing.T) {
+	mockService := new(MockService)
+	mockBot := new(MockBot)
+
+	handler := NewBotHandler(mockBot, mockService)
+	ctx := context.Background()
+	handler.inputManager.Set(100, PendingInput{InputType: inputDayLimit, MessageID: 1, CancelMsgID: 2, Variant: "diamond"})
+
+	mockService.On("GetPushupVariants").Return([]model.PushupVariant{
+		{Code: model.VariantStandard, Name: "⚪ Обычные", Coefficient: 1},
+		{Code: "diamond", Name: "💎 Алмазные", Coefficient: 1.4},
+	})
+	mockBot.On("Send", mock.MatchedBy(func(msg tgbotapi.MessageConfig) bool {
+		markup, ok := msg.ReplyMarkup.(tgbotapi.InlineKeyboardMarkup)
+		return ok && markup.InlineKeyboard[0][1].Text == "✅ 💎 Алмазные" &&
+			markup.InlineKeyboard[0][0].Text == "⚪ Обычные"
+	})).Return(tgbotapi.Message{MessageID: 4}, nil).Once()
+	mockBot.On("Send", mock.Anything).Return(tgbotapi.Message{MessageID: 3}, nil)
+	mockService.On("AddPushupSet", ctx, int64(1), "diamond", 20).
+		Return(&model.AddPushupsViewModel{AddedCount: 20, Total: 28, DailyNorm: 100}, nil).Once()
+
+	input, _ := handler.getPendingInput(100)
+	handler.handlePendingInput(ctx, input, 1, "ivan", 100, "двадцать")
+
+	input, ok := handler.getPendingInput(100)
+	assert.True(t, ok)
+	assert.Equal(t, "diamond", input.Variant)
+
+	handler.handlePendingInput(ctx, input, 1, "ivan", 100, "20")
+
+	mockService.AssertExpectations(t)
+	mockBot.AssertExpectations(t)
+}
+
+func TestHandleAddPushups_NeedsConfirmation(t *testing.T) {
+	mockService := new(MockService)
+	mockBot := new(MockBot)
+
+	handler := NewBotHandler(mockBot, mockService)
+
+	vm := &model.AddPushupsViewModel{
+		AddedCount:        300,
+		DailyNorm:         100,
+		NeedsConfirmation: true,
+		Reason:            "подход 300 больше 1.5× максимума за подход (20)",
+	}
+
+	mockService.
+		On("AddPushupSet", mock.Anything, int64(1), model.VariantStandard, 300).
+		Return(vm, nil).
+		Once()
+
+	mockBot.
+		On("Send", mock.MatchedBy(func(c tgbotapi.Chattable) bool {
+			msg, ok := c.(tgbotapi.MessageConfig)
+			if !ok {
+				return false
+			}
+			_, inline := msg.ReplyMarkup.(tgbotapi.InlineKeyboardMarkup)
+			return inline
+		})).
+		Return(tgbotapi.Message{}, nil).
+		Once()
+
+	handler.handleAddPushups(context.Background(), 1, "john", 123, 300)
+
+	mockService.AssertExpectations(t)
+	mockBot.AssertExpectations(t)
+}
+
+func TestHandleConfirmPushups_SingleUse(t *testing.T) {
+	mockService := new(MockService)
+	mockBot := new(MockBot)
+
+	handler := NewBotHandler(mockBot, mockService)
+	confirmationID := handler.confirmations.Add(1, model.VariantStandard, 300)
+
+	callback := &tgbotapi.CallbackQuery{
+		ID:      "cb",
+		From:    &tgbotapi.User{ID: 1},
+		Data:    "confirm_pushups:" + confirmationID,
+		Message: &tgbotapi.Message{MessageID: 5, Chat: &tgbotapi.Chat{ID: 100}},
+	}
+
+	mockService.
+		On("ConfirmPushups", mock.Anything, int64(1), model.VariantStandard, 300).
+		Return(&model.AddPushupsViewModel{AddedCount: 300, DailyNorm: 100}, nil).
+		Once()
+	mockBot.On("Request", mock.Anything).Return(&tgbotapi.APIResponse{Ok: true}, nil)
+	mockBot.On("Send", mock.Anything).Return(tgbotapi.Message{}, nil)
+
+	handler.handleConfirmPushups(context.Background(), callback)
+	handler.handleConfirmPushups(context.Background(), callback)
+
+	mockService.AssertExpectations(t)
+	mockBot.AssertCalled(t, "Send", mock.MatchedBy(func(msg tgbotapi.EditMessageTextConfig) bool {
+		return strings.Contains(msg.Text, "уже использовано")
+	}))
+}
+
+func TestConfirmationManager_OtherUser(t *testing.T) {
+	manager := NewConfirmationManager(pushupConfirmationTTL)
+	id := manager.Add(1, model.VariantStandard, 300)
+
+	if _, ok := manager.Take(id, 2); ok {
+		t.Fatal("чужой пользователь не должен подтверждать запись")
+	}
+	if pending, ok := manager.Take(id, 1); !ok || pending.Count != 300 {
+		t.Fatalf("ожидали запись на 300, получили %+v, %v", pending, ok)
+	}
+}
+
+func TestHandleReviewFlags_NotAdmin(t *testing.T) {
+	mockService := new(MockService)
+	mockBot := new(MockBot)
+
+	handler := NewBotHandler(mockBot, mockService)
+
+	mockBot.On("Send", mock.Anything).Return(tgbotapi.Message{}, nil).Once()
+
+	handler.handleReviewFlags(context.Background(), 42, 123)
+
+	mockService.AssertNotCalled(t, "GetPendingFlags", mock.Anything)
+	mockBot.AssertExpectations(t)
+}
+
+func TestHandlePendingInput_ExerciseReps(t *testing.T) {
+	mockBot := new(MockBot)
+	mockService := new(MockService)
+	handler := NewBotHandler(mockBot, mockService)
+
+	ctx := context.Background()
+	chatID := int64(100)
+	userID := int64(1)
+
+	squats := model.Exercise{Code: "squats", Name: "Приседания", Emoji: "🦵", Unit: model.UnitReps}
+
+	handler.inputManager.Set(chatID, PendingInput{
+		InputType:   inputTypeExerciseReps,
+		MessageID:   1,
+		CancelMsgID: 2,
+		Exercise:    "squats",
+	})
+
+	mockService.On("AddExerciseReps", ctx, userID, "squats", 30).
+		Return(&model.AddPushupsViewModel{AddedCount: 30, Total: 30, DailyNorm: 90, Exercise: &squats}, nil).
+		Once()
+	mockBot.On("Send", mock.Anything).Return(tgbotapi.Message{}, nil)
+
+	input, ok := handler.getPendingInput(chatID)
+	assert.True(t, ok)
+
+	handler.handlePendingInput(ctx, input, userID, "alice", chatID, "30")
+
+	_, exists := handler.getPendingInput(chatID)
+	assert.False(t, exists)
+	mockService.AssertExpectations(t)
+}
+
+func TestHandlePendingInput_PushupVariant(t *testing.T) {
+	mockBot := new(MockBot)
+	mockService := new(MockService)
+	handler := NewBotHandler(mockBot, mockService)
+
+	ctx := context.Background()
+	chatID := int64(100)
+	userID := int64(1)
+
+	diamond := model.PushupVariant{Code: "diamond", Name: "💎 Алмазные", Coefficient: 1.5}
+
+	handler.inputManager.Set(chatID, PendingInput{
+		InputType:   inputDayLimit,
+		MessageID:   1,
+		CancelMsgID: 2,
+		Variant:     "diamond",
+	})
+
+	mockService.On("AddPushupSet", ctx, userID, "diamond", 20).
+		Return(&model.AddPushupsViewModel{AddedCount: 20, Total: 30, DailyNorm: 100, Variant: &diamond, Equivalent: 30}, nil).
+		Once()
+	mockBot.On("Send", mock.Anything).Return(tgbotapi.Message{}, nil)
+
+	input, ok := handler.getPendingInput(chatID)
+	assert.True(t, ok)
+
+	handler.handlePendingInput(ctx, input, userID, "alice", chatID, "20")
+
+	mockService.AssertExpectations(t)
+}
+
+func TestSendMaxTestReminders(t *testing.T) {
+	mockService := new(MockService)
+	mockBot := new(MockBot)
+
+	handler := NewBotHandler(mockBot, mockService)
+
+	reminders := []model.MaxTestReminder{
+		{UserID: 7, MaxReps: 25, LastMaxTest: time.Now().AddDate(0, 0, -9)},
+	}
+
+	mockService.On("GetMaxTestReminders", mock.Anything, 7*24*time.Hour).Return(reminders, nil).Once()
+	mockService.On("MarkMaxTestReminded", mock.Anything, int64(7)).Return(nil).Once()
+	mockBot.On("Send", mock.AnythingOfType("tgbotapi.MessageConfig")).Return(tgbotapi.Message{}, nil).Once()
+
+	handler.sendMaxTestReminders(context.Background(), 7*24*time.Hour, time.Now())
+
+	mockService.AssertExpectations(t)
+	mockBot.AssertExpectations(t)
+}
+
+func TestSendMaxTestReminders_MarksBlockedUser(t *testing.T) {
+	mockService := new(MockService)
+	mockBot := new(MockBot)
+
+	handler := NewBotHandler(mockBot, mockService)
+
+	reminders := []model.MaxTestReminder{
+		{UserID: 7, MaxReps: 25, LastMaxTest: time.Now().AddDate(0, 0, -9)},
+	}
+
+	mockService.On("GetMaxTestReminders", mock.Anything, 7*24*time.Hour).Return(reminders, nil).Once()
+	mockService.On("MarkMaxTestReminded", mock.Anything, int64(7)).Return(nil).Once()
+	mockBot.On("Send", mock.AnythingOfType("tgbotapi.MessageConfig")).
+		Return(tgbotapi.Message{}, errors.New("Forbidden: bot was blocked by the user")).Once()
+
+	handler.sendMaxTestReminders(context.Background(), 7*24*time.Hour, time.Now())
+
+	mockService.AssertExpectations(t)
+	mockBot.AssertExpectations(t)
+}
+
+func TestHandleAddPushups_NotifiesAchievements(t *testing.T) {
+	mockService := new(MockService)
+	mockBot := new(MockBot)
+
+	handler := NewBotHandler(mockBot, mockService)
+
+	vm := &model.AddPushupsViewModel{
+		AddedCount: 10,
+		Total:      10,
+		DailyNorm:  50,
+		NewAchievements: []model.Achievement{
+			{Code: "first_set", Emoji: "👣", Name: "Первый шаг"},
+		},
+	}
+
+	mockService.On("AddPushupSet", mock.Anything, int64(1), model.VariantStandard, 10).Return(vm, nil).Once()
+	// Ответ о подходе + уведомление о достижении
+	mockBot.On("Send", mock.AnythingOfType("tgbotapi.MessageConfig")).Return(tgbotapi.Message{}, nil).Twice()
+
+	handler.handleAddPushups(context.Background(), 1, "user", 100, 10)
+
+	mockService.AssertExpectations(t)
+	mockBot.AssertExpectations(t)
+}
+
+func TestHandleSetMaxReps_RankUpAnnouncement(t *testing.T) {
+	mockService := new(MockService)
+	mockBot := new(MockBot)
+
+	handler := NewBotHandler(mockBot, mockService)
+	handler.SetAnnouncementChat(-100500)
+
+	vm := &model.MaxRepsViewModel{
+		Count: 26,
+		Rank:  "⚔️ Рыцарь света",
+		RankChange: &model.RankChange{
+			FromRank: "🚀 Ракета-носитель",
+			ToRank:   "⚔️ Рыцарь света",
+			MaxReps:  26,
+			Promoted: true,
+			Username: "ivan",
+		},
+	}
+
+	mockService.On("UpdateMaxReps", mock.Anything, int64(1), 26).Return(vm, nil).Once()
+	mockBot.On("Send", mock.MatchedBy(func(msg tgbotapi.MessageConfig) bool {
+		return msg.ChatID == 100
+	})).Return(tgbotapi.Message{}, nil).Twice()
+	mockBot.On("Send", mock.MatchedBy(func(msg tgbotapi.MessageConfig) bool {
+		return msg.ChatID == -100500 && strings.Contains(msg.Text, "ivan")
+	})).Return(tgbotapi.Message{}, nil).Once()
+
+	handler.handleSetMaxReps(context.Background(), 1, "ivan", 100, 26)
+
+	mockService.AssertExpectations(t)
+	mockBot.AssertExpectations(t)
+}
+
+func TestHandleNormStrategyCallback(t *testing.T) {
+	mockService := new(MockService)
+	mockBot := new(MockBot)
+
+	handler := NewBotHandler(mockBot, mockService)
+
+	callback := &tgbotapi.CallbackQuery{
+		ID:      "cb",
+		From:    &tgbotapi.User{ID: 1},
+		Data:    "norm_strategy:ramp",
+		Message: &tgbotapi.Message{MessageID: 5, Chat: &tgbotapi.Chat{ID: 100}},
+	}
+
+	mockService.On("SetNormStrategy", mock.Anything, int64(1), "ramp").
+		Return(model.NormStrategyInfo{Code: "ramp", Name: "📈 Недельная прибавка"}, nil).Once()
+	mockBot.On("Request", mock.Anything).Return(&tgbotapi.APIResponse{Ok: true}, nil).Once()
+	mockBot.On("Send", mock.AnythingOfType("tgbotapi.EditMessageTextConfig")).Return(tgbotapi.Message{}, nil).Once()
+
+	handler.handleNormStrategyCallback(context.Background(), callback)
+
+	mockService.AssertExpectations(t)
+	mockBot.AssertExpectations(t)
+}
+
+func TestHandleAutoNormCallback(t *testing.T) {
+	mockService := new(MockService)
+	mockBot := new(MockBot)
+
+	handler := NewBotHandler(mockBot, mockService)
+
+	callback := &tgbotapi.CallbackQuery{
+		ID:      "cb",
+		From:    &tgbotapi.User{ID: 1},
+		Data:    "auto_norm:on",
+		Message: &tgbotapi.Message{MessageID: 5, Chat: &tgbotapi.Chat{ID: 100}},
+	}
+
+	mockService.On("SetAutoNorm", mock.Anything, int64(1), true).Return(nil).Once()
+	mockBot.On("Request", mock.Anything).Return(&tgbotapi.APIResponse{Ok: true}, nil).Once()
+	mockBot.On("Send", mock.AnythingOfType("tgbotapi.EditMessageTextConfig")).Return(tgbotapi.Message{}, nil).Once()
+
+	handler.handleAutoNormCallback(context.Background(), callback)
+
+	mockService.AssertExpectations(t)
+	mockBot.AssertExpectations(t)
+}
+
+func TestAdjustAutoNorms_NotifiesUsers(t *testing.T) {
+	mockService := new(MockService)
+	mockBot := new(MockBot)
+
+	handler := NewBotHandler(mockBot, mockService)
+	cfg := config.AutoNormConfig{Enabled: true, WindowDays: 7, RaiseRate: 0.85, LowerRate: 0.3, StepRatio: 0.1}
+
+	mockService.On("RunAutoNorm", mock.Anything, cfg).Return([]model.NormAdjustment{
+		{UserID: 42, OldNorm: 100, NewNorm: 110, Reason: "норма выполнена в 7 из 7 последних дней"},
+	}, nil).Once()
+	mockBot.On("Send", mock.MatchedBy(func(msg tgbotapi.MessageConfig) bool {
+		return msg.ChatID == 42 && strings.Contains(msg.Text, "100 → 110")
+	})).Return(tgbotapi.Message{}, nil).Once()
+
+	handler.adjustAutoNorms(context.Background(), cfg)
+
+	mockService.AssertExpectations(t)
+	mockBot.AssertExpectations(t)
+}
+
+func TestHandleRestCallback(t *testing.T) {
+	mockService := new(MockService)
+	mockBot := new(MockBot)
+
+	handler := NewBotHandler(mockBot, mockService)
+
+	callback := &tgbotapi.CallbackQuery{
+		ID:      "cb",
+		From:    &tgbotapi.User{ID: 1},
+		Data:    "rest:vacation:7",
+		Message: &tgbotapi.Message{MessageID: 5, Chat: &tgbotapi.Chat{ID: 100}},
+	}
+
+	start := time.Date(2026, 3, 16, 0, 0, 0, 0, time.UTC)
+	mockService.On("StartRest", mock.Anything, int64(1), model.RestKindVacation, 7).
+		Return(model.RestPeriod{Kind: model.RestKindVacation, StartDate: start, EndDate: start.AddDate(0, 0, 6)}, nil).Once()
+	mockBot.On("Request", mock.Anything).Return(&tgbotapi.APIResponse{Ok: true}, nil).Once()
+	mockBot.On("Send", mock.MatchedBy(func(msg tgbotapi.EditMessageTextConfig) bool {
+		return strings.Contains(msg.Text, "16.03 — 22.03")
+	})).Return(tgbotapi.Message{}, nil).Once()
+
+	handler.handleRestCallback(context.Background(), callback)
+
+	mockService.AssertExpectations(t)
+	mockBot.AssertExpectations(t)
+}
+
+func TestHandleProgramCallback_RepeatWeek(t *testing.T) {
+	mockService := new(MockService)
+	mockBot := new(MockBot)
+
+	handler := NewBotHandler(mockBot, mockService)
+
+	callback := &tgbotapi.CallbackQuery{
+		ID:      "cb",
+		From:    &tgbotapi.User{ID: 1},
+		Data:    "program:fail:2:3",
+		Message: &tgbotapi.Message{MessageID: 5, Chat: &tgbotapi.Chat{ID: 100}},
+	}
+
+	mockService.On("CompleteProgramSession", mock.Anything, int64(1), 2, 3, false).Return(&model.ProgramSessionResult{
+		ProgramName: "💯 100 отжиманий за 6 недель",
+		Outcome:     model.ProgramOutcomeRepeatWeek,
+		Next:        &model.ProgramWorkout{Week: 2, Day: 1, TotalWeeks: 6},
+	}, nil).Once()
+	mockBot.On("Request", mock.Anything).Return(&tgbotapi.APIResponse{Ok: true}, nil).Once()
+	mockBot.On("Send", mock.MatchedBy(func(msg tgbotapi.EditMessageTextConfig) bool {
+		return strings.Contains(msg.Text, "повторим неделю 2")
+	})).Return(tgbotapi.Message{}, nil).Once()
+
+	handler.handleProgramCallback(context.Background(), callback)
+
+	mockService.AssertExpectations(t)
+	mockBot.AssertExpectations(t)
+}
+
+func TestHandleProgramCallback_Outdated(t *testing.T) {
+	mockService := new(MockService)
+	mockBot := new(MockBot)
+
+	handler := NewBotHandler(mockBot, mockService)
+
+	callback := &tgbotapi.CallbackQuery{
+		ID:      "cb",
+		From:    &tgbotapi.User{ID: 1},
+		Data:    "program:done:1:2",
+		Message: &tgbotapi.Message{MessageID: 5, Chat: &tgbotapi.Chat{ID: 100}},
+	}
+
+	mockService.On("CompleteProgramSession", mock.Anything, int64(1), 1, 2, true).
+		Return(nil, model.ErrProgramSessionOutdated).Once()
+	mockBot.On("Request", mock.Anything).Return(&tgbotapi.APIResponse{Ok: true}, nil).Once()
+	mockBot.On("Send", mock.MatchedBy(func(msg tgbotapi.EditMessageTextConfig) bool {
+		return strings.Contains(msg.Text, "уже отмечена")
+	})).Return(tgbotapi.Message{}, nil).Once()
+
+	handler.handleProgramCallback(context.Background(), callback)
+
+	mockService.AssertExpectations(t)
+	mockBot.AssertExpectations(t)
+}
+
+func TestHandleGTGCallback_LogsSet(t *testing.T) {
+	mockService := new(MockService)
+	mockBot := new(MockBot)
+
+	handler := NewBotHandler(mockBot, mockService)
+	promptID := handler.gtgPrompts.Add(1, model.VariantStandard, 8)
+
+	callback := &tgbotapi.CallbackQuery{
+		ID:      "cb",
+		From:    &tgbotapi.User{ID: 1},
+		Data:    "gtg:done:" + promptID,
+		Message: &tgbotapi.Message{MessageID: 5, Chat: &tgbotapi.Chat{ID: 100}},
+	}
+
+	mockService.On("AddGTGSet", mock.Anything, int64(1), 8).
+		Return(&model.AddPushupsViewModel{AddedCount: 8, Total: 40, DailyNorm: 100}, nil).Once()
+	mockBot.On("Request", mock.Anything).Return(&tgbotapi.APIResponse{Ok: true}, nil).Twice()
+	mockBot.On("Send", mock.MatchedBy(func(msg tgbotapi.EditMessageTextConfig) bool {
+		return strings.Contains(msg.Text, "Микроподход на 8 записан")
+	})).Return(tgbotapi.Message{}, nil).Once()
+	mockBot.On("Send", mock.MatchedBy(func(msg tgbotapi.EditMessageTextConfig) bool {
+		return strings.Contains(msg.Text, "уже записан")
+	})).Return(tgbotapi.Message{}, nil).Once()
+	mockBot.On("Send", mock.AnythingOfType("tgbotapi.MessageConfig")).Return(tgbotapi.Message{}, nil).Once()
+
+	// Второе нажатие той же кнопки не записывает подход повторно
+	handler.handleCallback(tgbotapi.Update{CallbackQuery: callback})
+	handler.handleCallback(tgbotapi.Update{CallbackQuery: callback})
+
+	mockService.AssertExpectations(t)
+	mockBot.AssertExpectations(t)
+}
+
+func TestSendGTGPrompts(t *testing.T) {
+	mockService := new(MockService)
+	mockBot := new(MockBot)
+
+	handler := NewBotHandler(mockBot, mockService)
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.Local)
+	prompt := model.GTGPrompt{UserID: 7, MaxReps: 20, SetSize: 10}
+	var callbackData string
+
+	mockService.On("GetDueGTGPrompts", mock.Anything, now).Return([]model.GTGPrompt{prompt}, nil).Once()
+	mockBot.On("Send", mock.MatchedBy(func(msg tgbotapi.MessageConfig) bool {
+		markup, ok := msg.ReplyMarkup.(tgbotapi.InlineKeyboardMarkup)
+		if msg.ChatID != 7 || !ok {
+			return false
+		}
+		callbackData = *markup.InlineKeyboard[0][0].CallbackData
+		return strings.HasPrefix(callbackData, "gtg:done:")
+	})).Return(tgbotapi.Message{}, nil).Once()
+	mockService.On("MarkGTGPrompted", mock.Anything, prompt, now).Return(nil).Once()
+
+	handler.sendGTGPrompts(context.Background(), now)
+
+	// В кнопке — одноразовый ID напоминания с размером подхода
+	pending, ok := handler.gtgPrompts.Take(strings.TrimPrefix(callbackData, "gtg:done:"), 7)
+	assert.True(t, ok)
+	assert.Equal(t, 10, pending.Count)
+
+	mockService.AssertExpectations(t)
+	mockBot.AssertExpectations(t)
+}
+
+func TestRestTimerManager_ReplacesAndStops(t *testing.T) {
+	manager := NewRestTimerManager()
+
+	first := make(chan struct{})
+	manager.Start(100, 1, func(ctx context.Context) {
+		<-ctx.Done()
+		close(first)
+	})
+	manager.Start(100, 2, func(ctx context.Context) { <-ctx.Done() })
+	manager.Start(200, 3, func(ctx context.Context) { <-ctx.Done() })
+
+	// Новый таймер в том же чате отменяет старый
+	select {
+	case <-first:
+	case <-time.After(time.Second):
+		t.Fatal("первый таймер не отменён")
+	}
+
+	// Кнопка со старого отсчёта не останавливает новый таймер
+	assert.False(t, manager.Cancel(100, 1))
+	assert.True(t, manager.Cancel(200, 3))
+	assert.False(t, manager.Cancel(200, 3))
+	assert.Equal(t, 1, manager.Active())
+
+	manager.Stop()
+	assert.Equal(t, 0, manager.Active())
+	assert.False(t, manager.Start(300, 4, func(ctx context.Context) {}))
+}
+
+func TestRunRestCountdown_PingsAtEnd(t *testing.T) {
+	mockBot := new(MockBot)
+	handler := NewBotHandler(mockBot, new(MockService))
+
+	mockBot.On("Send", mock.AnythingOfType("tgbotapi.EditMessageTextConfig")).Return(tgbotapi.Message{}, nil)
+	mockBot.On("Send", mock.MatchedBy(func(msg tgbotapi.MessageConfig) bool {
+		return msg.ChatID == 100 && strings.Contains(msg.Text, "Отдых окончен")
+	})).Return(tgbotapi.Message{}, nil).Once()
+
+	handler.runRestCountdown(context.Background(), 100, 5, time.Now().Add(50*time.Millisecond), 10*time.Millisecond)
+
+	mockBot.AssertExpectations(t)
+}
+
+func TestHandleRestTimerCallback_Stop(t *testing.T) {
+	mockBot := new(MockBot)
+	handler := NewBotHandler(mockBot, new(MockService))
+
+	stopped := make(chan struct{})
+	handler.restTimers.Start(100, 5, func(ctx context.Context) {
+		<-ctx.Done()
+		close(stopped)
+	})
+
+	callback := &tgbotapi.CallbackQuery{
+		ID:      "cb",
+		From:    &tgbotapi.User{ID: 1},
+		Data:    "rest_timer:stop",
+		Message: &tgbotapi.Message{MessageID: 5, Chat: &tgbotapi.Chat{ID: 100}},
+	}
+
+	mockBot.On("Request", mock.Anything).Return(&tgbotapi.APIResponse{Ok: true}, nil).Once()
+	mockBot.On("Send", mock.AnythingOfType("tgbotapi.EditMessageTextConfig")).Return(tgbotapi.Message{}, nil).Once()
+
+	handler.handleCallback(tgbotapi.Update{CallbackQuery: callback})
+
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("таймер не остановлен")
+	}
+	mockBot.AssertExpectations(t)
+}
+
+func TestHandleFinishWorkout_ShowsSummary(t *testing.T) {
+	mockService := new(MockService)
+	mockBot := new(MockBot)
+
+	handler := NewBotHandler(mockBot, mockService)
+	started := time.Date(2026, 3, 10, 18, 0, 0, 0, time.Local)
+
+	mockService.On("FinishWorkout", mock.Anything, int64(1)).Return(&model.WorkoutSummary{
+		Session:  model.WorkoutSession{StartedAt: started, Sets: 4, Total: 60, BestSet: 20},
+		Duration: 25 * time.Minute,
+		Average:  15,
+		Previous: &model.WorkoutSession{StartedAt: started.AddDate(0, 0, -2), Sets: 4, Total: 50, BestSet: 18},
+	}, nil).Once()
+	mockBot.On("Send", mock.MatchedBy(func(msg tgbotapi.MessageConfig) bool {
+		return msg.ParseMode == tgbotapi.ModeHTML &&
+			strings.Contains(msg.Text, "25 мин") &&
+			strings.Contains(msg.Text, "объём +10") &&
+			strings.Contains(msg.Text, "лучший подход +2")
+	})).Return(tgbotapi.Message{}, nil).Once()
+
+	handler.handleFinishWorkout(context.Background(), 1, 1)
+
+	mockService.AssertExpectations(t)
+	mockBot.AssertExpectations(t)
+}
+
+func TestHandleMaxTestCallback_DeviationNeedsConfirmation(t *testing.T) {
+	mockService := new(MockService)
+	mockBot := new(MockBot)
+
+	handler := NewBotHandler(mockBot, mockService)
+
+	callback := &tgbotapi.CallbackQuery{
+		ID:      "cb",
+		From:    &tgbotapi.User{ID: 1},
+		Data:    "max_test:rpe:12:8",
+		Message: &tgbotapi.Message{MessageID: 5, Chat: &tgbotapi.Chat{ID: 100}},
+	}
+
+	mockService.On("CheckMaxTestResult", mock.Anything, int64(1), 12).
+		Return(model.MaxTestCheck{Count: 12, Previous: 30, Deviates: true}, nil).Once()
+	mockBot.On("Request", mock.Anything).Return(&tgbotapi.APIResponse{Ok: true}, nil).Once()
+	mockBot.On("Send", mock.AnythingOfType("tgbotapi.EditMessageTextConfig")).Return(tgbotapi.Message{}, nil).Once()
+	mockBot.On("Send", mock.MatchedBy(func(msg tgbotapi.MessageConfig) bool {
+		markup, ok := msg.ReplyMarkup.(tgbotapi.InlineKeyboardMarkup)
+		return ok && strings.Contains(msg.Text, "на 18 меньше") &&
+			*markup.InlineKeyboard[0][0].CallbackData == "max_test:save:12:8"
+	})).Return(tgbotapi.Message{}, nil).Once()
+
+	handler.handleCallback(tgbotapi.Update{CallbackQuery: callback})
+
+	mockService.AssertExpectations(t)
+	mockService.AssertNotCalled(t, "RecordMaxTest", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	mockBot.AssertExpectations(t)
+}
+
+func TestHandleMaxTestCallback_SavesWithRPE(t *testing.T) {
+	mockService := new(MockService)
+	mockBot := new(MockBot)
+
+	handler := NewBotHandler(mockBot, mockService)
+
+	callback := &tgbotapi.CallbackQuery{
+		ID:      "cb",
+		From:    &tgbotapi.User{ID: 1},
+		Data:    "max_test:rpe:32:9",
+		Message: &tgbotapi.Message{MessageID: 5, Chat: &tgbotapi.Chat{ID: 100}},
+	}
+
+	mockService.On("CheckMaxTestResult", mock.Anything, int64(1), 32).
+		Return(model.MaxTestCheck{Count: 32, Previous: 30}, nil).Once()
+	mockService.On("RecordMaxTest", mock.Anything, int64(1), 32, 9).
+		Return(&model.MaxRepsViewModel{Count: 32, DailyNorm: 90, Rank: "🏹 Адепт упорства", RPE: 9}, nil).Once()
+	mockBot.On("Request", mock.Anything).Return(&tgbotapi.APIResponse{Ok: true}, nil).Once()
+	mockBot.On("Send", mock.AnythingOfType("tgbotapi.EditMessageTextConfig")).Return(tgbotapi.Message{}, nil).Once()
+	mockBot.On("Send", mock.MatchedBy(func(msg tgbotapi.MessageConfig) bool {
+		return strings.Contains(msg.Text, "RPE 9")
+	})).Return(tgbotapi.Message{}, nil).Once()
+
+	handler.handleCallback(tgbotapi.Update{CallbackQuery: callback})
+
+	mockService.AssertExpectations(t)
+	mockBot.AssertExpectations(t)
+}
+
+func TestHandleMaxRepsHistoryCallback_DeleteConfirm(t *testing.T) {
+	mockService := new(MockService)
+	mockBot := new(MockBot)
+
+	handler := NewBotHandler(mockBot, mockService)
+
+	callback := &tgbotapi.CallbackQuery{
+		ID:      "cb",
+		From:    &tgbotapi.User{ID: 1},
+		Data:    "history:delete_confirm:42",
+		Message: &tgbotapi.Message{MessageID: 5, Chat: &tgbotapi.Chat{ID: 100}},
+	}
+
+	mockService.On("DeleteMaxRepsEntry", mock.Anything, int64(1), int64(42)).
+		Return(&model.MaxRepsHistoryChange{PrevMaxReps: 80, MaxReps: 30, Rank: "🐜 Трудяга", LatestChanged: true}, nil).Once()
+	mockBot.On("Request", mock.Anything).Return(&tgbotapi.APIResponse{Ok: true}, nil).Once()
+	mockBot.On("Send", mock.AnythingOfType("tgbotapi.EditMessageTextConfig")).Return(tgbotapi.Message{}, nil).Once()
+	mockBot.On("Send", mock.MatchedBy(func(msg tgbotapi.MessageConfig) bool {
+		markup, ok := msg.ReplyMarkup.(tgbotapi.InlineKeyboardMarkup)
+		return strings.Contains(msg.Text, "Текущий максимум: 30") &&
+			ok && markup.InlineKeyboard[0][0].CallbackData != nil &&
+			*markup.InlineKeyboard[0][0].CallbackData == "history:norm"
+	})).Return(tgbotapi.Message{}, nil).Once()
+
+	handler.handleCallback(tgbotapi.Update{CallbackQuery: callback})
+
+	mockService.AssertExpectations(t)
+	mockBot.AssertExpectations(t)
+}
+
+func TestHandleMaxRepsHistoryCallback_EditRequestsNumber(t *testing.T) {
+	mockService := new(MockService)
+	mockBot := new(MockBot)
+
+	handler := NewBotHandler(mockBot, mockService)
+
+	callback := &tgbotapi.CallbackQuery{
+		ID:      "cb",
+		From:    &tgbotapi.User{ID: 1},
+		Data:    "history:edit:42",
+		Message: &tgbotapi.Message{MessageID: 5, Chat: &tgbotapi.Chat{ID: 100}},
+	}
+
+	mockBot.On("Request", mock.Anything).Return(&tgbotapi.APIResponse{Ok: true}, nil).Once()
+	mockBot.On("Send", mock.Anything).Return(tgbotapi.Message{MessageID: 7}, nil)
+
+	handler.handleCallback(tgbotapi.Update{CallbackQuery: callback})
+
+	input, ok := handler.inputManager.Get(100)
+	assert.True(t, ok)
+	assert.Equal(t, inputTypeHistoryEdit, input.InputType)
+	assert.Equal(t, int64(42), input.RecordID)
+}
+
+func TestHandleMaxRepsHistoryEdit_KeepsRecordAfterInvalidInput(t *testing.T) {
+	mockService := new(MockService)
+	mockBot := new(MockBot)
+
+	handler := NewBotHandler(mockBot, mockService)
+
+	callback := &tgbotapi.CallbackQuery{
+		ID:      "cb",
+		From:    &tgbotapi.User{ID: 1},
+		Data:    "history:edit:42",
+		Message: &tgbotapi.Message{MessageID: 5, Chat: &tgbotapi.Chat{ID: 100}},
+	}
+	message := func(text string) tgbotapi.Update {
+		return tgbotapi.Update{Message: &tgbotapi.Message{
+			From: &tgbotapi.User{ID: 1},
+			Chat: &tgbotapi.Chat{ID: 100},
+			Text: text,
+		}}
+	}
+
+	mockBot.On("Request", mock.Anything).Return(&tgbotapi.APIResponse{Ok: true}, nil)
+	mockBot.On("Send", mock.Anything).Return(tgbotapi.Message{MessageID: 7}, nil)
+	mockService.On("EditMaxRepsEntry", mock.Anything, int64(1), int64(42), 35).
+		Return(&model.MaxRepsHistoryChange{PrevMaxReps: 30, MaxReps: 35}, nil).Once()
+
+	handler.handleCallback(tgbotapi.Update{CallbackQuery: callback})
+	handler.HandleUpdate(message("abc"))
+	handler.HandleUpdate(message("100000"))
+
+	input, ok := handler.inputManager.Get(100)
+	assert.True(t, ok)
+	assert.Equal(t, int64(42), input.RecordID)
+
+	handler.HandleUpdate(message("35"))
+
+	_, ok = handler.inputManager.Get(100)
+	assert.False(t, ok)
+	mockService.AssertExpectations(t)
+}
+
+func TestHandleMaxRepsHistoryCallback_Page(t *testing.T) {
+	mockService := new(MockService)
+	mockBot := new(MockBot)
+
+	handler := NewBotHandler(mockBot, mockService)
+
+	callback := &tgbotapi.CallbackQuery{
+		ID:      "cb",
+		From:    &tgbotapi.User{ID: 1},
+		Data:    "history:page:year:1",
+		Message: &tgbotapi.Message{MessageID: 5, Chat: &tgbotapi.Chat{ID: 100}},
+	}
+
+	page := &model.MaxRepsHistoryPage{
+		Items:  []model.MaxRepsHistoryItem{{ID: 3, Date: time.Date(2026, 2, 1, 0, 0, 0, 0, time.Local), MaxReps: 25}},
+		Range:  model.HistoryRangeYear,
+		Page:   1,
+		Pages:  2,
+		Offset: 8,
+		Total:  9,
+		First:  25,
+		Last:   40,
+	}
+
+	mockService.On("GetMaxRepsHistoryPage", mock.Anything, int64(1), model.HistoryRangeYear, 1).Return(page, nil).Once()
+	mockBot.On("Request", mock.Anything).Return(&tgbotapi.APIResponse{Ok: true}, nil).Once()
+	mockBot.On("Send", mock.MatchedBy(func(edit tgbotapi.EditMessageTextConfig) bool {
+		nav := edit.ReplyMarkup.InlineKeyboard[1]
+		return edit.MessageID == 5 &&
+			strings.Contains(edit.Text, "1. 01.02.2026 → 25") &&
+			strings.Contains(edit.Text, "Страница 2 из 2") &&
+			*nav[0].CallbackData == "history:page:year:0"
+	})).Return(tgbotapi.Message{}, nil).Once()
+
+	handler.handleCallback(tgbotapi.Update{CallbackQuery: callback})
+
+	mockService.AssertExpectations(t)
+	mockBot.AssertExpectations(t)
+}
+
+func TestHandleMaxRepsHistoryCallback_ChartWithNorm(t *testing.T) {
+	mockService := new(MockService)
+	mockBot := new(MockBot)
+
+	handler := NewBotHandler(mockBot, mockService)
+
+	callback := &tgbotapi.CallbackQuery{
+		ID:      "cb",
+		From:    &tgbotapi.User{ID: 1},
+		Data:    "history:chart_norm",
+		Message: &tgbotapi.Message{MessageID: 5, Chat: &tgbotapi.Chat{ID: 100}},
+	}
+
+	history := []model.MaxRepsHistoryItem{{MaxReps: 30}, {MaxReps: 25}}
+
+	mockService.On("GetMaxRepsHistory", mock.Anything, int64(1)).Return(history, nil).Once()
+	mockService.On("BuildSchedule", mock.Anything, int64(1), history, true).
+		Return(*bytes.NewBufferString("png"), nil).Once()
+	mockBot.On("Request", mock.Anything).Return(&tgbotapi.APIResponse{Ok: true}, nil).Once()
+	mockBot.On("Send", mock.MatchedBy(func(photo tgbotapi.PhotoConfig) bool {
+		return photo.ChatID == 100 && photo.ReplyMarkup == nil
+	})).Return(tgbotapi.Message{}, nil).Once()
+
+	handler.handleCallback(tgbotapi.Update{CallbackQuery: callback})
+
+	mockService.AssertExpectations(t)
+	mockBot.AssertExpectations(t)
+}
+
+func TestHandleVolumeChartCallback(t *testing.T) {
+	mockService := new(MockService)
+	mockBot := new(MockBot)
+
+	handler := NewBotHandler(mockBot, mockService)
+
+	callback := &tgbotapi.CallbackQuery{
+		ID:      "cb",
+		From:    &tgbotapi.User{ID: 1},
+		Data:    "volume:30",
+		Message: &tgbotapi.Message{MessageID: 5, Chat: &tgbotapi.Chat{ID: 100}},
+	}
+
+	mockService.On("BuildVolumeChart", mock.Anything, int64(1), 30).
+		Return(*bytes.NewBufferString("png"), nil).Once()
+	mockBot.On("Request", mock.Anything).Return(&tgbotapi.APIResponse{Ok: true}, nil).Once()
+	mockBot.On("Send", mock.MatchedBy(func(photo tgbotapi.PhotoConfig) bool {
+		return photo.ChatID == 100 && strings.Contains(photo.Caption, "30 дн.")
+	})).Return(tgbotapi.Message{}, nil).Once()
+
+	handler.handleCallback(tgbotapi.Update{CallbackQuery: callback})
+
+	mockService.AssertExpectations(t)
+	mockBot.AssertExpectations(t)
+}
+
+func TestHandleHeatmapCallback(t *testing.T) {
+	mockService := new(MockService)
+	mockBot := new(MockBot)
+
+	handler := NewBotHandler(mockBot, mockService)
+
+	callback := &tgbotapi.CallbackQuery{
+		ID:      "cb",
+		From:    &tgbotapi.User{ID: 1},
+		Data:    "heatmap",
+		Message: &tgbotapi.Message{MessageID: 5, Chat: &tgbotapi.Chat{ID: 100}},
+	}
+
+	mockService.On("BuildHeatmap", mock.Anything, int64(1)).Return(*bytes.NewBufferString("png"), nil).Once()
+	mockBot.On("Request", mock.Anything).Return(&tgbotapi.APIResponse{Ok: true}, nil).Once()
+	mockBot.On("Send", mock.MatchedBy(func(photo tgbotapi.PhotoConfig) bool {
+		return photo.ChatID == 100 && strings.Contains(photo.Caption, "Календарь активности")
+	})).Return(tgbotapi.Message{}, nil).Once()
+
+	handler.handleCallback(tgbotapi.Update{CallbackQuery: callback})
+
+	mockService.AssertExpectations(t)
+	mockBot.AssertExpectations(t)
+}
+
+func TestHandleStatsCardCallback(t *testing.T) {
+	mockService := new(MockService)
+	mockBot := new(MockBot)
+
+	handler := NewBotHandler(mockBot, mockService)
+
+	callback := &tgbotapi.CallbackQuery{
+		ID:      "cb",
+		From:    &tgbotapi.User{ID: 1},
+		Data:    "share_card",
+		Message: &tgbotapi.Message{MessageID: 5, Chat: &tgbotapi.Chat{ID: 100}},
+	}
+
+	mockService.On("BuildStatsCard", mock.Anything, int64(1)).Return(&model.StatsCard{}, *bytes.NewBufferString("png"), nil).Once()
+	mockBot.On("Request", mock.Anything).Return(&tgbotapi.APIResponse{Ok: true}, nil).Once()
+	mockBot.On("Send", mock.MatchedBy(func(photo tgbotapi.PhotoConfig) bool {
+		return photo.ChatID == 100 && strings.Contains(photo.Caption, "перешлите")
+	})).Return(tgbotapi.Message{}, nil).Once()
+
+	handler.handleCallback(tgbotapi.Update{CallbackQuery: callback})
+
+	mockService.AssertExpectations(t)
+	mockBot.AssertExpectations(t)
+}
+
+func TestHandleInlineQuery_PersonalResultsAreCached(t *testing.T) {
+	mockService := new(MockService)
+	mockBot := new(MockBot)
+
+	handler := NewBotHandler(mockBot, mockService)
+
+	stat := &model.FullStatViewModel{TodayTotal: 60, DailyNorm: 100, Leaderboard: []model.LeaderboardItem{{Username: "ivan", Count: 150}}}
+	card := &model.StatsCard{Username: "ivan", Rank: "Трудяга", MaxReps: 12, TotalAllTime: 900, Streak: 3}
+
+	mockService.On("GetFullStat", mock.Anything, int64(1)).Return(stat, nil).Once()
+	mockService.On("GetStatsCard", mock.Anything, int64(1)).Return(card, nil).Once()
+	mockBot.On("Request", mock.MatchedBy(func(answer tgbotapi.InlineConfig) bool {
+		return answer.IsPersonal &&
+			answer.CacheTime == inlinePersonalCacheTime &&
+			len(answer.Results) == 3 &&
+			answer.SwitchPMParameter == inlineCardStartParameter
+	})).Return(&tgbotapi.APIResponse{Ok: true}, nil).Twice()
+
+	query := &tgbotapi.InlineQuery{ID: "q", From: &tgbotapi.User{ID: 1, UserName: "ivan"}}
+	handler.HandleUpdate(tgbotapi.Update{InlineQuery: query})
+	handler.HandleUpdate(tgbotapi.Update{InlineQuery: query})
+
+	mockService.AssertExpectations(t)
+	mockBot.AssertExpectations(t)
+}
+
+func TestHandleInlineQuery_LeaderboardIsShared(t *testing.T) {
+	mockService := new(MockService)
+	mockBot := new(MockBot)
+
+	handler := NewBotHandler(mockBot, mockService)
+
+	mockService.On("GetFullStat", mock.Anything, int64(1)).
+		Return(&model.FullStatViewModel{Leaderboard: []model.LeaderboardItem{{Username: "ivan", Count: 150}}}, nil).Once()
+	mockService.On("GetStatsCard", mock.Anything, int64(1)).Return(&model.StatsCard{}, nil).Once()
+	mockBot.On("Request", mock.MatchedBy(func(answer tgbotapi.InlineConfig) bool {
+		if answer.IsPersonal || len(answer.Results) != 1 {
+			return false
+		}
+		article, ok := answer.Results[0].(tgbotapi.InlineQueryResultArticle)
+		return ok && article.ID == "leaderboard"
+	})).Return(&tgbotapi.APIResponse{Ok: true}, nil).Once()
+
+	handler.HandleUpdate(tgbotapi.Update{InlineQuery: &tgbotapi.InlineQuery{
+		ID:    "q",
+		From:  &tgbotapi.User{ID: 1},
+		Query: "Рейтинг",
+	}})
+
+	mockService.AssertExpectations(t)
+	mockBot.AssertExpectations(t)
+}
+
+func TestHandleInlineQuery_CachedCard(t *testing.T) {
+	mockService := new(MockService)
+	mockBot := new(MockBot)
+
+	handler := NewBotHandler(mockBot, mockService)
+	handler.inlineCache.SetCard(1, "file-id", &model.StatsCard{Username: "ivan", MaxReps: 20})
+
+	mockService.On("GetFullStat", mock.Anything, int64(1)).Return(&model.FullStatViewModel{}, nil).Once()
+	mockService.On("GetStatsCard", mock.Anything, int64(1)).Return(&model.StatsCard{Username: "ivan", MaxReps: 20}, nil).Once()
+	mockBot.On("Request", mock.MatchedBy(func(answer tgbotapi.InlineConfig) bool {
+		if !answer.IsPersonal || len(answer.Results) != 1 || answer.SwitchPMText != "" {
+			return false
+		}
+		photo, ok := answer.Results[0].(tgbotapi.InlineQueryResultCachedPhoto)
+		return ok && photo.PhotoID == "file-id"
+	})).Return(&tgbotapi.APIResponse{Ok: true}, nil).Once()
+
+	handler.HandleUpdate(tgbotapi.Update{InlineQuery: &tgbotapi.InlineQuery{
+		ID:    "q",
+		From:  &tgbotapi.User{ID: 1},
+		Query: "my card",
+	}})
+
+	mockService.AssertExpectations(t)
+	mockBot.AssertExpectations(t)
+}
+
+func TestHandleInlineQuery_OutdatedCard(t *testing.T) {
+	mockService := new(MockService)
+	mockBot := new(MockBot)
+
+	handler := NewBotHandler(mockBot, mockService)
+	handler.inlineCache.SetCard(1, "file-id", &model.StatsCard{Username: "ivan", MaxReps: 20})
+
+	// После карточки вырос максимум — старую картинку отправлять нельзя
+	mockService.On("GetFullStat", mock.Anything, int64(1)).Return(&model.FullStatViewModel{}, nil).Once()
+	mockService.On("GetStatsCard", mock.Anything, int64(1)).Return(&model.StatsCard{Username: "ivan", MaxReps: 25}, nil).Once()
+	mockBot.On("Request", mock.MatchedBy(func(answer tgbotapi.InlineConfig) bool {
+		return len(answer.Results) == 0 && answer.SwitchPMParameter == inlineCardStartParameter
+	})).Return(&tgbotapi.APIResponse{Ok: true}, nil).Once()
+
+	handler.HandleUpdate(tgbotapi.Update{InlineQuery: &tgbotapi.InlineQuery{
+		ID:    "q",
+		From:  &tgbotapi.User{ID: 1},
+		Query: "my card",
+	}})
+
+	_, ok := handler.inlineCache.Card(1, &model.StatsCard{Username: "ivan", MaxReps: 20})
+	assert.False(t, ok)
+	mockService.AssertExpectations(t)
+	mockBot.AssertExpectations(t)
+}
+
+func TestHandleStartCard_NewUser(t *testing.T) {
+	mockService := new(MockService)
+	mockBot := new(MockBot)
+
+	handler := NewBotHandler(mockBot, mockService)
+
+	mockService.On("EnsureUser", mock.Anything, int64(1), "ivan").Return(nil)
+	mockService.On("GetUserMaxReps", mock.Anything, int64(1)).Return(0, nil)
+	mockBot.On("Send", mock.Anything).Return(tgbotapi.Message{MessageID: 1}, nil)
+
+	handler.handleStartCard(context.Background(), 123, 1, "ivan")
+
+	mockService.AssertNotCalled(t, "BuildStatsCard", mock.Anything, int64(1))
+	input, ok := handler.getPendingInput(123)
+	assert.True(t, ok)
+	assert.Equal(t, inputTypeMaxReps, input.InputType)
+	mockService.AssertExpectations(t)
+	mockBot.AssertExpectations(t)
+}
+
+func TestHandleStartCard_ExistingUser(t *testing.T) {
+	mockService := new(MockService)
+	mockBot := new(MockBot)
+
+	handler := NewBotHandler(mockBot, mockService)
+
+	mockService.On("EnsureUser", mock.Anything, int64(1), "ivan").Return(nil).Once()
+	mockService.On("GetUserMaxReps", mock.Anything, int64(1)).Return(20, nil).Once()
+	mockService.On("BuildStatsCard", mock.Anything, int64(1)).Return(&model.StatsCard{}, *bytes.NewBufferString("png"), nil).Once()
+	mockBot.On("Send", mock.Anything).Return(tgbotapi.Message{}, nil).Once()
+
+	handler.handleStartCard(context.Background(), 123, 1, "ivan")
+
+	mockService.AssertExpectations(t)
+	mockBot.AssertExpectations(t)
+}
+
+func TestInlineCache_SnapshotExpires(t *testing.T) {
+	cache := NewInlineCache()
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	cache.now = func() time.Time { return now }
+
+	cache.Store(1, &model.FullStatViewModel{TodayTotal: 10}, &model.StatsCard{})
+
+	stat, _, ok := cache.Snapshot(1)
+	assert.True(t, ok)
+	assert.Equal(t, 10, stat.TodayTotal)
+
+	now = now.Add(inlineCacheTTL + time.Second)
+	_, _, ok = cache.Snapshot(1)
+	assert.False(t, ok)
+}
+
+func TestInlineCache_EvictsExpired(t *testing.T) {
+	cache := NewInlineCache()
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	cache.now = func() time.Time { return now }
+
+	cache.Store(1, &model.FullStatViewModel{}, &model.StatsCard{})
+	cache.SetCard(1, "file-id", &model.StatsCard{})
+
+	now = now.Add(inlineCardTTL + time.Second)
+	cache.Store(2, &model.FullStatViewModel{}, &model.StatsCard{})
+
+	assert.Len(t, cache.snapshots, 1)
+	assert.Empty(t, cache.cards)
+}
@@ -0,0 +1,34 @@
+package hendler
+
+import (
+	"context"
+	"log"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// handleHeatmap присылает календарь активности за год
+func (h *BotHandler) handleHeatmap(ctx context.Context, userID int64, chatID int64) {
+	image, err := h.service.BuildHeatmap(ctx, userID)
+	if err != nil {
+		log.Printf("BuildHeatmap error: %v", err)
+		h.sendError(chatID)
+		return
+	}
+
+	photo := tgbotapi.NewPhoto(chatID, tgbotapi.FileBytes{
+		Name:  "heatmap.png",
+		Bytes: image.Bytes(),
+	})
+	photo.Caption = "🟩 Календарь активности за год. Чем темнее клетка, тем больше отжиманий относительно нормы. Перешлите картинку друзьям, чтобы похвастаться!"
+
+	if _, err := h.bot.Send(photo); err != nil {
+		log.Printf("Ошибка отправки календаря активности: %v", err)
+	}
+}
+
+// handleHeatmapCallback обрабатывает кнопку календаря под статистикой
+func (h *BotHandler) handleHeatmapCallback(ctx context.Context, callback *tgbotapi.CallbackQuery) {
+	h.answerCallback(callback.ID, "")
+	h.handleHeatmap(ctx, callback.From.ID, callback.Message.Chat.ID)
+}
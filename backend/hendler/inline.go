@@ -0,0 +1,253 @@
+package hendler
+
+import (
+	"context"
+	"log"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"trackerbot/model"
+	"trackerbot/presenter"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const (
+	inlineCacheTTL             = 30 * time.Second // Сколько живут посчитанные данные пользователя
+	inlineCardTTL              = 24 * time.Hour   // Сколько хранится file_id карточки: мини-график всё равно сдвигается за день
+	inlinePersonalCacheTime    = 30               // Сколько секунд Telegram кэширует личные результаты
+	inlineLeaderboardCacheTime = 300              // ... и общий рейтинг
+	inlineCardStartParameter   = "card"           // Параметр /start, по которому бот рисует карточку
+)
+
+// Результаты inline-режима и слова, по которым они находятся
+var (
+	inlineTodayKeywords       = []string{"my today", "today", "сегодня"}
+	inlineRecordKeywords      = []string{"my record", "record", "рекорд", "максимум"}
+	inlineCardKeywords        = []string{"my card", "card", "карточка"}
+	inlineLeaderboardKeywords = []string{"leaderboard", "top", "рейтинг", "топ"}
+)
+
+// inlineSnapshot — данные пользователя для ответа на inline-запросы
+type inlineSnapshot struct {
+	stat    *model.FullStatViewModel
+	card    *model.StatsCard
+	expires time.Time
+}
+
+// inlineCard — загруженная карточка и данные, по которым она нарисована
+type inlineCard struct {
+	fileID  string
+	card    model.StatsCard
+	expires time.Time
+}
+
+// InlineCache хранит данные пользователей между inline-запросами: Telegram присылает
+// запрос на каждое нажатие клавиши, и без кэша каждое из них ходило бы в базу.
+// Там же лежат file_id последних карточек — их можно отправить как cached photo
+type InlineCache struct {
+	mu        sync.Mutex
+	snapshots map[int64]inlineSnapshot
+	cards     map[int64]inlineCard
+	now       func() time.Time
+}
+
+func NewInlineCache() *InlineCache {
+	return &InlineCache{
+		snapshots: make(map[int64]inlineSnapshot),
+		cards:     make(map[int64]inlineCard),
+		now:       time.Now,
+	}
+}
+
+// evictExpired удаляет устаревшие записи, чтобы кэш не рос с числом пользователей.
+// Вызывается под c.mu
+func (c *InlineCache) evictExpired(now time.Time) {
+	for userID, snapshot := range c.snapshots {
+		if now.After(snapshot.expires) {
+			delete(c.snapshots, userID)
+		}
+	}
+	for userID, card := range c.cards {
+		if now.After(card.expires) {
+			delete(c.cards, userID)
+		}
+	}
+}
+
+// Snapshot возвращает данные пользователя, если они ещё не устарели
+func (c *InlineCache) Snapshot(userID int64) (*model.FullStatViewModel, *model.StatsCard, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	snapshot, ok := c.snapshots[userID]
+	if !ok || c.now().After(snapshot.expires) {
+		delete(c.snapshots, userID)
+		return nil, nil, false
+	}
+	return snapshot.stat, snapshot.card, true
+}
+
+// Store запоминает данные пользователя на inlineCacheTTL
+func (c *InlineCache) Store(userID int64, stat *model.FullStatViewModel, card *model.StatsCard) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	c.evictExpired(now)
+	c.snapshots[userID] = inlineSnapshot{stat: stat, card: card, expires: now.Add(inlineCacheTTL)}
+}
+
+// Card возвращает file_id последней карточки пользователя, если она нарисована
+// по тем же данным, что и current. Устаревшая карточка забывается
+func (c *InlineCache) Card(userID int64, current *model.StatsCard) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cached, ok := c.cards[userID]
+	if !ok {
+		return "", false
+	}
+	if c.now().After(cached.expires) || current == nil || !reflect.DeepEqual(cached.card, *current) {
+		delete(c.cards, userID)
+		return "", false
+	}
+	return cached.fileID, true
+}
+
+// SetCard запоминает file_id свежей карточки пользователя и данные, по которым она нарисована
+func (c *InlineCache) SetCard(userID int64, fileID string, card *model.StatsCard) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	c.evictExpired(now)
+	c.cards[userID] = inlineCard{fileID: fileID, card: *card, expires: now.Add(inlineCardTTL)}
+}
+
+// inlineMatches сообщает, подходит ли результат под запрос (пустой запрос подходит всем)
+func inlineMatches(query string, keywords []string) bool {
+	if query == "" {
+		return true
+	}
+	for _, keyword := range keywords {
+		if strings.HasPrefix(keyword, query) || strings.HasPrefix(query, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// inlineDisplayName — как подписать пользователя в сообщениях inline-режима
+func inlineDisplayName(user *tgbotapi.User) string {
+	if user.UserName != "" {
+		return "@" + user.UserName
+	}
+	return user.FirstName
+}
+
+// handleInlineQuery отвечает на запрос «@бот ...» из любого чата:
+// сегодняшний прогресс, рекорд, карточка и рейтинг за сегодня
+func (h *BotHandler) handleInlineQuery(query *tgbotapi.InlineQuery) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	userID := query.From.ID
+	text := strings.ToLower(strings.TrimSpace(query.Query))
+
+	wantToday := inlineMatches(text, inlineTodayKeywords)
+	wantRecord := inlineMatches(text, inlineRecordKeywords)
+	wantCard := inlineMatches(text, inlineCardKeywords)
+	wantLeaderboard := inlineMatches(text, inlineLeaderboardKeywords)
+
+	answer := tgbotapi.InlineConfig{
+		InlineQueryID: query.ID,
+		CacheTime:     inlineLeaderboardCacheTime,
+	}
+
+	if !wantToday && !wantRecord && !wantCard && !wantLeaderboard {
+		h.answerInline(answer)
+		return
+	}
+
+	stat, card, err := h.inlineSnapshot(ctx, userID)
+	if err != nil {
+		log.Printf("Ошибка подготовки inline-ответа: %v", err)
+		h.answerInline(answer)
+		return
+	}
+
+	name := inlineDisplayName(query.From)
+	personal := false
+
+	if wantToday {
+		article := tgbotapi.NewInlineQueryResultArticle("today", "📈 Мой прогресс за сегодня", presenter.FormatInlineToday(name, stat))
+		article.Description = presenter.FormatInlineTodayShort(stat)
+		answer.Results = append(answer.Results, article)
+		personal = true
+	}
+
+	if wantRecord {
+		article := tgbotapi.NewInlineQueryResultArticle("record", "🏆 Мой рекорд", presenter.FormatInlineRecord(name, card))
+		article.Description = presenter.FormatInlineRecordShort(card)
+		answer.Results = append(answer.Results, article)
+		personal = true
+	}
+
+	if wantCard {
+		if fileID, ok := h.inlineCache.Card(userID, card); ok {
+			photo := tgbotapi.NewInlineQueryResultCachedPhoto("card", fileID)
+			photo.Title = "📤 Моя карточка"
+			answer.Results = append(answer.Results, photo)
+		} else {
+			answer.SwitchPMText = "📤 Создать карточку"
+			answer.SwitchPMParameter = inlineCardStartParameter
+		}
+		personal = true
+	}
+
+	if wantLeaderboard {
+		article := tgbotapi.NewInlineQueryResultArticle("leaderboard", "🥇 Рейтинг за сегодня", presenter.FormatInlineLeaderboard(stat.Leaderboard))
+		article.Description = "Топ пользователей по отжиманиям за сегодня"
+		answer.Results = append(answer.Results, article)
+	}
+
+	// Личные результаты нельзя показывать другим пользователям из кэша Telegram
+	if personal {
+		answer.IsPersonal = true
+		answer.CacheTime = inlinePersonalCacheTime
+	}
+
+	h.answerInline(answer)
+}
+
+// inlineSnapshot берёт данные пользователя из кэша или собирает заново
+func (h *BotHandler) inlineSnapshot(ctx context.Context, userID int64) (*model.FullStatViewModel, *model.StatsCard, error) {
+	if stat, card, ok := h.inlineCache.Snapshot(userID); ok {
+		return stat, card, nil
+	}
+
+	stat, err := h.service.GetFullStat(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	card, err := h.service.GetStatsCard(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	h.inlineCache.Store(userID, stat, card)
+	return stat, card, nil
+}
+
+// answerInline отправляет ответ на inline-запрос
+func (h *BotHandler) answerInline(answer tgbotapi.InlineConfig) {
+	if answer.Results == nil {
+		answer.Results = []interface{}{}
+	}
+	if _, err := h.bot.Request(answer); err != nil {
+		log.Printf("Ошибка ответа на inline-запрос: %v", err)
+	}
+}
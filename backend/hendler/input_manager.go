@@ -14,6 +14,9 @@ type PendingInput struct {
 	InputType   inputType
 	MessageID   int
 	CancelMsgID int
+	Exercise    string
+	Variant     string
+	RecordID    int64 // Исправляемая запись истории теста максимума
 }
 
 func (m *InputManager) Set(chatID int64, input PendingInput) {
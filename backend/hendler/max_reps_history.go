@@ -0,0 +1,191 @@
+package hendler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	ui "trackerbot/keyboard"
+	"trackerbot/model"
+	"trackerbot/presenter"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// handleMaxRepsHistoryCallback обрабатывает листание и исправление истории теста максимума:
+//
+//	history:page:<диапазон>:<N> — показать страницу N истории в диапазоне
+//	history:noop                — номер страницы, ничего не делает
+//	history:chart_norm          — прислать график с наложенной дневной нормой
+//	history:edit:<id>           — ввести новый результат
+//	history:delete:<id>         — спросить подтверждение удаления
+//	history:delete_confirm:<id> — удалить запись
+//	history:norm                — пересчитать норму по текущему максимуму
+//	history:cancel              — ничего не менять
+func (h *BotHandler) handleMaxRepsHistoryCallback(ctx context.Context, callback *tgbotapi.CallbackQuery) {
+	userID := callback.From.ID
+	chatID := callback.Message.Chat.ID
+	action := strings.TrimPrefix(callback.Data, "history:")
+
+	switch {
+	case strings.HasPrefix(action, "page:"):
+		h.showMaxRepsHistoryPage(ctx, callback, strings.TrimPrefix(action, "page:"))
+
+	case action == "noop":
+		h.answerCallback(callback.ID, "")
+
+	case action == "chart_norm":
+		history, err := h.service.GetMaxRepsHistory(ctx, userID)
+		if err != nil {
+			log.Printf("GetMaxRepsHistory error: %v", err)
+			h.answerCallback(callback.ID, "Ошибка")
+			return
+		}
+
+		h.answerCallback(callback.ID, "")
+		h.sendProgressChart(ctx, userID, chatID, history, true)
+
+	case strings.HasPrefix(action, "edit:"):
+		recordID, err := strconv.ParseInt(strings.TrimPrefix(action, "edit:"), 10, 64)
+		if err != nil {
+			h.answerCallback(callback.ID, "Некорректные данные")
+			return
+		}
+
+		h.answerCallback(callback.ID, "")
+		h.requestNumber(chatID, inputTypeHistoryEdit)
+		if input, ok := h.inputManager.Get(chatID); ok {
+			input.RecordID = recordID
+			h.inputManager.Set(chatID, input)
+		}
+
+	case strings.HasPrefix(action, "delete:"):
+		recordID, err := strconv.ParseInt(strings.TrimPrefix(action, "delete:"), 10, 64)
+		if err != nil {
+			h.answerCallback(callback.ID, "Некорректные данные")
+			return
+		}
+
+		h.answerCallback(callback.ID, "")
+		h.sendMessage(chatID, "Удалить эту запись из истории? Максимум и ранг пересчитаются по оставшимся записям.",
+			ui.DeleteHistoryEntryInlineKeyboard(recordID))
+
+	case strings.HasPrefix(action, "delete_confirm:"):
+		recordID, err := strconv.ParseInt(strings.TrimPrefix(action, "delete_confirm:"), 10, 64)
+		if err != nil {
+			h.answerCallback(callback.ID, "Некорректные данные")
+			return
+		}
+
+		change, err := h.service.DeleteMaxRepsEntry(ctx, userID, recordID)
+		if err != nil {
+			log.Printf("DeleteMaxRepsEntry error: %v", err)
+			h.answerCallback(callback.ID, "Не удалось удалить запись")
+			return
+		}
+
+		h.answerCallback(callback.ID, "Запись удалена")
+		h.editCallbackMessage(callback, "🗑 Запись удалена")
+		h.sendMaxRepsHistoryChange(chatID, change)
+
+	case action == "norm":
+		dailyNorm, err := h.service.RecalculateNorm(ctx, userID)
+		if err != nil {
+			log.Printf("RecalculateNorm error: %v", err)
+			h.answerCallback(callback.ID, "Ошибка")
+			return
+		}
+
+		h.answerCallback(callback.ID, "Норма пересчитана")
+		h.editCallbackMessage(callback, fmt.Sprintf("🔔 Дневная норма пересчитана: %d", dailyNorm))
+
+	case action == "cancel":
+		h.answerCallback(callback.ID, "")
+		h.editCallbackMessage(callback, "Ничего не изменено")
+
+	default:
+		h.answerCallback(callback.ID, "Неизвестное действие")
+	}
+}
+
+// showMaxRepsHistoryPage заменяет сообщение с историей выбранной страницей ("<диапазон>:<N>")
+func (h *BotHandler) showMaxRepsHistoryPage(ctx context.Context, callback *tgbotapi.CallbackQuery, data string) {
+	rangeCode, pageStr, found := strings.Cut(data, ":")
+	page, err := strconv.Atoi(pageStr)
+	if !found || err != nil {
+		h.answerCallback(callback.ID, "Некорректные данные")
+		return
+	}
+
+	historyPage, err := h.service.GetMaxRepsHistoryPage(ctx, callback.From.ID, rangeCode, page)
+	if err != nil {
+		log.Printf("GetMaxRepsHistoryPage error: %v", err)
+		h.answerCallback(callback.ID, "Ошибка")
+		return
+	}
+
+	h.answerCallback(callback.ID, "")
+
+	edit := tgbotapi.NewEditMessageTextAndMarkup(
+		callback.Message.Chat.ID,
+		callback.Message.MessageID,
+		presenter.FormatProgressHistoryPage(historyPage),
+		ui.MaxRepsHistoryInlineKeyboard(historyPage),
+	)
+	if _, err := h.bot.Send(edit); err != nil {
+		log.Printf("Ошибка листания истории: %v", err)
+	}
+}
+
+// sendProgressChart присылает график теста максимума; без нормы — с кнопкой её наложить
+func (h *BotHandler) sendProgressChart(
+	ctx context.Context,
+	userID int64,
+	chatID int64,
+	history []model.MaxRepsHistoryItem,
+	withNorm bool,
+) {
+
+	image, err := h.service.BuildSchedule(ctx, userID, history, withNorm)
+	if err != nil {
+		log.Printf("Ошибка построения графика прогресса: %v", err)
+		return
+	}
+
+	photo := tgbotapi.NewPhoto(chatID, tgbotapi.FileBytes{
+		Name:  "schedule.png",
+		Bytes: image.Bytes(),
+	})
+	if !withNorm {
+		photo.ReplyMarkup = ui.ProgressChartInlineKeyboard()
+	}
+
+	if _, err := h.bot.Send(photo); err != nil {
+		log.Printf("Ошибка отправки графика прогресса: %v", err)
+	}
+}
+
+// handleEditMaxRepsEntry сохраняет исправленный результат из ввода
+func (h *BotHandler) handleEditMaxRepsEntry(ctx context.Context, userID int64, chatID int64, recordID int64, count int) {
+	change, err := h.service.EditMaxRepsEntry(ctx, userID, recordID, count)
+	if err != nil {
+		log.Printf("EditMaxRepsEntry error: %v", err)
+		h.sendError(chatID)
+		return
+	}
+
+	h.sendMaxRepsHistoryChange(chatID, change)
+}
+
+// sendMaxRepsHistoryChange показывает итог исправления и, если изменился последний тест,
+// предлагает пересчитать норму
+func (h *BotHandler) sendMaxRepsHistoryChange(chatID int64, change *model.MaxRepsHistoryChange) {
+	var markup interface{} = ui.MainKeyboard()
+	if change.LatestChanged && change.MaxReps > 0 {
+		markup = ui.RecalculateNormInlineKeyboard()
+	}
+
+	h.sendMessage(chatID, presenter.FormatMaxRepsHistoryChange(change), markup)
+}
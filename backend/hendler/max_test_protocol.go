@@ -0,0 +1,123 @@
+package hendler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	ui "trackerbot/keyboard"
+	"trackerbot/presenter"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// handleMaxTestIntro начинает тест максимума: проверка готовности и разминка
+func (h *BotHandler) handleMaxTestIntro(ctx context.Context, userID int64, chatID int64) {
+	readiness, err := h.service.GetMaxTestReadiness(ctx, userID)
+	if err != nil {
+		log.Printf("GetMaxTestReadiness error: %v", err)
+		h.sendError(chatID)
+		return
+	}
+
+	h.sendMarkdownMessage(chatID, presenter.FormatMaxTestIntro(readiness, time.Now()), ui.MaxTestIntroInlineKeyboard())
+}
+
+// handleMaxTestResult спрашивает тяжесть теста после ввода результата
+func (h *BotHandler) handleMaxTestResult(chatID int64, count int) {
+	h.sendMessage(chatID, presenter.FormatMaxTestRPEQuestion(count), ui.MaxTestRPEInlineKeyboard(count))
+}
+
+// handleMaxTestCallback обрабатывает шаги теста максимума:
+//
+//	max_test:go                  — разминка закончена, ждём результат
+//	max_test:later               — тест отложен
+//	max_test:rpe:<результат>:<rpe> — тяжесть указана (0 — пропущена)
+//	max_test:save:<результат>:<rpe> — подтверждено сохранение необычного результата
+//	max_test:cancel              — необычный результат не сохраняем
+func (h *BotHandler) handleMaxTestCallback(ctx context.Context, callback *tgbotapi.CallbackQuery) {
+	userID := callback.From.ID
+	chatID := callback.Message.Chat.ID
+	action := strings.TrimPrefix(callback.Data, "max_test:")
+
+	switch {
+	case action == "go":
+		h.answerCallback(callback.ID, "")
+		h.editCallbackMessage(callback, "🔥 Разминка завершена. Сделайте один подход до отказа и введите результат.")
+		h.requestNumber(chatID, inputTypeMaxTest)
+
+	case action == "later":
+		h.answerCallback(callback.ID, "Тест отложен")
+		h.editCallbackMessage(callback, "⏳ Тест отложен. Пройти его можно в любой момент через «🎯 Тест максимальных отжиманий».")
+
+	case action == "cancel":
+		h.answerCallback(callback.ID, "Результат не сохранён")
+		h.editCallbackMessage(callback, "❌ Результат не сохранён. Повторите тест, когда отдохнёте.")
+
+	case strings.HasPrefix(action, "rpe:"), strings.HasPrefix(action, "save:"):
+		step, rawResult, _ := strings.Cut(action, ":")
+		count, rpe, ok := parseMaxTestResult(rawResult)
+		if !ok {
+			h.answerCallback(callback.ID, "Некорректные данные")
+			return
+		}
+
+		if step == "rpe" {
+			check, err := h.service.CheckMaxTestResult(ctx, userID, count)
+			if err != nil {
+				log.Printf("CheckMaxTestResult error: %v", err)
+				h.answerCallback(callback.ID, "Ошибка")
+				return
+			}
+			if check.Deviates {
+				h.answerCallback(callback.ID, "")
+				h.editCallbackMessage(callback, fmt.Sprintf("Результат: %d", count))
+				h.sendMessage(chatID, presenter.FormatMaxTestDeviation(check), ui.MaxTestConfirmInlineKeyboard(count, rpe))
+				return
+			}
+		}
+
+		h.answerCallback(callback.ID, "Сохранено")
+		h.editCallbackMessage(callback, fmt.Sprintf("Результат: %d", count))
+		h.saveMaxTest(ctx, userID, chatID, count, rpe)
+
+	default:
+		h.answerCallback(callback.ID, "Неизвестное действие")
+	}
+}
+
+// parseMaxTestResult разбирает "<результат>:<rpe>" из callback
+func parseMaxTestResult(raw string) (int, int, bool) {
+	rawCount, rawRPE, ok := strings.Cut(raw, ":")
+	if !ok {
+		return 0, 0, false
+	}
+
+	count, err := strconv.Atoi(rawCount)
+	if err != nil || count < 1 || count > maxRepsLimit {
+		return 0, 0, false
+	}
+	rpe, err := strconv.Atoi(rawRPE)
+	if err != nil {
+		return 0, 0, false
+	}
+
+	return count, rpe, true
+}
+
+// saveMaxTest сохраняет результат теста и пересчитывает норму
+func (h *BotHandler) saveMaxTest(ctx context.Context, userID int64, chatID int64, count, rpe int) {
+	vm, err := h.service.RecordMaxTest(ctx, userID, count, rpe)
+	if err != nil {
+		log.Printf("RecordMaxTest error: %v", err)
+		h.sendError(chatID)
+		return
+	}
+
+	h.sendMessage(chatID, presenter.FormatMaxReps(vm), ui.MainKeyboard())
+	h.notifyRankChange(chatID, vm.RankChange)
+	h.notifyAchievements(chatID, vm.NewAchievements)
+}
@@ -0,0 +1,75 @@
+package hendler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	ui "trackerbot/keyboard"
+	"trackerbot/presenter"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// handleNormStrategies показывает стратегии расчёта нормы и текущий выбор
+func (h *BotHandler) handleNormStrategies(ctx context.Context, userID int64, chatID int64) {
+	current, err := h.service.GetUserNormStrategy(ctx, userID)
+	if err != nil {
+		log.Printf("GetUserNormStrategy error: %v", err)
+		h.sendError(chatID)
+		return
+	}
+
+	autoNorm, err := h.service.GetAutoNorm(ctx, userID)
+	if err != nil {
+		log.Printf("GetAutoNorm error: %v", err)
+		h.sendError(chatID)
+		return
+	}
+
+	strategies := h.service.GetNormStrategies()
+	h.sendMarkdownMessage(
+		chatID,
+		presenter.FormatNormStrategies(strategies, current, autoNorm),
+		ui.NormStrategyInlineKeyboard(strategies, current.Code, autoNorm),
+	)
+}
+
+// handleNormStrategyCallback сохраняет выбранную стратегию
+func (h *BotHandler) handleNormStrategyCallback(ctx context.Context, callback *tgbotapi.CallbackQuery) {
+	userID := callback.From.ID
+	code := strings.TrimPrefix(callback.Data, "norm_strategy:")
+
+	strategy, err := h.service.SetNormStrategy(ctx, userID, code)
+	if err != nil {
+		log.Printf("SetNormStrategy error: %v", err)
+		h.answerCallback(callback.ID, "Ошибка")
+		return
+	}
+
+	h.answerCallback(callback.ID, "Сохранено")
+	h.editCallbackMessage(callback, fmt.Sprintf(
+		"✅ Стратегия нормы: %s\nНовая норма будет рассчитана при следующем тесте максимальных отжиманий.",
+		strategy.Name,
+	))
+}
+
+// handleAutoNormCallback включает или выключает ночную автоподстройку нормы
+func (h *BotHandler) handleAutoNormCallback(ctx context.Context, callback *tgbotapi.CallbackQuery) {
+	enabled := callback.Data == "auto_norm:on"
+
+	if err := h.service.SetAutoNorm(ctx, callback.From.ID, enabled); err != nil {
+		log.Printf("SetAutoNorm error: %v", err)
+		h.answerCallback(callback.ID, "Ошибка")
+		return
+	}
+
+	text := "⏸ Автоподстройка нормы выключена. Норма меняется только после теста или вручную."
+	if enabled {
+		text = "🔄 Автоподстройка нормы включена. Каждую ночь бот сравнивает последние дни с нормой и, если нужно, немного её меняет — с объяснением причины."
+	}
+
+	h.answerCallback(callback.ID, "Сохранено")
+	h.editCallbackMessage(callback, text)
+}
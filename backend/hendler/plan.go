@@ -0,0 +1,20 @@
+package hendler
+
+import (
+	"context"
+	"log"
+
+	"trackerbot/presenter"
+)
+
+// handleDailyPlan показывает дневную норму, разбитую на подходы
+func (h *BotHandler) handleDailyPlan(ctx context.Context, userID int64, chatID int64) {
+	plan, err := h.service.GetDailyPlan(ctx, userID)
+	if err != nil {
+		log.Printf("GetDailyPlan error: %v", err)
+		h.sendError(chatID)
+		return
+	}
+
+	h.sendMarkdownMessage(chatID, presenter.FormatDailyPlan(plan), nil)
+}
@@ -0,0 +1,96 @@
+package hendler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	ui "trackerbot/keyboard"
+	"trackerbot/model"
+	"trackerbot/presenter"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// handlePrograms показывает тренировку по текущей программе или каталог программ
+func (h *BotHandler) handlePrograms(ctx context.Context, userID int64, chatID int64) {
+	workout, err := h.service.GetProgramWorkout(ctx, userID)
+	if err != nil {
+		log.Printf("GetProgramWorkout error: %v", err)
+		h.sendError(chatID)
+		return
+	}
+
+	if workout != nil {
+		h.sendMarkdownMessage(chatID, presenter.FormatProgramWorkout(workout, time.Now()), ui.ProgramWorkoutInlineKeyboard(workout))
+		return
+	}
+
+	programs := h.service.GetPrograms()
+	h.sendMarkdownMessage(chatID, presenter.FormatPrograms(programs), ui.ProgramCatalogInlineKeyboard(programs))
+}
+
+// handleProgramCallback обрабатывает "program:enroll:<код>", "program:done:<неделя>:<день>",
+// "program:fail:<неделя>:<день>" и "program:leave"
+func (h *BotHandler) handleProgramCallback(ctx context.Context, callback *tgbotapi.CallbackQuery) {
+	userID := callback.From.ID
+	chatID := callback.Message.Chat.ID
+	action := strings.TrimPrefix(callback.Data, "program:")
+
+	switch {
+	case strings.HasPrefix(action, "enroll:"):
+		workout, err := h.service.EnrollProgram(ctx, userID, strings.TrimPrefix(action, "enroll:"))
+		if err != nil {
+			log.Printf("EnrollProgram error: %v", err)
+			h.answerCallback(callback.ID, "Не удалось записаться")
+			h.sendMessage(chatID, fmt.Sprintf("❌ %v", err), nil)
+			return
+		}
+
+		h.answerCallback(callback.ID, "Вы записаны на программу")
+		h.editCallbackMessage(callback, "✅ Вы записаны на программу. Первая тренировка:")
+		h.sendMarkdownMessage(chatID, presenter.FormatProgramWorkout(workout, time.Now()), ui.ProgramWorkoutInlineKeyboard(workout))
+
+	case strings.HasPrefix(action, "done:"), strings.HasPrefix(action, "fail:"):
+		outcome, rawSession, _ := strings.Cut(action, ":")
+		rawWeek, rawDay, _ := strings.Cut(rawSession, ":")
+		week, weekErr := strconv.Atoi(rawWeek)
+		day, dayErr := strconv.Atoi(rawDay)
+		if weekErr != nil || dayErr != nil {
+			h.answerCallback(callback.ID, "Некорректные данные")
+			return
+		}
+
+		result, err := h.service.CompleteProgramSession(ctx, userID, week, day, outcome == "done")
+		if errors.Is(err, model.ErrProgramSessionOutdated) {
+			h.answerCallback(callback.ID, "Эта тренировка уже отмечена")
+			h.editCallbackMessage(callback, "⌛ Эта тренировка уже отмечена. Текущая — в «🗓 Программы».")
+			return
+		}
+		if err != nil {
+			log.Printf("CompleteProgramSession error: %v", err)
+			h.answerCallback(callback.ID, "Ошибка")
+			return
+		}
+
+		h.answerCallback(callback.ID, "Сохранено")
+		h.editCallbackMessage(callback, presenter.FormatProgramSessionResult(result))
+
+	case action == "leave":
+		if err := h.service.LeaveProgram(ctx, userID); err != nil {
+			log.Printf("LeaveProgram error: %v", err)
+			h.answerCallback(callback.ID, "Ошибка")
+			return
+		}
+
+		h.answerCallback(callback.ID, "Вы вышли из программы")
+		h.editCallbackMessage(callback, "🚪 Вы вышли из программы. Выбрать новую можно в «🗓 Программы».")
+
+	default:
+		h.answerCallback(callback.ID, "Неизвестное действие")
+	}
+}
@@ -0,0 +1,25 @@
+package hendler
+
+import (
+	"trackerbot/model"
+	"trackerbot/presenter"
+)
+
+// SetAnnouncementChat включает объявления о новых рангах в общем чате
+func (h *BotHandler) SetAnnouncementChat(chatID int64) {
+	h.announceChatID = chatID
+}
+
+// notifyRankChange поздравляет с повышением ранга и объявляет о нём в общем чате.
+// О понижении пользователь узнаёт из ответа на тест
+func (h *BotHandler) notifyRankChange(chatID int64, change *model.RankChange) {
+	if change == nil || !change.Promoted {
+		return
+	}
+
+	h.sendMarkdownMessage(chatID, presenter.FormatRankUp(change), nil)
+
+	if h.announceChatID != 0 && h.announceChatID != chatID {
+		h.sendMarkdownMessage(h.announceChatID, presenter.FormatRankAnnouncement(change), nil)
+	}
+}
@@ -0,0 +1,56 @@
+package hendler
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"trackerbot/config"
+	ui "trackerbot/keyboard"
+	"trackerbot/presenter"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// RunMaxTestReminders периодически напоминает пользователям пройти тест максимума.
+// Блокируется до отмены ctx — запускать в отдельной горутине.
+func (h *BotHandler) RunMaxTestReminders(ctx context.Context, cfg config.ReminderConfig) {
+	if !cfg.Enabled {
+		return
+	}
+
+	interval := time.Duration(cfg.MaxTestIntervalDays) * 24 * time.Hour
+
+	runPeriodically(ctx, cfg.CheckInterval, func(ctx context.Context, now time.Time) {
+		if !inHoursWindow(now, cfg.FromHour, cfg.ToHour) {
+			return
+		}
+		h.sendMaxTestReminders(ctx, interval, now)
+	})
+}
+
+func (h *BotHandler) sendMaxTestReminders(ctx context.Context, interval time.Duration, now time.Time) {
+	jobCtx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+
+	reminders, err := h.service.GetMaxTestReminders(jobCtx, interval)
+	if err != nil {
+		log.Printf("GetMaxTestReminders error: %v", err)
+		return
+	}
+
+	for _, reminder := range reminders {
+		// В личном чате chatID совпадает с userID
+		msg := tgbotapi.NewMessage(reminder.UserID, presenter.FormatMaxTestReminder(reminder, now))
+		msg.ReplyMarkup = ui.MaxTestReminderInlineKeyboard()
+
+		if _, err := h.bot.Send(msg); err != nil {
+			log.Printf("Ошибка отправки напоминания пользователю %d: %v", reminder.UserID, err)
+		}
+
+		// Отмечаем и при ошибке отправки (например, бот заблокирован), чтобы не повторять её каждую проверку
+		if err := h.service.MarkMaxTestReminded(jobCtx, reminder.UserID); err != nil {
+			log.Printf("MarkMaxTestReminded error: %v", err)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package hendler
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"strings"
+
+	ui "trackerbot/keyboard"
+	"trackerbot/presenter"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// handleRestStatus показывает текущий перерыв или кнопки, чтобы его взять
+func (h *BotHandler) handleRestStatus(ctx context.Context, userID int64, chatID int64) {
+	active, err := h.service.GetActiveRest(ctx, userID)
+	if err != nil {
+		log.Printf("GetActiveRest error: %v", err)
+		h.sendError(chatID)
+		return
+	}
+
+	h.sendMessage(chatID, presenter.FormatRestStatus(active), ui.RestInlineKeyboard(active != nil))
+}
+
+// handleRestCallback обрабатывает "rest:<вид>:<дней>" и "rest:end"
+func (h *BotHandler) handleRestCallback(ctx context.Context, callback *tgbotapi.CallbackQuery) {
+	userID := callback.From.ID
+	data := strings.TrimPrefix(callback.Data, "rest:")
+
+	if data == "end" {
+		if err := h.service.EndRest(ctx, userID); err != nil {
+			log.Printf("EndRest error: %v", err)
+			h.answerCallback(callback.ID, "Ошибка")
+			return
+		}
+
+		h.answerCallback(callback.ID, "С возвращением!")
+		h.editCallbackMessage(callback, "▶️ Перерыв завершён. С возвращением к тренировкам! 💪")
+		return
+	}
+
+	kind, daysText, ok := strings.Cut(data, ":")
+	days, err := strconv.Atoi(daysText)
+	if !ok || err != nil {
+		h.answerCallback(callback.ID, "Неизвестный перерыв")
+		return
+	}
+
+	period, err := h.service.StartRest(ctx, userID, kind, days)
+	if err != nil {
+		log.Printf("StartRest error: %v", err)
+		h.answerCallback(callback.ID, "Ошибка")
+		return
+	}
+
+	h.answerCallback(callback.ID, "Сохранено")
+	h.editCallbackMessage(callback, presenter.FormatRestStarted(period))
+}
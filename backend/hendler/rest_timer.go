@@ -0,0 +1,222 @@
+package hendler
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	ui "trackerbot/keyboard"
+	"trackerbot/presenter"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const (
+	defaultRestSeconds = 90
+	minRestSeconds     = 10
+	maxRestSeconds     = 600
+
+	// Telegram ограничивает частоту правок сообщения, поэтому обратный отсчёт обновляется раз в 5 секунд
+	restTimerTick = 5 * time.Second
+)
+
+// restTimer — запущенный таймер отдыха в одном чате
+type restTimer struct {
+	messageID int // Сообщение с обратным отсчётом — по нему кнопка «⏹ Остановить» находит свой таймер
+	cancel    context.CancelFunc
+}
+
+// RestTimerManager хранит таймеры отдыха по чатам: в чате одновременно идёт не больше одного таймера,
+// новый таймер заменяет старый. Stop отменяет все таймеры и дожидается их завершения.
+type RestTimerManager struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu     sync.Mutex
+	timers map[int64]*restTimer
+	wg     sync.WaitGroup
+}
+
+func NewRestTimerManager() *RestTimerManager {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &RestTimerManager{
+		ctx:    ctx,
+		cancel: cancel,
+		timers: make(map[int64]*restTimer),
+	}
+}
+
+// Start запускает run в отдельной горутине, отменяя предыдущий таймер чата.
+// messageID — сообщение с обратным отсчётом этого таймера. После Stop новые таймеры не запускаются
+func (m *RestTimerManager) Start(chatID int64, messageID int, run func(ctx context.Context)) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.ctx.Err() != nil {
+		return false
+	}
+
+	if old, ok := m.timers[chatID]; ok {
+		old.cancel()
+	}
+
+	ctx, cancel := context.WithCancel(m.ctx)
+	timer := &restTimer{messageID: messageID, cancel: cancel}
+	m.timers[chatID] = timer
+
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		defer m.finish(chatID, timer)
+		run(ctx)
+	}()
+
+	return true
+}
+
+// finish убирает таймер из списка, если его ещё не заменили новым
+func (m *RestTimerManager) finish(chatID int64, timer *restTimer) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	timer.cancel()
+	if m.timers[chatID] == timer {
+		delete(m.timers, chatID)
+	}
+}
+
+// Cancel останавливает таймер чата с сообщением messageID; возвращает false, если такого таймера
+// уже нет — кнопка со старого отсчёта не трогает таймер, запущенный после него
+func (m *RestTimerManager) Cancel(chatID int64, messageID int) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	timer, ok := m.timers[chatID]
+	if !ok || timer.messageID != messageID {
+		return false
+	}
+
+	timer.cancel()
+	delete(m.timers, chatID)
+	return true
+}
+
+// Active возвращает число идущих таймеров
+func (m *RestTimerManager) Active() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.timers)
+}
+
+// Stop отменяет все таймеры и ждёт завершения их горутин
+func (m *RestTimerManager) Stop() {
+	m.mu.Lock()
+	m.cancel()
+	m.mu.Unlock()
+
+	m.wg.Wait()
+}
+
+// StopRestTimers отменяет таймеры отдыха и ждёт их завершения — вызывать при остановке бота
+func (h *BotHandler) StopRestTimers() {
+	h.restTimers.Stop()
+}
+
+// handleRestTimerCommand обрабатывает /rest [секунды]
+func (h *BotHandler) handleRestTimerCommand(chatID int64, args string) {
+	seconds := defaultRestSeconds
+	if args = strings.TrimSpace(args); args != "" {
+		value, err := strconv.Atoi(args)
+		if err != nil || value < minRestSeconds || value > maxRestSeconds {
+			h.sendMessage(chatID, "Укажите отдых в секундах: от 10 до 600, например /rest 90", nil)
+			return
+		}
+		seconds = value
+	}
+
+	h.startRestTimer(chatID, time.Duration(seconds)*time.Second)
+}
+
+// handleRestTimerCallback обрабатывает "rest_timer:<секунды>" и "rest_timer:stop".
+// Кнопка остановки стоит на сообщении с отсчётом и останавливает только его таймер
+func (h *BotHandler) handleRestTimerCallback(callback *tgbotapi.CallbackQuery) {
+	chatID := callback.Message.Chat.ID
+	action := strings.TrimPrefix(callback.Data, "rest_timer:")
+
+	if action == "stop" {
+		if h.restTimers.Cancel(chatID, callback.Message.MessageID) {
+			h.answerCallback(callback.ID, "Таймер остановлен")
+		} else {
+			h.answerCallback(callback.ID, "Этот таймер уже не идёт")
+		}
+		h.editCallbackMessage(callback, "⏹ Таймер отдыха остановлен")
+		return
+	}
+
+	seconds, err := strconv.Atoi(action)
+	if err != nil || seconds < minRestSeconds || seconds > maxRestSeconds {
+		h.answerCallback(callback.ID, "Некорректные данные")
+		return
+	}
+
+	h.answerCallback(callback.ID, "")
+	h.startRestTimer(chatID, time.Duration(seconds)*time.Second)
+}
+
+// startRestTimer отправляет сообщение с обратным отсчётом и запускает таймер
+func (h *BotHandler) startRestTimer(chatID int64, duration time.Duration) {
+	msg := tgbotapi.NewMessage(chatID, presenter.FormatRestTimer(duration))
+	msg.ReplyMarkup = ui.RestTimerStopInlineKeyboard()
+
+	sent, err := h.bot.Send(msg)
+	if err != nil {
+		log.Printf("Ошибка отправки таймера отдыха: %v", err)
+		return
+	}
+
+	until := time.Now().Add(duration)
+	h.restTimers.Start(chatID, sent.MessageID, func(ctx context.Context) {
+		h.runRestCountdown(ctx, chatID, sent.MessageID, until, restTimerTick)
+	})
+}
+
+// runRestCountdown обновляет сообщение с оставшимся временем, а по окончании
+// отправляет новое сообщение — правка сообщения не вызывает уведомления
+func (h *BotHandler) runRestCountdown(ctx context.Context, chatID int64, messageID int, until time.Time, tick time.Duration) {
+	ticker := time.NewTicker(tick)
+	defer ticker.Stop()
+
+	deadline := time.NewTimer(time.Until(until))
+	defer deadline.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-deadline.C:
+			edit := tgbotapi.NewEditMessageText(chatID, messageID, presenter.FormatRestTimer(0))
+			if _, err := h.bot.Send(edit); err != nil {
+				log.Printf("Ошибка обновления таймера отдыха: %v", err)
+			}
+			h.sendMessage(chatID, presenter.FormatRestTimerDone(), ui.RestTimerInlineKeyboard(defaultRestSeconds))
+			return
+
+		case now := <-ticker.C:
+			remaining := until.Sub(now)
+			if remaining <= 0 {
+				continue
+			}
+
+			edit := tgbotapi.NewEditMessageTextAndMarkup(
+				chatID, messageID, presenter.FormatRestTimer(remaining), ui.RestTimerStopInlineKeyboard(),
+			)
+			if _, err := h.bot.Send(edit); err != nil {
+				log.Printf("Ошибка обновления таймера отдыха: %v", err)
+			}
+		}
+	}
+}
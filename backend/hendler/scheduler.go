@@ -0,0 +1,26 @@
+package hendler
+
+import (
+	"context"
+	"time"
+)
+
+// runPeriodically вызывает job каждые interval до отмены ctx
+func runPeriodically(ctx context.Context, interval time.Duration, job func(ctx context.Context, now time.Time)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			job(ctx, now)
+		}
+	}
+}
+
+// inHoursWindow проверяет, что текущий час попадает в окно [fromHour, toHour)
+func inHoursWindow(now time.Time, fromHour, toHour int) bool {
+	return now.Hour() >= fromHour && now.Hour() < toHour
+}
@@ -0,0 +1,64 @@
+package hendler
+
+import (
+	"context"
+	"log"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// handleStatsCard присылает карточку статистики, которую можно переслать в другой чат
+func (h *BotHandler) handleStatsCard(ctx context.Context, userID int64, chatID int64) {
+	card, image, err := h.service.BuildStatsCard(ctx, userID)
+	if err != nil {
+		log.Printf("BuildStatsCard error: %v", err)
+		h.sendError(chatID)
+		return
+	}
+
+	photo := tgbotapi.NewPhoto(chatID, tgbotapi.FileBytes{
+		Name:  "card.png",
+		Bytes: image.Bytes(),
+	})
+	photo.Caption = "📤 Ваша карточка готова — перешлите её в любой чат!"
+
+	sent, err := h.bot.Send(photo)
+	if err != nil {
+		log.Printf("Ошибка отправки карточки статистики: %v", err)
+		return
+	}
+
+	// Запоминаем загруженную картинку вместе с данными, чтобы отправлять её из inline-режима, пока они не изменились
+	if len(sent.Photo) > 0 {
+		h.inlineCache.SetCard(userID, sent.Photo[len(sent.Photo)-1].FileID, card)
+	}
+}
+
+// handleStartCard рисует карточку по ссылке из inline-режима (/start card).
+// По ссылке может прийти и новый пользователь — тогда сначала обычный старт с вводом максимума
+func (h *BotHandler) handleStartCard(ctx context.Context, chatID int64, userID int64, username string) {
+	if err := h.service.EnsureUser(ctx, userID, username); err != nil {
+		log.Printf("Ошибка при создании или обновлении пользователя: %v", err)
+		h.sendError(chatID)
+		return
+	}
+
+	maxReps, err := h.service.GetUserMaxReps(ctx, userID)
+	if err != nil {
+		log.Printf("Ошибка получения данных: %v", err)
+		h.sendError(chatID)
+		return
+	}
+	if maxReps == 0 {
+		h.handleStart(ctx, chatID, userID, username, inputTypeMaxReps)
+		return
+	}
+
+	h.handleStatsCard(ctx, userID, chatID)
+}
+
+// handleStatsCardCallback обрабатывает кнопку «📤 Поделиться» под статистикой
+func (h *BotHandler) handleStatsCardCallback(ctx context.Context, callback *tgbotapi.CallbackQuery) {
+	h.answerCallback(callback.ID, "")
+	h.handleStatsCard(ctx, callback.From.ID, callback.Message.Chat.ID)
+}
@@ -0,0 +1,39 @@
+package hendler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// handleVolumeChartCallback присылает график объёма по дням ("volume:<дней>")
+func (h *BotHandler) handleVolumeChartCallback(ctx context.Context, callback *tgbotapi.CallbackQuery) {
+	days, err := strconv.Atoi(strings.TrimPrefix(callback.Data, "volume:"))
+	if err != nil {
+		h.answerCallback(callback.ID, "Некорректные данные")
+		return
+	}
+
+	image, err := h.service.BuildVolumeChart(ctx, callback.From.ID, days)
+	if err != nil {
+		log.Printf("BuildVolumeChart error: %v", err)
+		h.answerCallback(callback.ID, "Не удалось построить график")
+		return
+	}
+
+	h.answerCallback(callback.ID, "")
+
+	photo := tgbotapi.NewPhoto(callback.Message.Chat.ID, tgbotapi.FileBytes{
+		Name:  "volume.png",
+		Bytes: image.Bytes(),
+	})
+	photo.Caption = fmt.Sprintf("📊 Отжимания по дням за %d дн. Зелёные столбцы — норма выполнена, линия — дневная норма", days)
+
+	if _, err := h.bot.Send(photo); err != nil {
+		log.Printf("Ошибка отправки графика объёма: %v", err)
+	}
+}
@@ -0,0 +1,61 @@
+package hendler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"trackerbot/presenter"
+)
+
+// handleWeeklyGoal обрабатывает /week:
+//
+//	/week             — показать текущий режим
+//	/week 500 [4]     — недельная цель 500 (и 4 тренировочных дня)
+//	/week off         — вернуться к дневной норме
+func (h *BotHandler) handleWeeklyGoal(ctx context.Context, userID int64, chatID int64, args string) {
+	fields := strings.Fields(args)
+
+	switch {
+	case len(fields) == 0:
+		goal, err := h.service.GetWeeklyGoal(ctx, userID)
+		if err != nil {
+			log.Printf("GetWeeklyGoal error: %v", err)
+			h.sendError(chatID)
+			return
+		}
+		h.sendMarkdownMessage(chatID, presenter.FormatWeeklyGoal(goal), nil)
+
+	case fields[0] == "off":
+		if err := h.service.DisableWeeklyGoal(ctx, userID); err != nil {
+			log.Printf("DisableWeeklyGoal error: %v", err)
+			h.sendError(chatID)
+			return
+		}
+		h.sendMessage(chatID, "✅ Вы снова в режиме дневной нормы", nil)
+
+	default:
+		volume, err := strconv.Atoi(fields[0])
+		if err != nil {
+			h.sendMarkdownMessage(chatID, "Использование: <code>/week 500 4</code> или <code>/week off</code>", nil)
+			return
+		}
+
+		days := 0
+		if len(fields) > 1 {
+			if days, err = strconv.Atoi(fields[1]); err != nil {
+				h.sendMessage(chatID, "Число тренировочных дней должно быть числом от 0 до 7", nil)
+				return
+			}
+		}
+
+		goal, err := h.service.SetWeeklyGoal(ctx, userID, volume, days)
+		if err != nil {
+			h.sendMessage(chatID, fmt.Sprintf("❌ %v", err), nil)
+			return
+		}
+		h.sendMarkdownMessage(chatID, presenter.FormatWeeklyGoal(goal), nil)
+	}
+}
@@ -0,0 +1,34 @@
+package hendler
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	ui "trackerbot/keyboard"
+	"trackerbot/presenter"
+)
+
+// handleStartWorkout начинает тренировку
+func (h *BotHandler) handleStartWorkout(ctx context.Context, userID int64, chatID int64) {
+	session, err := h.service.StartWorkout(ctx, userID)
+	if err != nil {
+		log.Printf("StartWorkout error: %v", err)
+		h.sendMessage(chatID, fmt.Sprintf("❌ %v", err), ui.MainKeyboard())
+		return
+	}
+
+	h.sendMessage(chatID, presenter.FormatWorkoutStarted(session), ui.MainKeyboard())
+}
+
+// handleFinishWorkout завершает тренировку и показывает её итоги
+func (h *BotHandler) handleFinishWorkout(ctx context.Context, userID int64, chatID int64) {
+	summary, err := h.service.FinishWorkout(ctx, userID)
+	if err != nil {
+		log.Printf("FinishWorkout error: %v", err)
+		h.sendMessage(chatID, fmt.Sprintf("❌ %v", err), ui.MainKeyboard())
+		return
+	}
+
+	h.sendMarkdownMessage(chatID, presenter.FormatWorkoutSummary(summary), ui.MainKeyboard())
+}
@@ -1,14 +1,26 @@
 package keyboard
 
-import tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+import (
+	"fmt"
 
-// MainKeyboard - основная клавиатура с двумя кнопками
+	"trackerbot/model"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// MainKeyboard - основная клавиатура
 func MainKeyboard() tgbotapi.ReplyKeyboardMarkup {
 	return tgbotapi.NewReplyKeyboard(
 		tgbotapi.NewKeyboardButtonRow(
 			tgbotapi.NewKeyboardButton("➕ Добавить отжимания"),
+			tgbotapi.NewKeyboardButton("📋 План на сегодня"),
 		),
 		tgbotapi.NewKeyboardButtonRow(
+			tgbotapi.NewKeyboardButton("▶️ Начать тренировку"),
+			tgbotapi.NewKeyboardButton("⏹ Завершить"),
+		),
+		tgbotapi.NewKeyboardButtonRow(
+			tgbotapi.NewKeyboardButton("🏋️ Упражнения"),
 			tgbotapi.NewKeyboardButton("⚙️ Дополнительно"),
 		),
 	)
@@ -23,11 +35,22 @@ func SettingsKeyboard() tgbotapi.ReplyKeyboardMarkup {
 		),
 		tgbotapi.NewKeyboardButtonRow(
 			tgbotapi.NewKeyboardButton("📝 Установить норму"),
+			tgbotapi.NewKeyboardButton("🧮 Стратегия нормы"),
+		),
+		tgbotapi.NewKeyboardButtonRow(
+			tgbotapi.NewKeyboardButton("📅 Недельная цель"),
+		),
+		tgbotapi.NewKeyboardButtonRow(
 			tgbotapi.NewKeyboardButton("📈 Мой прогресс"),
+			tgbotapi.NewKeyboardButton("📊 Статистика"),
 		),
 		tgbotapi.NewKeyboardButtonRow(
 			tgbotapi.NewKeyboardButton("📖 Инфо"),
-			tgbotapi.NewKeyboardButton("📊 Статистика"),
+			tgbotapi.NewKeyboardButton("🏅 Достижения"),
+		),
+		tgbotapi.NewKeyboardButtonRow(
+			tgbotapi.NewKeyboardButton("🗓 Программы"),
+			tgbotapi.NewKeyboardButton("🌴 Отдых"),
 		),
 		tgbotapi.NewKeyboardButtonRow(
 			tgbotapi.NewKeyboardButton("⬅️ Назад"),
@@ -43,3 +66,335 @@ func CancelInlineKeyboard() tgbotapi.InlineKeyboardMarkup {
 		),
 	)
 }
+
+// ConfirmPushupsInlineKeyboard - подтверждение подозрительной записи
+func ConfirmPushupsInlineKeyboard(confirmationID string) tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("✅ Всё верно", "confirm_pushups:"+confirmationID),
+			tgbotapi.NewInlineKeyboardButtonData("❌ Отменить", "cancel_pushups:"+confirmationID),
+		),
+	)
+}
+
+// ReviewFlagInlineKeyboard - решение админа по подозрительной записи
+func ReviewFlagInlineKeyboard(flagID int64) tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("✅ Оставить", fmt.Sprintf("flag_approve:%d", flagID)),
+			tgbotapi.NewInlineKeyboardButtonData("🚫 Исключить", fmt.Sprintf("flag_exclude:%d", flagID)),
+		),
+	)
+}
+
+// ExercisePickerInlineKeyboard - выбор упражнения из каталога
+func ExercisePickerInlineKeyboard(exercises []model.Exercise) tgbotapi.InlineKeyboardMarkup {
+	var rows [][]tgbotapi.InlineKeyboardButton
+
+	for i := 0; i < len(exercises); i += 2 {
+		row := tgbotapi.NewInlineKeyboardRow(exerciseButton(exercises[i]))
+		if i+1 < len(exercises) {
+			row = append(row, exerciseButton(exercises[i+1]))
+		}
+		rows = append(rows, row)
+	}
+
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+func exerciseButton(exercise model.Exercise) tgbotapi.InlineKeyboardButton {
+	return tgbotapi.NewInlineKeyboardButtonData(
+		exercise.Emoji+" "+exercise.Name,
+		"exercise:"+exercise.Code,
+	)
+}
+
+// ExerciseActionsInlineKeyboard - действия с выбранным упражнением
+func ExerciseActionsInlineKeyboard(code string) tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("➕ Добавить", "exercise_add:"+code),
+			tgbotapi.NewInlineKeyboardButtonData("🎯 Тест максимума", "exercise_max:"+code),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("📝 Норма", "exercise_norm:"+code),
+			tgbotapi.NewInlineKeyboardButtonData("📈 Прогресс", "exercise_progress:"+code),
+		),
+	)
+}
+
+// VariantCancelInlineKeyboard - выбор варианта отжиманий и отмена ввода.
+// Выбранный вариант отмечается галочкой.
+func VariantCancelInlineKeyboard(variants []model.PushupVariant, selected string) tgbotapi.InlineKeyboardMarkup {
+	var rows [][]tgbotapi.InlineKeyboardButton
+
+	for i := 0; i < len(variants); i += 2 {
+		row := tgbotapi.NewInlineKeyboardRow(variantButton(variants[i], selected))
+		if i+1 < len(variants) {
+			row = append(row, variantButton(variants[i+1], selected))
+		}
+		rows = append(rows, row)
+	}
+
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("❌ Отменить", "cancel_input"),
+	))
+
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+func variantButton(variant model.PushupVariant, selected string) tgbotapi.InlineKeyboardButton {
+	text := variant.Name
+	if variant.Code == selected {
+		text = "✅ " + text
+	}
+	return tgbotapi.NewInlineKeyboardButtonData(text, "variant:"+variant.Code)
+}
+
+// MaxTestReminderInlineKeyboard - кнопка для перехода к тесту максимума из напоминания
+func MaxTestReminderInlineKeyboard() tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🎯 Пройти тест", "start_max_test"),
+		),
+	)
+}
+
+// NormStrategyInlineKeyboard - выбор стратегии расчёта нормы
+func NormStrategyInlineKeyboard(strategies []model.NormStrategyInfo, current string, autoNorm bool) tgbotapi.InlineKeyboardMarkup {
+	var rows [][]tgbotapi.InlineKeyboardButton
+
+	for _, strategy := range strategies {
+		label := strategy.Name
+		if strategy.Code == current {
+			label = "✅ " + label
+		}
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(label, "norm_strategy:"+strategy.Code),
+		))
+	}
+
+	toggle := tgbotapi.NewInlineKeyboardButtonData("🔄 Включить автоподстройку", "auto_norm:on")
+	if autoNorm {
+		toggle = tgbotapi.NewInlineKeyboardButtonData("⏸ Выключить автоподстройку", "auto_norm:off")
+	}
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(toggle))
+
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// RestInlineKeyboard предлагает взять перерыв или досрочно его закончить
+func RestInlineKeyboard(resting bool) tgbotapi.InlineKeyboardMarkup {
+	if resting {
+		return tgbotapi.NewInlineKeyboardMarkup(
+			tgbotapi.NewInlineKeyboardRow(
+				tgbotapi.NewInlineKeyboardButtonData("▶️ Вернуться к тренировкам", "rest:end"),
+			),
+		)
+	}
+
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("😴 Отдыхаю сегодня", "rest:rest:1"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🤒 3 дня", "rest:vacation:3"),
+			tgbotapi.NewInlineKeyboardButtonData("🏖 Неделя", "rest:vacation:7"),
+			tgbotapi.NewInlineKeyboardButtonData("🏖 2 недели", "rest:vacation:14"),
+		),
+	)
+}
+
+// ProgramCatalogInlineKeyboard - запись на одну из программ тренировок
+func ProgramCatalogInlineKeyboard(programs []model.Program) tgbotapi.InlineKeyboardMarkup {
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for _, program := range programs {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(program.Name, "program:enroll:"+program.Code),
+		))
+	}
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// ProgramWorkoutInlineKeyboard - отметка результата тренировки по программе.
+// Неделя и день в кнопке не дают отметить одну тренировку дважды
+func ProgramWorkoutInlineKeyboard(workout *model.ProgramWorkout) tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("✅ Выполнил все подходы", fmt.Sprintf("program:done:%d:%d", workout.Week, workout.Day)),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("⚠️ Не осилил", fmt.Sprintf("program:fail:%d:%d", workout.Week, workout.Day)),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🚪 Выйти из программы", "program:leave"),
+		),
+	)
+}
+
+// GTGPromptInlineKeyboard - отметка микроподхода из напоминания grease-the-groove.
+// promptID — одноразовый ID напоминания: повторное нажатие не запишет подход дважды
+func GTGPromptInlineKeyboard(promptID string) tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("✅ Сделал", "gtg:done:"+promptID),
+		),
+	)
+}
+
+// RestTimerInlineKeyboard - запуск таймера отдыха после подхода
+func RestTimerInlineKeyboard(seconds int) tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("⏱ Отдых", fmt.Sprintf("rest_timer:%d", seconds)),
+		),
+	)
+}
+
+// RestTimerStopInlineKeyboard - остановка идущего таймера отдыха
+func RestTimerStopInlineKeyboard() tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("⏹ Остановить", "rest_timer:stop"),
+		),
+	)
+}
+
+// MaxTestIntroInlineKeyboard - начало теста максимума после разминки
+func MaxTestIntroInlineKeyboard() tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("✅ Размялся — начать тест", "max_test:go"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("⏳ Отложить", "max_test:later"),
+		),
+	)
+}
+
+// MaxTestRPEInlineKeyboard - оценка тяжести теста (RPE 6–10) или пропуск
+func MaxTestRPEInlineKeyboard(count int) tgbotapi.InlineKeyboardMarkup {
+	row := make([]tgbotapi.InlineKeyboardButton, 0, 5)
+	for rpe := 6; rpe <= 10; rpe++ {
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData(
+			fmt.Sprintf("%d", rpe), fmt.Sprintf("max_test:rpe:%d:%d", count, rpe),
+		))
+	}
+
+	return tgbotapi.NewInlineKeyboardMarkup(
+		row,
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("Пропустить", fmt.Sprintf("max_test:rpe:%d:0", count)),
+		),
+	)
+}
+
+// MaxTestConfirmInlineKeyboard - подтверждение результата, сильно отличающегося от истории
+func MaxTestConfirmInlineKeyboard(count, rpe int) tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("✅ Всё верно, сохранить", fmt.Sprintf("max_test:save:%d:%d", count, rpe)),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("❌ Не сохранять", "max_test:cancel"),
+		),
+	)
+}
+
+// historyRangeButtons — кнопки выбора диапазона истории прогресса
+var historyRangeButtons = []struct {
+	code  string
+	label string
+}{
+	{model.HistoryRangeMonth, "Месяц"},
+	{model.HistoryRangeYear, "Год"},
+	{model.HistoryRangeAll, "Всё время"},
+}
+
+// MaxRepsHistoryInlineKeyboard - исправление записей страницы истории,
+// листание страниц и выбор диапазона
+func MaxRepsHistoryInlineKeyboard(page *model.MaxRepsHistoryPage) tgbotapi.InlineKeyboardMarkup {
+	rows := make([][]tgbotapi.InlineKeyboardButton, 0, len(page.Items)+2)
+	for _, item := range page.Items {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(
+				fmt.Sprintf("✏️ %s → %d", item.Date.Format("02.01.2006"), item.MaxReps),
+				fmt.Sprintf("history:edit:%d", item.ID),
+			),
+			tgbotapi.NewInlineKeyboardButtonData("🗑", fmt.Sprintf("history:delete:%d", item.ID)),
+		))
+	}
+
+	if page.Pages > 1 {
+		var nav []tgbotapi.InlineKeyboardButton
+		if page.Page > 0 {
+			nav = append(nav, tgbotapi.NewInlineKeyboardButtonData(
+				"◀️", fmt.Sprintf("history:page:%s:%d", page.Range, page.Page-1)))
+		}
+		nav = append(nav, tgbotapi.NewInlineKeyboardButtonData(
+			fmt.Sprintf("%d/%d", page.Page+1, page.Pages), "history:noop"))
+		if page.Page < page.Pages-1 {
+			nav = append(nav, tgbotapi.NewInlineKeyboardButtonData(
+				"▶️", fmt.Sprintf("history:page:%s:%d", page.Range, page.Page+1)))
+		}
+		rows = append(rows, nav)
+	}
+
+	ranges := make([]tgbotapi.InlineKeyboardButton, 0, len(historyRangeButtons))
+	for _, button := range historyRangeButtons {
+		label := button.label
+		if button.code == page.Range {
+			label = "✅ " + label
+		}
+		ranges = append(ranges, tgbotapi.NewInlineKeyboardButtonData(label, "history:page:"+button.code+":0"))
+	}
+	rows = append(rows, ranges)
+
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// FullStatInlineKeyboard - графики под статистикой и карточка, которой можно поделиться
+func FullStatInlineKeyboard() tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("📊 Неделя", "volume:7"),
+			tgbotapi.NewInlineKeyboardButtonData("📊 Месяц", "volume:30"),
+			tgbotapi.NewInlineKeyboardButtonData("📊 3 месяца", "volume:90"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🟩 Календарь активности за год", "heatmap"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("📤 Поделиться", "share_card"),
+		),
+	)
+}
+
+// ProgressChartInlineKeyboard - наложение дневной нормы на график прогресса
+func ProgressChartInlineKeyboard() tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("📏 Наложить дневную норму", "history:chart_norm"),
+		),
+	)
+}
+
+// DeleteHistoryEntryInlineKeyboard - подтверждение удаления записи истории
+func DeleteHistoryEntryInlineKeyboard(recordID int64) tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🗑 Удалить", fmt.Sprintf("history:delete_confirm:%d", recordID)),
+			tgbotapi.NewInlineKeyboardButtonData("Отмена", "history:cancel"),
+		),
+	)
+}
+
+// RecalculateNormInlineKeyboard - пересчёт нормы после исправления последнего теста
+func RecalculateNormInlineKeyboard() tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🔄 Пересчитать норму", "history:norm"),
+			tgbotapi.NewInlineKeyboardButtonData("Оставить как есть", "history:cancel"),
+		),
+	)
+}
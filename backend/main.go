@@ -71,16 +71,30 @@ func main() {
 
 	pushupRepo := repository.NewPushupRepository(db.Pool)
 
-
-	pushupService := service.NewPushupService(pushupRepo)
+	pushupService := service.NewPushupService(pushupRepo, service.Config{
+		Variants: cfg.Variants,
+		Ranks:    cfg.Ranks,
+		Norm:     cfg.Norm,
+	})
 
 	botHandler := hendler.NewBotHandler(telegramBot, pushupService)
+	botHandler.SetAnnouncementChat(cfg.Announcements.ChatID)
+
+	go botHandler.RunMaxTestReminders(ctx, cfg.Reminders)
+	go botHandler.RunAutoNorm(ctx, cfg.AutoNorm)
+	go botHandler.RunGTG(ctx, cfg.GTG)
 
 	u := tgbotapi.NewUpdate(0)
 	u.Timeout = 60
 
 	updates := telegramBot.GetUpdatesChan(u)
 
+	// При остановке перестаём получать обновления: канал закроется, и цикл ниже завершится
+	go func() {
+		<-ctx.Done()
+		telegramBot.StopReceivingUpdates()
+	}()
+
 	var wg sync.WaitGroup
 
 	for update := range updates {
@@ -94,8 +108,8 @@ func main() {
 
 	}
 
-	<-ctx.Done()
 	log.Println("Shutting down gracefully...")
 
+	botHandler.StopRestTimers()
 	wg.Wait()
 }
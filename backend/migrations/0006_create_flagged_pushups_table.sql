@@ -0,0 +1,19 @@
+-- migrations/0006_create_flagged_pushups_table.sql
+-- +goose Up
+CREATE TABLE flagged_pushups (
+    flag_id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    date DATE NOT NULL,
+    count INT NOT NULL DEFAULT 0,
+    reason TEXT NOT NULL DEFAULT '',
+    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_flagged_pushups_status ON flagged_pushups(status);
+CREATE INDEX idx_flagged_pushups_user_date ON flagged_pushups(user_id, date);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_flagged_pushups_status;
+DROP INDEX IF EXISTS idx_flagged_pushups_user_date;
+DROP TABLE IF EXISTS flagged_pushups;
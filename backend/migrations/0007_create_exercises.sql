@@ -0,0 +1,75 @@
+-- migrations/0007_create_exercises.sql
+-- +goose Up
+
+-- Каталог упражнений. Отжимания — упражнение по умолчанию:
+-- их максимум и норма по-прежнему хранятся в users
+CREATE TABLE exercises (
+    code VARCHAR(32) PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    emoji VARCHAR(16) NOT NULL DEFAULT '',
+    unit VARCHAR(16) NOT NULL DEFAULT 'reps',
+    norm_factor NUMERIC(5, 2) NOT NULL DEFAULT 3,
+    sort_order INT NOT NULL DEFAULT 0
+);
+
+INSERT INTO exercises (code, name, emoji, unit, norm_factor, sort_order) VALUES
+    ('pushups', 'Отжимания', '💪', 'reps', 0, 1),
+    ('squats', 'Приседания', '🦵', 'reps', 3, 2),
+    ('pullups', 'Подтягивания', '🧗', 'reps', 2.5, 3),
+    ('plank', 'Планка', '🧘', 'seconds', 3, 4);
+
+-- Дневные записи привязываются к упражнению, существующие данные — отжимания
+ALTER TABLE pushups
+ADD COLUMN exercise VARCHAR(32) NOT NULL DEFAULT 'pushups' REFERENCES exercises(code);
+
+ALTER TABLE pushups
+DROP CONSTRAINT IF EXISTS unique_user_date;
+
+ALTER TABLE pushups
+ADD CONSTRAINT unique_user_date_exercise UNIQUE (user_id, date, exercise);
+
+ALTER TABLE max_reps_history
+ADD COLUMN exercise VARCHAR(32) NOT NULL DEFAULT 'pushups' REFERENCES exercises(code);
+
+ALTER TABLE max_reps_history
+DROP CONSTRAINT IF EXISTS max_reps_history_user_id_date_key;
+
+ALTER TABLE max_reps_history
+ADD CONSTRAINT unique_max_reps_user_date_exercise UNIQUE (user_id, date, exercise);
+
+-- Максимум и норма по остальным упражнениям
+CREATE TABLE user_exercises (
+    user_id BIGINT NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    exercise VARCHAR(32) NOT NULL REFERENCES exercises(code),
+    max_reps INT NOT NULL DEFAULT 0,
+    daily_norm INT NOT NULL DEFAULT 0,
+    last_updated_max_reps TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, exercise)
+);
+
+-- +goose Down
+
+DROP TABLE IF EXISTS user_exercises;
+
+DELETE FROM max_reps_history WHERE exercise <> 'pushups';
+DELETE FROM pushups WHERE exercise <> 'pushups';
+
+ALTER TABLE max_reps_history
+DROP CONSTRAINT IF EXISTS unique_max_reps_user_date_exercise;
+
+ALTER TABLE max_reps_history
+ADD CONSTRAINT max_reps_history_user_id_date_key UNIQUE (user_id, date);
+
+ALTER TABLE max_reps_history
+DROP COLUMN IF EXISTS exercise;
+
+ALTER TABLE pushups
+DROP CONSTRAINT IF EXISTS unique_user_date_exercise;
+
+ALTER TABLE pushups
+ADD CONSTRAINT unique_user_date UNIQUE (user_id, date);
+
+ALTER TABLE pushups
+DROP COLUMN IF EXISTS exercise;
+
+DROP TABLE IF EXISTS exercises;
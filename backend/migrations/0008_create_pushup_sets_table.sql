@@ -0,0 +1,26 @@
+-- migrations/0008_create_pushup_sets_table.sql
+-- +goose Up
+
+-- Журнал подходов: вариант отжиманий, фактическое количество
+-- и эквивалент в обычных отжиманиях (он же суммируется в pushups.count)
+CREATE TABLE pushup_sets (
+    set_id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    date DATE NOT NULL,
+    variant VARCHAR(32) NOT NULL DEFAULT 'standard',
+    count INT NOT NULL DEFAULT 0,
+    equivalent INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_pushup_sets_user_date ON pushup_sets(user_id, date);
+
+-- Существующие дневные суммы считаем обычными отжиманиями
+INSERT INTO pushup_sets (user_id, date, variant, count, equivalent)
+SELECT user_id, date, 'standard', count, count
+FROM pushups
+WHERE exercise = 'pushups' AND count > 0;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_pushup_sets_user_date;
+DROP TABLE IF EXISTS pushup_sets;
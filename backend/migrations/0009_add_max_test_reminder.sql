@@ -0,0 +1,10 @@
+-- migrations/0009_add_max_test_reminder.sql
+-- +goose Up
+
+ALTER TABLE users
+ADD COLUMN max_test_reminded_at TIMESTAMP WITH TIME ZONE;
+
+-- +goose Down
+
+ALTER TABLE users
+DROP COLUMN IF EXISTS max_test_reminded_at;
@@ -0,0 +1,19 @@
+-- migrations/0010_create_weekly_targets_table.sql
+-- +goose Up
+
+-- Недельные цели по максимуму за подход: после каждого теста
+-- ставится цель на следующую неделю (week_start — понедельник)
+CREATE TABLE weekly_targets (
+    target_id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    week_start DATE NOT NULL,
+    base_max INT NOT NULL DEFAULT 0,
+    target_max INT NOT NULL DEFAULT 0,
+    achieved_max INT NOT NULL DEFAULT 0,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT unique_weekly_target_user_week UNIQUE (user_id, week_start)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS weekly_targets;
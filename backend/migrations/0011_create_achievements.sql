@@ -0,0 +1,27 @@
+-- migrations/0011_create_achievements.sql
+-- +goose Up
+
+-- Дни выполнения дневной нормы; is_first — пользователь закрыл норму первым за день
+CREATE TABLE norm_completions (
+    user_id BIGINT NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    date DATE NOT NULL,
+    daily_norm INT NOT NULL DEFAULT 0,
+    is_first BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, date)
+);
+
+CREATE INDEX idx_norm_completions_date ON norm_completions(date);
+
+-- Открытые достижения пользователей (каталог описан в коде)
+CREATE TABLE user_achievements (
+    user_id BIGINT NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    code VARCHAR(64) NOT NULL,
+    unlocked_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, code)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS user_achievements;
+DROP INDEX IF EXISTS idx_norm_completions_date;
+DROP TABLE IF EXISTS norm_completions;
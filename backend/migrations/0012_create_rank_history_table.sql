@@ -0,0 +1,19 @@
+-- migrations/0012_create_rank_history_table.sql
+-- +goose Up
+
+-- История смены рангов: повышения и понижения после тестов максимума
+CREATE TABLE rank_history (
+    change_id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    from_rank VARCHAR(100) NOT NULL DEFAULT '',
+    to_rank VARCHAR(100) NOT NULL DEFAULT '',
+    max_reps INT NOT NULL DEFAULT 0,
+    promoted BOOLEAN NOT NULL DEFAULT TRUE,
+    changed_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_rank_history_user ON rank_history(user_id, changed_at);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_rank_history_user;
+DROP TABLE IF EXISTS rank_history;
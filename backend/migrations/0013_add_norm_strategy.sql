@@ -0,0 +1,11 @@
+-- migrations/0013_add_norm_strategy.sql
+-- +goose Up
+
+-- Стратегия расчёта дневной нормы, применяемая после теста максимума
+ALTER TABLE users
+ADD COLUMN norm_strategy VARCHAR(32) NOT NULL DEFAULT 'acsm';
+
+-- +goose Down
+
+ALTER TABLE users
+DROP COLUMN IF EXISTS norm_strategy;
@@ -0,0 +1,28 @@
+-- migrations/0014_create_norm_history.sql
+-- +goose Up
+
+-- Ночная автоподстройка нормы (по желанию пользователя)
+ALTER TABLE users
+ADD COLUMN auto_norm BOOLEAN NOT NULL DEFAULT FALSE,
+ADD COLUMN auto_norm_checked_on DATE;
+
+-- История изменений дневной нормы с причиной
+CREATE TABLE norm_history (
+    change_id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    old_norm INT NOT NULL DEFAULT 0,
+    new_norm INT NOT NULL DEFAULT 0,
+    reason TEXT NOT NULL DEFAULT '',
+    source VARCHAR(16) NOT NULL DEFAULT 'auto',
+    changed_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_norm_history_user ON norm_history(user_id, changed_at);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_norm_history_user;
+DROP TABLE IF EXISTS norm_history;
+
+ALTER TABLE users
+DROP COLUMN IF EXISTS auto_norm_checked_on,
+DROP COLUMN IF EXISTS auto_norm;
@@ -0,0 +1,14 @@
+-- migrations/0015_add_weekly_goal.sql
+-- +goose Up
+
+-- Режим цели: дневная норма (daily) или недельный объём (weekly)
+ALTER TABLE users
+ADD COLUMN goal_mode VARCHAR(16) NOT NULL DEFAULT 'daily',
+ADD COLUMN weekly_goal INT NOT NULL DEFAULT 0,
+ADD COLUMN weekly_days INT NOT NULL DEFAULT 0;
+
+-- +goose Down
+ALTER TABLE users
+DROP COLUMN IF EXISTS weekly_days,
+DROP COLUMN IF EXISTS weekly_goal,
+DROP COLUMN IF EXISTS goal_mode;
@@ -0,0 +1,20 @@
+-- migrations/0016_create_rest_periods.sql
+-- +goose Up
+
+-- Дни отдыха и отпуск/болезнь: не ломают серии, не учитываются в доле выполненных дней
+-- и приостанавливают напоминания
+CREATE TABLE rest_periods (
+    period_id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    kind VARCHAR(16) NOT NULL DEFAULT 'rest',
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CHECK (end_date >= start_date)
+);
+
+CREATE INDEX idx_rest_periods_user ON rest_periods(user_id, end_date);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_rest_periods_user;
+DROP TABLE IF EXISTS rest_periods;
@@ -0,0 +1,33 @@
+-- migrations/0017_create_training_programs.sql
+-- +goose Up
+
+-- Текущая программа тренировок пользователя (не больше одной)
+CREATE TABLE user_programs (
+    user_id BIGINT PRIMARY KEY REFERENCES users(user_id) ON DELETE CASCADE,
+    program_code VARCHAR(32) NOT NULL,
+    level INT NOT NULL DEFAULT 0,
+    week INT NOT NULL DEFAULT 1,
+    day INT NOT NULL DEFAULT 1,
+    failed_in_week BOOLEAN NOT NULL DEFAULT FALSE,
+    started_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    last_session_at TIMESTAMP WITH TIME ZONE
+);
+
+-- История тренировок по программам
+CREATE TABLE program_sessions (
+    session_id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    program_code VARCHAR(32) NOT NULL,
+    week INT NOT NULL,
+    day INT NOT NULL,
+    planned INT NOT NULL DEFAULT 0,
+    completed BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_program_sessions_user ON program_sessions(user_id, created_at);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_program_sessions_user;
+DROP TABLE IF EXISTS program_sessions;
+DROP TABLE IF EXISTS user_programs;
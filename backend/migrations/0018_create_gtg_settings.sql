@@ -0,0 +1,19 @@
+-- migrations/0018_create_gtg_settings.sql
+-- +goose Up
+
+-- Режим grease-the-groove: окно (минуты от полуночи), интервал и размер микроподхода
+CREATE TABLE gtg_settings (
+    user_id BIGINT PRIMARY KEY REFERENCES users(user_id) ON DELETE CASCADE,
+    enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    window_start INT NOT NULL DEFAULT 600,
+    window_end INT NOT NULL DEFAULT 1080,
+    interval_minutes INT NOT NULL DEFAULT 60,
+    set_size INT NOT NULL DEFAULT 0, -- 0 — половина максимума за подход
+    next_prompt_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_gtg_settings_next ON gtg_settings(next_prompt_at) WHERE enabled;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_gtg_settings_next;
+DROP TABLE IF EXISTS gtg_settings;
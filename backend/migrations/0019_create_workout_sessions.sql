@@ -0,0 +1,30 @@
+-- migrations/0019_create_workout_sessions.sql
+-- +goose Up
+
+-- Тренировки: подходы между «Начать» и «Завершить» привязываются к сессии,
+-- итоги сохраняются при завершении
+CREATE TABLE workout_sessions (
+    session_id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    finished_at TIMESTAMP WITH TIME ZONE,
+    sets INT NOT NULL DEFAULT 0,
+    total INT NOT NULL DEFAULT 0,
+    best_set INT NOT NULL DEFAULT 0
+);
+
+-- Незавершённая тренировка у пользователя может быть только одна
+CREATE UNIQUE INDEX idx_workout_sessions_open ON workout_sessions(user_id) WHERE finished_at IS NULL;
+CREATE INDEX idx_workout_sessions_user_finished ON workout_sessions(user_id, finished_at);
+
+ALTER TABLE pushup_sets
+    ADD COLUMN session_id BIGINT REFERENCES workout_sessions(session_id) ON DELETE SET NULL;
+
+CREATE INDEX idx_pushup_sets_session ON pushup_sets(session_id) WHERE session_id IS NOT NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_pushup_sets_session;
+ALTER TABLE pushup_sets DROP COLUMN IF EXISTS session_id;
+DROP INDEX IF EXISTS idx_workout_sessions_user_finished;
+DROP INDEX IF EXISTS idx_workout_sessions_open;
+DROP TABLE IF EXISTS workout_sessions;
@@ -0,0 +1,8 @@
+-- migrations/0020_add_max_test_rpe.sql
+-- +goose Up
+
+-- Субъективная тяжесть теста максимума (RPE 6–10), NULL — не указана
+ALTER TABLE max_reps_history ADD COLUMN rpe SMALLINT;
+
+-- +goose Down
+ALTER TABLE max_reps_history DROP COLUMN IF EXISTS rpe;
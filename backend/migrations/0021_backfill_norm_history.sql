@@ -0,0 +1,56 @@
+-- migrations/0021_backfill_norm_history.sql
+-- +goose Up
+
+-- До этой миграции в norm_history попадала только автоподстройка. Восстанавливаем
+-- прошлые изменения по дням выполнения нормы: если норма отличается от нормы
+-- предыдущего такого дня, значит, она поменялась не позже этой даты.
+-- Восстанавливаем только период до первой уже записанной смены нормы
+INSERT INTO norm_history (user_id, old_norm, new_norm, reason, source, changed_at)
+SELECT
+    t.user_id,
+    t.prev_norm,
+    t.daily_norm,
+    'Восстановлено по дням выполнения нормы',
+    'backfill',
+    t.date::timestamptz
+FROM (
+    SELECT
+        user_id,
+        date,
+        daily_norm,
+        LAG(daily_norm) OVER (PARTITION BY user_id ORDER BY date) AS prev_norm
+    FROM norm_completions
+) AS t
+WHERE t.prev_norm IS NOT NULL
+  AND t.prev_norm <> t.daily_norm
+  AND NOT EXISTS (
+      SELECT 1 FROM norm_history h
+      WHERE h.user_id = t.user_id AND h.changed_at <= t.date::timestamptz
+  );
+
+-- Если норма поменялась уже после последнего дня выполнения, это случилось
+-- не раньше следующего дня — ставим изменение на него
+INSERT INTO norm_history (user_id, old_norm, new_norm, reason, source, changed_at)
+SELECT
+    u.user_id,
+    c.daily_norm,
+    u.daily_norm,
+    'Восстановлено по дням выполнения нормы',
+    'backfill',
+    (c.date + 1)::timestamptz
+FROM users u
+JOIN LATERAL (
+    SELECT date, daily_norm
+    FROM norm_completions
+    WHERE user_id = u.user_id
+    ORDER BY date DESC
+    LIMIT 1
+) AS c ON TRUE
+WHERE c.daily_norm <> u.daily_norm
+  AND NOT EXISTS (
+      SELECT 1 FROM norm_history h
+      WHERE h.user_id = u.user_id AND h.changed_at > c.date::timestamptz
+  );
+
+-- +goose Down
+DELETE FROM norm_history WHERE source = 'backfill';
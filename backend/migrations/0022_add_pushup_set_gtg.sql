@@ -0,0 +1,8 @@
+-- migrations/0022_add_pushup_set_gtg.sql
+-- +goose Up
+
+-- Микроподход из напоминания «смазки» (GTG) — не засчитывается в план подходов на день
+ALTER TABLE pushup_sets ADD COLUMN gtg BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- +goose Down
+ALTER TABLE pushup_sets DROP COLUMN IF EXISTS gtg;
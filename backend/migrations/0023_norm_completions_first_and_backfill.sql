@@ -0,0 +1,69 @@
+-- migrations/0023_norm_completions_first_and_backfill.sql
+-- +goose Up
+
+-- Раньше is_first выставлялся проверкой NOT EXISTS при вставке, и два пользователя,
+-- выполнившие норму одновременно, могли оба стать первыми. Оставляем самого раннего
+UPDATE norm_completions
+SET is_first = FALSE
+WHERE is_first
+  AND (user_id, date) NOT IN (
+      SELECT DISTINCT ON (date) user_id, date
+      FROM norm_completions
+      WHERE is_first
+      ORDER BY date, created_at NULLS LAST, user_id
+  );
+
+-- Первый за день — не больше одного
+CREATE UNIQUE INDEX idx_norm_completions_first ON norm_completions(date) WHERE is_first;
+
+-- Дни до появления norm_completions восстанавливаем по суммам за день:
+-- норма на дату берётся из norm_history (до первой записи — её старое значение,
+-- без истории — текущая норма), исключённый админом объём не засчитывается
+INSERT INTO norm_completions (user_id, date, daily_norm, is_first, created_at)
+SELECT t.user_id, t.date, t.norm, FALSE, t.date::timestamptz
+FROM (
+    SELECT
+        p.user_id,
+        p.date,
+        p.count - COALESCE(ex.excluded, 0) AS total,
+        COALESCE(
+            (SELECT h.new_norm FROM norm_history h
+             WHERE h.user_id = p.user_id AND h.changed_at::date <= p.date
+             ORDER BY h.changed_at DESC LIMIT 1),
+            (SELECT h.old_norm FROM norm_history h
+             WHERE h.user_id = p.user_id
+             ORDER BY h.changed_at LIMIT 1),
+            u.daily_norm
+        ) AS norm
+    FROM pushups p
+    JOIN users u ON u.user_id = p.user_id
+    LEFT JOIN (
+        SELECT user_id, date, SUM(count) AS excluded
+        FROM flagged_pushups
+        WHERE status = 'excluded'
+        GROUP BY user_id, date
+    ) ex ON ex.user_id = p.user_id AND ex.date = p.date
+    WHERE p.exercise = 'pushups'
+      AND p.date < COALESCE((SELECT MIN(date) FROM norm_completions), CURRENT_DATE)
+) AS t
+WHERE t.norm > 0 AND t.total >= t.norm
+ON CONFLICT (user_id, date) DO NOTHING;
+
+-- «Первый за день» для восстановленных дней — тот, кто раньше начал записывать отжимания
+-- (как в GetFirstNormCompleter)
+UPDATE norm_completions c
+SET is_first = TRUE
+FROM (
+    SELECT DISTINCT ON (nc.date) nc.user_id, nc.date
+    FROM norm_completions nc
+    JOIN pushups p ON p.user_id = nc.user_id AND p.date = nc.date AND p.exercise = 'pushups'
+    WHERE NOT EXISTS (
+        SELECT 1 FROM norm_completions f WHERE f.date = nc.date AND f.is_first
+    )
+    ORDER BY nc.date, p.record_id
+) AS earliest
+WHERE c.user_id = earliest.user_id AND c.date = earliest.date;
+
+-- +goose Down
+-- Восстановленные дни неотличимы от записанных ботом и остаются
+DROP INDEX IF EXISTS idx_norm_completions_first;
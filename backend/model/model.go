@@ -1,6 +1,9 @@
 package model
 
-import "time"
+import (
+	"errors"
+	"time"
+)
 
 type AddPushupsViewModel struct {
 	AddedCount int
@@ -9,6 +12,17 @@ type AddPushupsViewModel struct {
 	Completed  bool
 	HasLeader  bool
 	Leader     string
+
+	NeedsConfirmation bool
+	Reason            string
+
+	Exercise   *Exercise
+	Variant    *PushupVariant
+	Equivalent int
+
+	NewAchievements []Achievement
+	Weekly          *WeeklyProgress
+	Plan            *DailyPlan
 }
 
 type MaxRepsViewModel struct {
@@ -18,6 +32,17 @@ type MaxRepsViewModel struct {
 	RepsToNext int
 	History    []MaxRepsHistoryItem
 	Record     *MaxRepsHistoryItem
+	Exercise   *Exercise
+
+	// Недельные цели
+	NextTarget   *WeeklyTarget
+	HitTarget    *WeeklyTarget
+	TargetStreak int
+
+	NewAchievements []Achievement
+	RankChange      *RankChange
+	NormStrategy    string
+	RPE             int // Субъективная тяжесть теста (0 — не указана)
 }
 
 type FullStatViewModel struct {
@@ -26,9 +51,12 @@ type FullStatViewModel struct {
 	DailyNorm        int
 	FirstWorkoutDate *time.Time
 	Leaderboard      []LeaderboardItem
+	VariantTotals    []VariantTotal
+	Weekly           *WeeklyProgress
 }
 
 type MaxRepsHistoryItem struct {
+	ID      int64
 	Date    time.Time
 	MaxReps int
 }
@@ -39,3 +67,462 @@ type LeaderboardItem struct {
 	Count    int
 }
 
+// Статусы подозрительных записей
+const (
+	FlagStatusPending  = "pending"
+	FlagStatusApproved = "approved"
+	FlagStatusExcluded = "excluded"
+)
+
+type FlaggedEntry struct {
+	ID       int64
+	UserID   int64
+	Username string
+	Date     time.Time
+	Count    int
+	Reason   string
+	Status   string
+}
+
+// Упражнения и единицы измерения
+const (
+	ExercisePushups = "pushups"
+
+	UnitReps    = "reps"
+	UnitSeconds = "seconds"
+)
+
+type Exercise struct {
+	Code       string
+	Name       string
+	Emoji      string
+	Unit       string
+	NormFactor float64
+}
+
+// IsTimed сообщает, измеряется ли упражнение во времени (секундах), а не в повторениях
+func (e Exercise) IsTimed() bool {
+	return e.Unit == UnitSeconds
+}
+
+type UserExercise struct {
+	Exercise  Exercise
+	MaxReps   int
+	DailyNorm int
+}
+
+// Варианты отжиманий
+const VariantStandard = "standard"
+
+type PushupVariant struct {
+	Code        string
+	Name        string
+	Coefficient float64
+}
+
+type VariantTotal struct {
+	Code       string
+	Name       string
+	Count      int
+	Equivalent int
+}
+
+type DailyTotal struct {
+	Date  time.Time
+	Count int
+}
+
+type MaxTestReminder struct {
+	UserID      int64
+	MaxReps     int
+	DailyNorm   int
+	LastMaxTest time.Time
+}
+
+// Статусы недельных целей
+const (
+	TargetStatusPending = "pending"
+	TargetStatusHit     = "hit"
+	TargetStatusMissed  = "missed"
+)
+
+type WeeklyTarget struct {
+	ID          int64
+	WeekStart   time.Time
+	BaseMax     int
+	TargetMax   int
+	AchievedMax int
+	Status      string
+}
+
+type WeeklyTargetSummary struct {
+	Current    *WeeklyTarget
+	Next       *WeeklyTarget
+	Resolved   []WeeklyTarget
+	Hits       int
+	Misses     int
+	Streak     int
+	BestStreak int
+}
+
+type Achievement struct {
+	Code        string
+	Emoji       string
+	Name        string
+	Description string
+}
+
+type UserAchievement struct {
+	Code       string
+	UnlockedAt time.Time
+}
+
+// AchievementStats — показатели пользователя, по которым проверяются достижения
+type AchievementStats struct {
+	TotalPushups     int
+	NormStreak       int
+	WeekStreak       int // Недель подряд с выполненной недельной целью (0 в режиме дневной нормы)
+	FirstCompletions int
+	MonthlyMaxGain   int
+	MaxReps          int
+}
+
+type AchievementStatus struct {
+	Achievement
+	Unlocked   bool
+	UnlockedAt time.Time
+	Progress   int
+	Goal       int
+}
+
+type RankChange struct {
+	Date     time.Time
+	FromRank string
+	ToRank   string
+	MaxReps  int
+	Promoted bool
+	Username string
+}
+
+// RankInfo — ступень лестницы рангов: порог максимума за подход,
+// название и рекомендуемая частота тренировок
+type RankInfo struct {
+	Threshold int
+	Emoji     string
+	Name      string
+	Frequency string
+}
+
+// Title возвращает название ранга вместе с эмодзи
+func (r RankInfo) Title() string {
+	if r.Emoji == "" {
+		return r.Name
+	}
+	return r.Emoji + " " + r.Name
+}
+
+type NormStrategyInfo struct {
+	Code        string
+	Name        string
+	Description string
+}
+
+// Источники изменения нормы
+const (
+	NormSourceAuto     = "auto"     // Ночная автоподстройка
+	NormSourceManual   = "manual"   // Пользователь ввёл норму сам
+	NormSourceMaxTest  = "max_test" // Пересчёт после теста максимума
+	NormSourceHistory  = "history"  // Пересчёт после правки истории тестов
+	NormSourceReset    = "reset"    // Сброс на значение по умолчанию
+	NormSourceBackfill = "backfill" // Восстановлено миграцией по дням выполнения нормы
+)
+
+type NormAdjustment struct {
+	UserID        int64
+	OldNorm       int
+	NewNorm       int
+	Reason        string
+	CompletedDays int
+	WindowDays    int
+}
+
+// NormChange — запись истории изменений дневной нормы
+type NormChange struct {
+	ChangedAt time.Time
+	OldNorm   int
+	NewNorm   int
+}
+
+// NormPoint — дневная норма, действующая с указанной даты
+type NormPoint struct {
+	Date time.Time
+	Norm int
+}
+
+type AutoNormCandidate struct {
+	UserID    int64
+	DailyNorm int
+}
+
+// Режимы цели пользователя
+const (
+	GoalModeDaily  = "daily"
+	GoalModeWeekly = "weekly"
+)
+
+type WeeklyGoal struct {
+	Mode   string
+	Volume int // Объём за ISO-неделю
+	Days   int // Желаемое число тренировочных дней (0 — не важно)
+}
+
+// IsWeekly сообщает, что пользователь выбрал недельную цель вместо дневной нормы
+func (g WeeklyGoal) IsWeekly() bool {
+	return g.Mode == GoalModeWeekly && g.Volume > 0
+}
+
+type WeeklyProgress struct {
+	WeekStart     time.Time
+	Total         int
+	Volume        int
+	TrainingDays  int
+	TargetDays    int
+	RestDays      int // Дни отдыха на этой неделе (цель уже уменьшена на них)
+	DaysLeft      int
+	Completed     bool
+	JustCompleted bool
+	Streak        int // Недель подряд с выполненной целью
+}
+
+// Виды перерывов в тренировках
+const (
+	RestKindDay      = "rest"
+	RestKindVacation = "vacation"
+)
+
+type RestPeriod struct {
+	ID        int64
+	Kind      string
+	StartDate time.Time
+	EndDate   time.Time // Последний день перерыва включительно
+}
+
+// Covers сообщает, попадает ли день date в перерыв
+func (p RestPeriod) Covers(date time.Time) bool {
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	start := time.Date(p.StartDate.Year(), p.StartDate.Month(), p.StartDate.Day(), 0, 0, 0, 0, time.UTC)
+	end := time.Date(p.EndDate.Year(), p.EndDate.Month(), p.EndDate.Day(), 0, 0, 0, 0, time.UTC)
+	return !day.Before(start) && !day.After(end)
+}
+
+type PlannedSet struct {
+	Reps int
+	Done bool
+}
+
+// LoggedSet — записанный за день подход из журнала pushup_sets
+type LoggedSet struct {
+	Variant string
+	Count   int
+	GTG     bool // Микроподход из напоминания GTG
+}
+
+// DailyPlan — разбивка дневной нормы на рекомендуемые подходы
+type DailyPlan struct {
+	DailyNorm int
+	MaxReps   int
+	SetSize   int // Рекомендуемый размер подхода (ACSMIntensityRatio от максимума)
+	Sets      []PlannedSet
+	Total     int // Сделано за сегодня
+	Remaining []int
+}
+
+// DoneSets возвращает число выполненных подходов плана
+func (p DailyPlan) DoneSets() int {
+	done := 0
+	for _, set := range p.Sets {
+		if set.Done {
+			done++
+		}
+	}
+	return done
+}
+
+// ProgramWeek — тренировки недели для каждого уровня: Levels[уровень][день] = подходы
+type ProgramWeek struct {
+	Levels [][][]int
+}
+
+type Program struct {
+	Code        string
+	Name        string
+	Description string
+	Placement   []int // Верхние границы максимума для уровней; выше последней — последний уровень
+	LastSetMax  bool  // Последний подход — «максимум, но не меньше указанного»
+	Weeks       []ProgramWeek
+}
+
+type ProgramEnrollment struct {
+	ProgramCode   string
+	Level         int
+	Week          int // Нумерация с 1
+	Day           int // Нумерация с 1
+	FailedInWeek  bool
+	StartedAt     time.Time
+	LastSessionAt *time.Time
+}
+
+type ProgramWorkout struct {
+	ProgramCode string
+	ProgramName string
+	Level       int
+	Week        int
+	Day         int
+	TotalWeeks  int
+	DaysInWeek  int
+	Sets        []int
+	LastSetMax  bool
+	RestUntil   *time.Time // До этого времени лучше отдохнуть после прошлой тренировки
+}
+
+type ProgramSession struct {
+	ProgramCode string
+	Week        int
+	Day         int
+	Planned     int
+	Completed   bool
+}
+
+// ErrProgramSessionOutdated — отмечают не текущую тренировку программы
+// (повторное нажатие или кнопка из старого сообщения)
+var ErrProgramSessionOutdated = errors.New("эта тренировка уже отмечена")
+
+// Итоги тренировки по программе
+const (
+	ProgramOutcomeNextDay    = "next_day"
+	ProgramOutcomeNextWeek   = "next_week"
+	ProgramOutcomeRepeatWeek = "repeat_week"
+	ProgramOutcomeFinished   = "finished"
+)
+
+type ProgramSessionResult struct {
+	ProgramName string
+	Completed   bool
+	Outcome     string
+	Next        *ProgramWorkout // nil, если программа завершена
+}
+
+type GTGSettings struct {
+	Enabled         bool
+	WindowStart     int // Начало окна, минуты от полуночи
+	WindowEnd       int // Конец окна, минуты от полуночи
+	IntervalMinutes int
+	SetSize         int // 0 — половина максимума за подход
+	NextPromptAt    *time.Time
+}
+
+type GTGPrompt struct {
+	UserID   int64
+	Settings GTGSettings
+	MaxReps  int
+	SetSize  int // Размер подхода для этого напоминания
+}
+
+// WorkoutSessionTimeout — через сколько после начала незавершённая тренировка считается забытой:
+// новые подходы к ней не привязываются, а сама она закрывается на последнем подходе
+const WorkoutSessionTimeout = 4 * time.Hour
+
+type WorkoutSession struct {
+	ID         int64
+	StartedAt  time.Time
+	FinishedAt *time.Time // nil, пока тренировка идёт
+	LastSetAt  *time.Time // Время последнего подхода идущей тренировки (nil — подходов нет)
+	Sets       int
+	Total      int
+	BestSet    int
+}
+
+type WorkoutSummary struct {
+	Session    WorkoutSession
+	Duration   time.Duration
+	Average    float64         // Средний подход
+	Previous   *WorkoutSession // Прошлая тренировка для сравнения (nil, если это первая)
+	AutoClosed bool            // Тренировку забыли завершить — закрыта по WorkoutSessionTimeout
+}
+
+type MaxTestReadiness struct {
+	MaxReps        int
+	DailyNorm      int
+	LastTestAt     *time.Time
+	RecentVolume   int  // Объём за последние RecoveryHours
+	TrainedToday   int  // Объём за сегодня
+	Recovered      bool // За RecoveryHours не было дня с выполненной нормой
+	TestedRecently bool // Прошлый тест был меньше RecoveryHours назад
+}
+
+// Ready сообщает, что ничего не мешает показать честный максимум
+func (r MaxTestReadiness) Ready() bool {
+	return r.Recovered && !r.TestedRecently && r.TrainedToday == 0
+}
+
+type MaxTestCheck struct {
+	Count    int
+	Previous int  // Обычный результат: медиана последних тестов (или текущий максимум, если истории нет)
+	Tests    int  // По скольким последним тестам посчитан Previous (0 — по текущему максимуму)
+	Deviates bool // Результат сильно отличается от истории — нужно подтверждение
+}
+
+type MaxRepsHistoryChange struct {
+	History       []MaxRepsHistoryItem // История после изменения
+	PrevMaxReps   int
+	MaxReps       int  // Текущий максимум по последней записи (0, если история пуста)
+	Rank          string
+	LatestChanged bool // Изменилась последняя запись — стоит пересчитать норму
+	RankChange    *RankChange
+}
+
+// Диапазоны истории прогресса
+const (
+	HistoryRangeMonth = "month"
+	HistoryRangeYear  = "year"
+	HistoryRangeAll   = "all"
+)
+
+// MaxRepsHistoryPage — страница истории теста максимума в выбранном диапазоне
+type MaxRepsHistoryPage struct {
+	Items  []MaxRepsHistoryItem // Записи страницы, новые первыми
+	Range  string
+	Page   int // Номер страницы с нуля
+	Pages  int
+	Offset int // Сколько записей диапазона новее первой записи страницы
+	Total  int // Записей в диапазоне
+	First  int // Первый результат в диапазоне
+	Last   int // Последний результат в диапазоне
+}
+
+// DailyVolume — объём за день на графике и норма, действовавшая в этот день
+type DailyVolume struct {
+	Date      time.Time
+	Count     int
+	Norm      int  // 0 — день отдыха или норма не задана
+	Completed bool // Норма выполнена
+}
+
+// ActivityHeatmap — календарь активности за год с итогами
+type ActivityHeatmap struct {
+	Days          []DailyVolume // С понедельника первой недели по сегодня
+	Total         int
+	ActiveDays    int // Дней с отжиманиями
+	CompletedDays int // Дней с выполненной нормой
+	Streak        int // Текущая серия выполнения нормы
+}
+
+// StatsCard — данные карточки статистики, которой делятся в других чатах
+type StatsCard struct {
+	Username     string
+	Rank         string // Название ранга без эмодзи — шрифт карточки их не рисует
+	MaxReps      int
+	TotalAllTime int
+	Streak       int   // Текущая серия выполнения нормы
+	Sparkline    []int // Отжимания по дням за последние StatsCardDays дней, старые первыми
+}
@@ -2,7 +2,9 @@ package presenter
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
+	"time"
 
 	"trackerbot/model"
 )
@@ -34,7 +36,7 @@ func FormatWelcomeMessage(maxReps int) string {
 Выберите действие ниже 👇`
 }
 
-func FormatInfoMessage() string {
+func FormatInfoMessage(ranks []model.RankInfo) string {
 	return `🤖 <b>Инструкция по использованию PushUpper</b>
 
 🎯 <b>Основные функции</b>
@@ -44,11 +46,24 @@ func FormatInfoMessage() string {
 Показывает текущий прогресс выполнения дневной нормы
 Участвуйте в соревновании — кто первый выполнит норму сегодня
 
+<b>⏱ Таймер отдыха</b> (/rest 90)
+Кнопка «⏱ Отдых» под записью подхода запускает обратный отсчёт
+Когда отдых закончится, бот пришлёт уведомление
+
+<b>▶️ Начать тренировку / ⏹ Завершить</b>
+Подходы между началом и завершением собираются в одну тренировку
+Итоги: длительность, объём, средний и лучший подход, сравнение с прошлой тренировкой
+
+<b>📋 План на сегодня</b>
+Дневная норма, разбитая на подходы по ~70% от вашего максимума
+Сделанные подходы отмечаются автоматически, остаток пересчитывается после каждой записи
+
 <b>⚙️ Дополнительное меню</b>
 Настройки, статистика и прогресс
 
 <b>🎯 Тест максимальных отжиманий</b>
 Определите ваш рекорд в одном подходе
+Бот проверит готовность, проведёт разминку и спросит, насколько было тяжело
 На основе результата устанавливается персональная дневная норма
 Получите свой ранг силы и увидите прогресс до следующего уровня
 Рекомендуется обновлять каждые 1–2 недели
@@ -58,65 +73,67 @@ func FormatInfoMessage() string {
 Сегодня — ваш прогресс и процент выполнения нормы
 Общая — сумма всех отжиманий за всё время
 Рейтинг — таблица лидеров среди всех пользователей
+График — отжимания по дням за неделю, месяц или 3 месяца на фоне нормы
 
 <b>📈 Мой прогресс</b>
-График и список всех ваших рекордов за подход
+График по датам с рекордом, порогами рангов и (по кнопке) дневной нормой
+Список всех ваших результатов за подход
 Отслеживайте динамику роста силы
+Листайте историю ◀️/▶️ и выбирайте период: месяц, год или всё время
+Ошибочный результат можно исправить ✏️ или удалить 🗑
 
 <b>📝 Установить норму</b>
 Ручная установка индивидуальной дневной нормы
 Полезно если хотите тренироваться по собственному плану
 
-💡 <b>Советы по использованию</b>
-
-1. Начните с теста — определите свой текущий уровень
-2. Регулярно добавляйте отжимания — даже небольшие подходы
-3. Обновляйте рекорд раз в неделю
-4. Следите за прогрессом через историю и графики
-
-🚀 <b>Начните сейчас с кнопки «🎯 Тест максимальных отжиманий»</b>
-
-📅 <b>Рекомендованная частота тренировок по рангу</b>
+<b>🧮 Стратегия нормы</b>
+Выберите, как рассчитывать норму после теста максимума
 
-<i>Формат: количество отжиманий за один подход → ваш ранг → рекомендуемое число тренировок в неделю</i>
+<b>🏅 Достижения</b>
+Награды за объём, серии выполнения нормы и рекорды
+Закрытые достижения показывают, сколько осталось до цели
 
-<b>1–4</b>  💤 <b>Сонная муха</b>  
-<i>1–2 тренировки в неделю</i>
+<b>🗓 Программы</b>
+Готовые планы на несколько недель (например, «100 отжиманий за 6 недель») с уровнем по тесту максимума
+Тренировка на сегодня, отметка результата, переход на следующую неделю или повтор недели
 
-<b>5–9</b>  🌱 <b>Росток силы</b>  
-<i>2–3 тренировки в неделю</i>
+<b>🌴 Отдых</b>
+День отдыха или отпуск/болезнь с автоматической датой окончания
+Напоминания на паузе, серии не прерываются
 
-<b>10–14</b>  🐜 <b>Трудяга</b>  
-<i>2–3 тренировки в неделю</i>
+<b>📅 Недельная цель</b>
+Вместо дневной нормы — объём за неделю и, по желанию, число тренировочных дней
+Дни отдыха не считаются пропусками, серии считаются по неделям
 
-<b>15–19</b>  🚀 <b>Стажёр космоса</b>  
-<i>3 тренировки в неделю</i>
+<b>🔁 Grease the groove</b> (/gtg)
+Напоминания о лёгких подходах в течение дня — в своём окне и с интервалом
+Кнопка «✅ Сделал» сразу записывает подход
 
-<b>20–24</b>  🚀 <b>Ракета-носитель</b>  
-<i>3–4 тренировки в неделю</i>
+<b>📤 Поделиться</b> (/card)
+Карточка с рангом, максимумом, суммой, серией и мини-графиком за месяц
+Перешлите её в любой чат
 
-<b>25–29</b>  ⚔️ <b>Рыцарь света</b>  
-<i>3–4 тренировки в неделю</i>
+<b>💬 Inline-режим</b>
+Наберите @PushUpTracker_bot в любом чате: сегодня, рекорд, карточка или рейтинг
 
-<b>30–39</b>  🛡️ <b>Непробиваемый</b>  
-<i>3–4 тренировки в неделю</i>
+<b>🟩 Календарь активности</b> (/heatmap)
+Год тренировок одной картинкой: чем темнее клетка, тем ближе день к норме и выше
+Итоги года и текущая серия — удобно переслать друзьям
 
-<b>40–49</b>  ⚡ <b>Гроза пола</b>  
-<i>4–5 тренировок в неделю</i>
+💡 <b>Советы по использованию</b>
 
-<b>50–64</b>  🏹 <b>Адепт упорства</b>  
-<i>4–5 тренировок в неделю</i>
+1. Начните с теста — определите свой текущий уровень
+2. Регулярно добавляйте отжимания — даже небольшие подходы
+3. Обновляйте рекорд раз в неделю
+4. Следите за прогрессом через историю и графики
 
-<b>65–79</b>  🌌 <b>Победитель гравитации</b>  
-<i>4–6 тренировок в неделю</i>
+🚀 <b>Начните сейчас с кнопки «🎯 Тест максимальных отжиманий»</b>
 
-<b>80–99</b>  🏆 <b>Легенда горизонтов</b>  
-<i>4–6 тренировок в неделю</i>
+📅 <b>Рекомендованная частота тренировок по рангу</b>
 
-<b>100+</b>  🌟 <b>ВЛАСТЕЛИН ОТЖИМАНИЙ</b>  
-<i>5–6 тренировок в неделю</i>
+<i>Формат: количество отжиманий за один подход → ваш ранг → рекомендуемое число тренировок в неделю</i>
 
-⚠️ <i>6–7 раз в неделю допустимо только при хорошем восстановлении и без боли в суставах</i>`
+` + FormatRankFrequencyTable(ranks) + `⚠️ <i>6–7 раз в неделю допустимо только при хорошем восстановлении и без боли в суставах</i>`
 }
 
 func FormatFullStat(vm *model.FullStatViewModel) string {
@@ -132,12 +149,22 @@ func FormatFullStat(vm *model.FullStatViewModel) string {
 			FormatTimesWord(vm.TodayTotal),
 		)
 
-		_, _ = fmt.Fprintf(
-			&builder,
-			"Твоя дневная норма: %s\n%s\n\n",
-			FormatTimesWord(vm.DailyNorm),
-			GenerateProgressBar(vm.TodayTotal, vm.DailyNorm, 10),
-		)
+		if vm.Weekly == nil {
+			_, _ = fmt.Fprintf(
+				&builder,
+				"Твоя дневная норма: %s\n%s\n\n",
+				FormatTimesWord(vm.DailyNorm),
+				GenerateProgressBar(vm.TodayTotal, vm.DailyNorm, 10),
+			)
+		} else {
+			_, _ = builder.WriteString("\n")
+		}
+	}
+
+	// --- Недельная цель ---
+	if vm.Weekly != nil {
+		_, _ = builder.WriteString(FormatWeeklyProgress(vm.Weekly))
+		_, _ = builder.WriteString("\n")
 	}
 
 	// --- За всё время ---
@@ -159,6 +186,26 @@ func FormatFullStat(vm *model.FullStatViewModel) string {
 		_, _ = builder.WriteString("Ты ещё не начинал тренироваться\n\n")
 	}
 
+	// --- По вариантам ---
+	if hasNonStandardVariants(vm.VariantTotals) {
+		_, _ = builder.WriteString("🔀 По вариантам (в обычных отжиманиях):\n")
+
+		for _, item := range vm.VariantTotals {
+			if item.Code == model.VariantStandard {
+				_, _ = fmt.Fprintf(&builder, "• %s: %d\n", item.Name, item.Count)
+				continue
+			}
+
+			_, _ = fmt.Fprintf(
+				&builder, "• %s: %d (≈ %d)\n",
+				item.Name,
+				item.Count,
+				item.Equivalent,
+			)
+		}
+		_, _ = builder.WriteString("\n")
+	}
+
 	// --- Лидерборд ---
 	if len(vm.Leaderboard) > 0 {
 		_, _ = builder.WriteString("🏆 Статистика за сегодня:\n\n")
@@ -177,22 +224,68 @@ func FormatFullStat(vm *model.FullStatViewModel) string {
 	return builder.String()
 }
 
+// hasNonStandardVariants сообщает, есть ли в статистике что-то кроме обычных отжиманий
+func hasNonStandardVariants(totals []model.VariantTotal) bool {
+	for _, item := range totals {
+		if item.Code != model.VariantStandard {
+			return true
+		}
+	}
+	return false
+}
+
 func FormatAddPushups(vm *model.AddPushupsViewModel) string {
+	if vm.Exercise != nil && vm.Exercise.Code != model.ExercisePushups {
+		return FormatAddExercise(vm)
+	}
 
 	var builder strings.Builder
 
-	_, _ = fmt.Fprintf(
-		&builder, "✅ Добавлено: %d отжиманий!\n📈 Твой прогресс: %d/%d\n",
-		vm.AddedCount,
-		vm.Total,
-		vm.DailyNorm,
-	)
+	// --- Режим недельной цели: дневная норма не показывается ---
+	if vm.Weekly != nil {
+		if vm.Variant != nil && vm.Variant.Code != model.VariantStandard {
+			_, _ = fmt.Fprintf(
+				&builder, "✅ Добавлено: %d отжиманий (%s ≈ %d обычных)!\n📈 Сегодня: %d\n\n",
+				vm.AddedCount,
+				vm.Variant.Name,
+				vm.Equivalent,
+				vm.Total,
+			)
+		} else {
+			_, _ = fmt.Fprintf(&builder, "✅ Добавлено: %d отжиманий!\n📈 Сегодня: %d\n\n", vm.AddedCount, vm.Total)
+		}
+
+		_, _ = builder.WriteString(FormatWeeklyProgress(vm.Weekly))
+		return builder.String()
+	}
+
+	if vm.Variant != nil && vm.Variant.Code != model.VariantStandard {
+		_, _ = fmt.Fprintf(
+			&builder, "✅ Добавлено: %d отжиманий (%s ≈ %d обычных)!\n📈 Твой прогресс: %d/%d\n",
+			vm.AddedCount,
+			vm.Variant.Name,
+			vm.Equivalent,
+			vm.Total,
+			vm.DailyNorm,
+		)
+	} else {
+		_, _ = fmt.Fprintf(
+			&builder, "✅ Добавлено: %d отжиманий!\n📈 Твой прогресс: %d/%d\n",
+			vm.AddedCount,
+			vm.Total,
+			vm.DailyNorm,
+		)
+	}
 
 	if vm.Completed {
 		_, _ = builder.WriteString("\n🎯 Ты выполнил дневную норму!\n")
 		return builder.String()
 	}
 
+	if vm.Plan != nil && len(vm.Plan.Remaining) > 0 {
+		_, _ = fmt.Fprintf(&builder, "%s\n", FormatPlanProgress(vm.Plan))
+	}
+
 	if !vm.HasLeader {
 		builder.WriteString(
 			"\n❌ Никто еще не выполнил норму сегодня.\nМожет, ты будешь первым? 💪\n",
@@ -209,7 +302,44 @@ func FormatAddPushups(vm *model.AddPushupsViewModel) string {
 	return builder.String()
 }
 
+// FormatPushupsConfirmation формирует предупреждение о подозрительной записи
+func FormatPushupsConfirmation(vm *model.AddPushupsViewModel) string {
+	var builder strings.Builder
+
+	_, _ = fmt.Fprintf(
+		&builder,
+		"🤔 %s за раз? Выглядит необычно: %s.\n\n",
+		FormatTimesWord(vm.AddedCount),
+		vm.Reason,
+	)
+
+	_, _ = builder.WriteString("Если всё верно — подтверди запись. Она попадёт в статистику, но может быть проверена администратором.")
+
+	return builder.String()
+}
+
+// FormatFlaggedEntry формирует карточку подозрительной записи для админа
+func FormatFlaggedEntry(entry model.FlaggedEntry) string {
+	username := entry.Username
+	if username == "" {
+		username = fmt.Sprintf("User%d", entry.UserID)
+	}
+
+	return fmt.Sprintf(
+		"🚩 #%d %s\n📅 %s → %d отжиманий\nПричина: %s",
+		entry.ID,
+		username,
+		entry.Date.Format("02.01.2006"),
+		entry.Count,
+		entry.Reason,
+	)
+}
+
 func FormatMaxReps(vm *model.MaxRepsViewModel) string {
+	if vm.Exercise != nil && vm.Exercise.Code != model.ExercisePushups {
+		return FormatExerciseMax(vm)
+	}
+
 	var builder strings.Builder
 
 	_, _ = fmt.Fprintf(
@@ -218,10 +348,22 @@ func FormatMaxReps(vm *model.MaxRepsViewModel) string {
 		vm.Count,
 	)
 
-	_, _ = fmt.Fprintf(
-		&builder, "🔔 Дневная норма установлена: %d\n\n",
-		vm.DailyNorm,
-	)
+	if vm.RPE > 0 {
+		_, _ = fmt.Fprintf(&builder, "😤 Тяжесть теста: RPE %d — %s\n\n", vm.RPE, formatRPEHint(vm.RPE))
+	}
+
+	if vm.NormStrategy != "" {
+		_, _ = fmt.Fprintf(
+			&builder, "🔔 Дневная норма установлена: %d (%s)\n\n",
+			vm.DailyNorm,
+			vm.NormStrategy,
+		)
+	} else {
+		_, _ = fmt.Fprintf(
+			&builder, "🔔 Дневная норма установлена: %d\n\n",
+			vm.DailyNorm,
+		)
+	}
 
 	_, _ = fmt.Fprintf(
 		&builder,
@@ -229,6 +371,15 @@ func FormatMaxReps(vm *model.MaxRepsViewModel) string {
 		vm.Rank,
 	)
 
+	if vm.RankChange != nil && !vm.RankChange.Promoted {
+		_, _ = fmt.Fprintf(
+			&builder,
+			"📉 Ранг понижен: %s → %s. Вернёшь его на следующем тесте!\n\n",
+			vm.RankChange.FromRank,
+			vm.RankChange.ToRank,
+		)
+	}
+
 	if vm.RepsToNext > 0 {
 
 		_, _ = fmt.Fprintf(
@@ -238,6 +389,20 @@ func FormatMaxReps(vm *model.MaxRepsViewModel) string {
 		)
 	}
 
+	if vm.HitTarget != nil {
+		_, _ = fmt.Fprintf(
+			&builder,
+			"🥇 Недельная цель %d выполнена! Целей подряд: %d\n\n",
+			vm.HitTarget.TargetMax,
+			vm.TargetStreak,
+		)
+	}
+
+	if vm.NextTarget != nil {
+		_, _ = builder.WriteString(FormatNextTarget(vm.NextTarget))
+		_, _ = builder.WriteString("\n\n")
+	}
+
 	if vm.Record != nil {
 
 		_, _ = fmt.Fprintf(
@@ -375,3 +540,1043 @@ func formatTimeUnit(value int, one, two, five string) string {
 func FormatTimesWord(n int) string {
 	return formatTimeUnit(n, "раз", "раза", "раз")
 }
+
+// FormatExerciseAmount форматирует количество в единицах упражнения (повторения или время)
+func FormatExerciseAmount(exercise *model.Exercise, value int) string {
+	if exercise != nil && exercise.IsTimed() {
+		return FormatDuration(value)
+	}
+	return formatTimeUnit(value, "повторение", "повторения", "повторений")
+}
+
+// FormatDuration форматирует секунды в вид "1 мин 30 сек"
+func FormatDuration(seconds int) string {
+	if seconds <= 0 {
+		return "0 сек"
+	}
+
+	minutes := seconds / 60
+	rest := seconds % 60
+
+	switch {
+	case minutes == 0:
+		return fmt.Sprintf("%d сек", rest)
+	case rest == 0:
+		return fmt.Sprintf("%d мин", minutes)
+	default:
+		return fmt.Sprintf("%d мин %d сек", minutes, rest)
+	}
+}
+
+// FormatExerciseTitle возвращает название упражнения с эмодзи
+func FormatExerciseTitle(exercise *model.Exercise) string {
+	if exercise == nil {
+		return "💪 Отжимания"
+	}
+	return strings.TrimSpace(exercise.Emoji + " " + exercise.Name)
+}
+
+// FormatExercisePrompt формирует запрос ввода для упражнения
+func FormatExercisePrompt(exercise *model.Exercise, isMax bool) string {
+	title := FormatExerciseTitle(exercise)
+
+	switch {
+	case isMax && exercise.IsTimed():
+		return fmt.Sprintf("%s\nВведите лучшее время за один подход в секундах:", title)
+	case isMax:
+		return fmt.Sprintf("%s\nВведите максимальное количество повторений за один подход:", title)
+	case exercise.IsTimed():
+		return fmt.Sprintf("%s\nВведите время в секундах:", title)
+	default:
+		return fmt.Sprintf("%s\nВведите количество повторений:", title)
+	}
+}
+
+// FormatAddExercise формирует ответ после добавления упражнения (кроме отжиманий)
+func FormatAddExercise(vm *model.AddPushupsViewModel) string {
+	var builder strings.Builder
+
+	_, _ = fmt.Fprintf(
+		&builder, "✅ %s: добавлено %s\n",
+		FormatExerciseTitle(vm.Exercise),
+		FormatExerciseAmount(vm.Exercise, vm.AddedCount),
+	)
+
+	if vm.DailyNorm <= 0 {
+		_, _ = fmt.Fprintf(
+			&builder, "📈 Сегодня: %s\n\nПройди тест максимума, чтобы получить дневную норму 🎯",
+			FormatExerciseAmount(vm.Exercise, vm.Total),
+		)
+		return builder.String()
+	}
+
+	_, _ = fmt.Fprintf(
+		&builder, "📈 Твой прогресс: %s из %s\n%s\n",
+		FormatExerciseAmount(vm.Exercise, vm.Total),
+		FormatExerciseAmount(vm.Exercise, vm.DailyNorm),
+		GenerateProgressBar(vm.Total, vm.DailyNorm, 10),
+	)
+
+	if vm.Completed {
+		_, _ = builder.WriteString("\n🎯 Ты выполнил дневную норму!\n")
+	}
+
+	return builder.String()
+}
+
+// FormatExerciseMax формирует ответ после теста максимума по упражнению (кроме отжиманий)
+func FormatExerciseMax(vm *model.MaxRepsViewModel) string {
+	var builder strings.Builder
+
+	_, _ = fmt.Fprintf(
+		&builder,
+		"✅ %s: твой результат — %s за подход!\n\n",
+		FormatExerciseTitle(vm.Exercise),
+		FormatExerciseAmount(vm.Exercise, vm.Count),
+	)
+
+	_, _ = fmt.Fprintf(
+		&builder, "🔔 Дневная норма установлена: %s\n\n",
+		FormatExerciseAmount(vm.Exercise, vm.DailyNorm),
+	)
+
+	if vm.Record != nil && vm.Record.MaxReps > 0 {
+		_, _ = fmt.Fprintf(
+			&builder,
+			"💪 Твой рекорд: %s → %s\n",
+			vm.Record.Date.Format("02.01.2006"),
+			FormatExerciseAmount(vm.Exercise, vm.Record.MaxReps),
+		)
+	}
+
+	if len(vm.History) >= 2 {
+		diff := vm.History[0].MaxReps - vm.History[1].MaxReps
+		switch {
+		case diff > 0:
+			_, _ = fmt.Fprintf(&builder, "\n🎉 Прогресс: +%s!", FormatExerciseAmount(vm.Exercise, diff))
+		case diff == 0:
+			_, _ = builder.WriteString("\n📊 Стабильный результат!")
+		}
+	} else {
+		_, _ = builder.WriteString("\n🎯 Это твой первый рекорд! Начнем отслеживать прогресс!")
+	}
+
+	return builder.String()
+}
+
+// historyRangeTitles — подписи диапазонов истории прогресса
+var historyRangeTitles = map[string]string{
+	model.HistoryRangeMonth: "за последний месяц",
+	model.HistoryRangeYear:  "за последний год",
+	model.HistoryRangeAll:   "за всё время",
+}
+
+// FormatProgressHistoryPage формирует страницу истории максимальных отжиманий.
+// Записи нумеруются от первого теста в выбранном диапазоне
+func FormatProgressHistoryPage(page *model.MaxRepsHistoryPage) string {
+	var builder strings.Builder
+	_, _ = fmt.Fprintf(&builder, "📈 Твоя история прогресса максимальных отжиманий %s:\n\n", historyRangeTitles[page.Range])
+
+	if page.Total == 0 {
+		_, _ = builder.WriteString("За этот период тестов не было. Выбери диапазон побольше 👇")
+		return builder.String()
+	}
+
+	for i, item := range page.Items {
+		_, _ = fmt.Fprintf(
+			&builder,
+			"%d. %s → %d отжиманий\n",
+			page.Total-page.Offset-i,
+			item.Date.Format("02.01.2006"),
+			item.MaxReps,
+		)
+	}
+
+	if page.Pages > 1 {
+		_, _ = fmt.Fprintf(&builder, "\n📄 Страница %d из %d (всего тестов: %d)\n", page.Page+1, page.Pages, page.Total)
+	}
+
+	if page.Total > 1 {
+		progress := page.Last - page.First
+
+		_, _ = builder.WriteString("\n📊 Общий прогресс: ")
+		switch {
+		case progress > 0:
+			_, _ = fmt.Fprintf(&builder, "+%d отжиманий! 🚀", progress)
+		case progress < 0:
+			_, _ = fmt.Fprintf(&builder, "%d отжиманий 📉", progress)
+		default:
+			builder.WriteString("стабильно! 🎯")
+		}
+	}
+
+	return builder.String()
+}
+
+// FormatExerciseHistory формирует историю максимумов по упражнению
+func FormatExerciseHistory(exercise *model.Exercise, history []model.MaxRepsHistoryItem) string {
+	if exercise == nil || exercise.Code == model.ExercisePushups {
+		return FormatProgressHistory(history)
+	}
+
+	title := FormatExerciseTitle(exercise)
+
+	if len(history) == 0 {
+		return fmt.Sprintf("📊 %s: история пуста.\nПройди тест максимума, чтобы начать отслеживать прогресс!", title)
+	}
+
+	var builder strings.Builder
+	_, _ = fmt.Fprintf(&builder, "📈 %s: история максимумов за подход\n\n", title)
+
+	for i := 0; i < len(history); i++ {
+		item := history[len(history)-1-i]
+
+		_, _ = fmt.Fprintf(
+			&builder,
+			"%d. %s → %s\n",
+			i+1,
+			item.Date.Format("02.01.2006"),
+			FormatExerciseAmount(exercise, item.MaxReps),
+		)
+	}
+
+	return builder.String()
+}
+
+// FormatMaxTestReminder формирует напоминание о повторном тесте максимума
+func FormatMaxTestReminder(reminder model.MaxTestReminder, now time.Time) string {
+	days := int(now.Sub(reminder.LastMaxTest).Hours() / 24)
+
+	return fmt.Sprintf(
+		"⏰ Пора проверить силу!\n\nПоследний тест максимума был %s назад — тогда ты сделал %d за подход.\n"+
+			"Рекомендуется обновлять результат каждые 1–2 недели, чтобы норма росла вместе с тобой 💪\n\n"+
+			"Ты хорошо восстановился — самое время для нового рекорда!",
+		formatTimeUnit(days, "день", "дня", "дней"),
+		reminder.MaxReps,
+	)
+}
+
+// FormatNextTarget описывает цель на неделю
+func FormatNextTarget(target *model.WeeklyTarget) string {
+	if target.TargetMax <= target.BaseMax {
+		return fmt.Sprintf(
+			"📅 Цель на неделю с %s: удержать %d за подход",
+			target.WeekStart.Format("02.01"),
+			target.TargetMax,
+		)
+	}
+
+	return fmt.Sprintf(
+		"📅 Цель на неделю с %s: %d за подход (+%d)",
+		target.WeekStart.Format("02.01"),
+		target.TargetMax,
+		target.TargetMax-target.BaseMax,
+	)
+}
+
+// FormatWeeklyTargets формирует блок недельных целей для экрана прогресса
+func FormatWeeklyTargets(summary *model.WeeklyTargetSummary) string {
+	if summary == nil || (summary.Current == nil && summary.Next == nil && len(summary.Resolved) == 0) {
+		return ""
+	}
+
+	var builder strings.Builder
+	_, _ = builder.WriteString("🎯 Недельные цели:\n\n")
+
+	if summary.Current != nil {
+		_, _ = fmt.Fprintf(
+			&builder,
+			"⏳ Эта неделя: %d за подход (лучший результат: %d)\n",
+			summary.Current.TargetMax,
+			summary.Current.AchievedMax,
+		)
+	}
+
+	if summary.Next != nil {
+		_, _ = builder.WriteString(FormatNextTarget(summary.Next))
+		_, _ = builder.WriteString("\n")
+	}
+
+	const maxResolved = 5
+	if len(summary.Resolved) > 0 {
+		_, _ = builder.WriteString("\n📝 Прошлые недели:\n")
+		for i, target := range summary.Resolved {
+			if i == maxResolved {
+				break
+			}
+
+			mark := "❌"
+			if target.Status == model.TargetStatusHit {
+				mark = "✅"
+			}
+
+			_, _ = fmt.Fprintf(
+				&builder,
+				"%s %s → цель %d, результат %d\n",
+				mark,
+				target.WeekStart.Format("02.01.2006"),
+				target.TargetMax,
+				target.AchievedMax,
+			)
+		}
+	}
+
+	if summary.Hits+summary.Misses > 0 {
+		_, _ = fmt.Fprintf(
+			&builder,
+			"\n📊 Выполнено: %d из %d\n🔥 Серия: %d (лучшая: %d)",
+			summary.Hits,
+			summary.Hits+summary.Misses,
+			summary.Streak,
+			summary.BestStreak,
+		)
+	}
+
+	return builder.String()
+}
+
+// FormatAchievementUnlocked уведомляет об открытом достижении
+func FormatAchievementUnlocked(achievement model.Achievement) string {
+	return fmt.Sprintf(
+		"🏅 Новое достижение!\n\n%s <b>%s</b>\n%s",
+		achievement.Emoji,
+		achievement.Name,
+		achievement.Description,
+	)
+}
+
+// FormatAchievements формирует экран достижений: открытые с датой, закрытые с прогрессом
+func FormatAchievements(statuses []model.AchievementStatus) string {
+	var builder strings.Builder
+
+	unlocked := 0
+	for _, status := range statuses {
+		if status.Unlocked {
+			unlocked++
+		}
+	}
+
+	_, _ = fmt.Fprintf(&builder, "🏅 Достижения: %d из %d\n\n", unlocked, len(statuses))
+
+	for _, status := range statuses {
+		if status.Unlocked {
+			_, _ = fmt.Fprintf(
+				&builder,
+				"%s <b>%s</b> — %s\n✅ Открыто %s\n\n",
+				status.Emoji,
+				status.Name,
+				status.Description,
+				status.UnlockedAt.Format("02.01.2006"),
+			)
+			continue
+		}
+
+		progress := status.Progress
+		if progress > status.Goal {
+			progress = status.Goal
+		}
+		if progress < 0 {
+			progress = 0
+		}
+
+		_, _ = fmt.Fprintf(
+			&builder,
+			"🔒 %s — %s\n%d / %d\n\n",
+			status.Name,
+			status.Description,
+			progress,
+			status.Goal,
+		)
+	}
+
+	return strings.TrimRight(builder.String(), "\n")
+}
+
+// FormatRankUp поздравляет с новым рангом
+func FormatRankUp(change *model.RankChange) string {
+	return fmt.Sprintf(
+		"🎉 <b>Новый ранг!</b>\n\n%s → <b>%s</b>\n\n💪 %d отжиманий за подход. Так держать!",
+		change.FromRank,
+		change.ToRank,
+		change.MaxReps,
+	)
+}
+
+// FormatRankAnnouncement — объявление о новом ранге для общего чата
+func FormatRankAnnouncement(change *model.RankChange) string {
+	return fmt.Sprintf(
+		"🎉 У %s новый ранг: <b>%s</b> — %d отжиманий за подход!",
+		change.Username,
+		change.ToRank,
+		change.MaxReps,
+	)
+}
+
+// FormatRankHistory формирует хронологию смены рангов для экрана прогресса
+func FormatRankHistory(history []model.RankChange) string {
+	if len(history) == 0 {
+		return ""
+	}
+
+	var builder strings.Builder
+	_, _ = builder.WriteString("🎖️ История рангов:\n\n")
+
+	for i := len(history) - 1; i >= 0; i-- {
+		change := history[i]
+
+		mark := "⬆️"
+		if !change.Promoted {
+			mark = "⬇️"
+		}
+
+		_, _ = fmt.Fprintf(
+			&builder,
+			"%s %s: %s → %s (%d)\n",
+			mark,
+			change.Date.Format("02.01.2006"),
+			change.FromRank,
+			change.ToRank,
+			change.MaxReps,
+		)
+	}
+
+	return strings.TrimRight(builder.String(), "\n")
+}
+
+// FormatRankFrequencyTable формирует таблицу рекомендованной частоты тренировок по рангам
+func FormatRankFrequencyTable(ranks []model.RankInfo) string {
+	var builder strings.Builder
+
+	for i, rank := range ranks {
+		from := rank.Threshold
+		if from < 1 {
+			from = 1
+		}
+
+		bounds := fmt.Sprintf("%d+", from)
+		if i+1 < len(ranks) {
+			bounds = fmt.Sprintf("%d–%d", from, ranks[i+1].Threshold-1)
+		}
+
+		_, _ = fmt.Fprintf(&builder, "<b>%s</b>  ", bounds)
+		if rank.Emoji != "" {
+			_, _ = fmt.Fprintf(&builder, "%s ", rank.Emoji)
+		}
+		_, _ = fmt.Fprintf(&builder, "<b>%s</b>  \n", rank.Name)
+
+		if rank.Frequency != "" {
+			_, _ = fmt.Fprintf(&builder, "<i>%s</i>\n", rank.Frequency)
+		}
+		_, _ = builder.WriteString("\n")
+	}
+
+	return builder.String()
+}
+
+// FormatNormStrategies описывает доступные стратегии расчёта нормы
+func FormatNormStrategies(strategies []model.NormStrategyInfo, current model.NormStrategyInfo, autoNorm bool) string {
+	var builder strings.Builder
+	_, _ = builder.WriteString("🧮 <b>Стратегия расчёта дневной нормы</b>\n\n")
+
+	for _, strategy := range strategies {
+		mark := "▫️"
+		if strategy.Code == current.Code {
+			mark = "✅"
+		}
+		_, _ = fmt.Fprintf(&builder, "%s <b>%s</b>\n%s\n\n", mark, strategy.Name, strategy.Description)
+	}
+
+	_, _ = builder.WriteString("Стратегия применяется при каждом тесте максимальных отжиманий.\n\n")
+
+	if autoNorm {
+		_, _ = builder.WriteString("🔄 Автоподстройка: <b>включена</b> — каждую ночь норма немного меняется по тому, как часто вы её выполняете.")
+	} else {
+		_, _ = builder.WriteString("🔄 Автоподстройка: <b>выключена</b>")
+	}
+	return builder.String()
+}
+
+// FormatNormAdjustment сообщает об автоматическом изменении нормы и его причине
+func FormatNormAdjustment(adjustment model.NormAdjustment) string {
+	icon := "📈"
+	if adjustment.NewNorm < adjustment.OldNorm {
+		icon = "📉"
+	}
+
+	return fmt.Sprintf(
+		"%s Дневная норма изменена: %d → %d\n\nПричина: %s.\n\nОтключить автоподстройку можно в «🧮 Стратегия нормы».",
+		icon,
+		adjustment.OldNorm,
+		adjustment.NewNorm,
+		adjustment.Reason,
+	)
+}
+
+// FormatWeeklyProgress формирует блок прогресса недельной цели
+func FormatWeeklyProgress(progress *model.WeeklyProgress) string {
+	var builder strings.Builder
+
+	_, _ = fmt.Fprintf(
+		&builder,
+		"📅 Неделя с %s: %d/%d\n%s\n",
+		progress.WeekStart.Format("02.01"),
+		progress.Total,
+		progress.Volume,
+		GenerateProgressBar(progress.Total, progress.Volume, 10),
+	)
+
+	if progress.TargetDays > 0 {
+		_, _ = fmt.Fprintf(&builder, "🗓 Тренировочных дней: %d/%d\n", progress.TrainingDays, progress.TargetDays)
+	}
+
+	if progress.RestDays > 0 {
+		_, _ = fmt.Fprintf(
+			&builder, "🌴 Дней отдыха: %d (цель уменьшена)\n",
+			progress.RestDays,
+		)
+	}
+
+	switch {
+	case progress.JustCompleted:
+		_, _ = builder.WriteString("\n🎯 Недельная цель выполнена!\n")
+	case progress.Completed:
+		_, _ = builder.WriteString("✅ Цель недели уже выполнена\n")
+	default:
+		_, _ = fmt.Fprintf(
+			&builder,
+			"Осталось %d за %s\n",
+			max(progress.Volume-progress.Total, 0),
+			formatTimeUnit(progress.DaysLeft, "день", "дня", "дней"),
+		)
+	}
+
+	if progress.Streak > 0 {
+		_, _ = fmt.Fprintf(&builder, "🔥 Недель подряд с выполненной целью: %d\n", progress.Streak)
+	}
+
+	return builder.String()
+}
+
+// FormatWeeklyGoal описывает текущий режим цели и как его поменять
+func FormatWeeklyGoal(goal model.WeeklyGoal) string {
+	var builder strings.Builder
+
+	if goal.IsWeekly() {
+		_, _ = fmt.Fprintf(&builder, "📅 Режим: недельная цель — <b>%d</b> за неделю", goal.Volume)
+		if goal.Days > 0 {
+			_, _ = fmt.Fprintf(&builder, ", %s тренировок", formatTimeUnit(goal.Days, "день", "дня", "дней"))
+		}
+		_, _ = builder.WriteString("\n\nСерии считаются по неделям (пн–вс), дни отдыха не ломают прогресс.\n")
+		_, _ = builder.WriteString("Вернуться к дневной норме: /week off")
+		return builder.String()
+	}
+
+	_, _ = builder.WriteString("📅 Режим: дневная норма\n\n")
+	_, _ = builder.WriteString(
+		"Если тренируетесь 3–4 дня в неделю, удобнее недельная цель: считается сумма за неделю (пн–вс), " +
+			"а дни отдыха не считаются пропусками.\n\n" +
+			"Включить: <code>/week 500</code> или <code>/week 500 4</code> (объём и число тренировочных дней)",
+	)
+	return builder.String()
+}
+
+// restKindTitle возвращает название вида перерыва
+func restKindTitle(kind string) string {
+	if kind == model.RestKindVacation {
+		return "🏖 Отпуск / болезнь"
+	}
+	return "😴 День отдыха"
+}
+
+// FormatRestStatus описывает текущий перерыв или предлагает его взять
+func FormatRestStatus(active *model.RestPeriod) string {
+	if active != nil {
+		return fmt.Sprintf(
+			"%s до %s включительно.\n\nНапоминания на паузе, серии не прерываются, "+
+				"а эти дни не учитываются в доле выполненной нормы.",
+			restKindTitle(active.Kind),
+			active.EndDate.Format("02.01.2006"),
+		)
+	}
+
+	return "🌴 Нужна пауза?\n\n" +
+		"Отметьте день отдыха или отпуск/болезнь — перерыв закончится сам. " +
+		"На это время напоминания отключатся, серии не сгорят, а адаптивная норма не снизится."
+}
+
+// FormatRestStarted подтверждает начало перерыва
+func FormatRestStarted(period model.RestPeriod) string {
+	if period.StartDate.Equal(period.EndDate) {
+		return fmt.Sprintf("%s отмечен. Восстанавливайтесь! 💤", restKindTitle(period.Kind))
+	}
+
+	return fmt.Sprintf(
+		"%s: %s — %s.\nВосстанавливайтесь, бот напомнит о тренировках после возвращения. 💤",
+		restKindTitle(period.Kind),
+		period.StartDate.Format("02.01"),
+		period.EndDate.Format("02.01"),
+	)
+}
+
+// formatRemainingSets описывает оставшиеся подходы: "3 × 17" или "3 подхода по 17–18"
+func formatRemainingSets(sets []int) string {
+	low, high := sets[len(sets)-1], sets[0]
+	if low == high {
+		return fmt.Sprintf("%d × %d", len(sets), low)
+	}
+	return fmt.Sprintf(
+		"%s по %d–%d",
+		formatTimeUnit(len(sets), "подход", "подхода", "подходов"),
+		low, high,
+	)
+}
+
+// FormatPlanProgress — короткая строка плана для ответа на добавление подхода
+func FormatPlanProgress(plan *model.DailyPlan) string {
+	var marks strings.Builder
+	for _, set := range plan.Sets {
+		if set.Done {
+			_, _ = marks.WriteString("✅")
+		} else {
+			_, _ = marks.WriteString("⬜")
+		}
+	}
+
+	return fmt.Sprintf("📋 План: %s — осталось %s", marks.String(), formatRemainingSets(plan.Remaining))
+}
+
+// FormatDailyPlan формирует план подходов на сегодня
+func FormatDailyPlan(plan *model.DailyPlan) string {
+	var builder strings.Builder
+
+	_, _ = builder.WriteString("📋 <b>План на сегодня</b>\n\n")
+
+	if plan.DailyNorm <= 0 {
+		_, _ = builder.WriteString("Дневная норма не задана — пройдите тест максимума или установите норму вручную.")
+		return builder.String()
+	}
+
+	if plan.MaxReps > 0 {
+		_, _ = fmt.Fprintf(
+			&builder, "Норма: %d • подход ≈ %d (70%% от максимума %d)\n\n",
+			plan.DailyNorm, plan.SetSize, plan.MaxReps,
+		)
+	} else {
+		_, _ = fmt.Fprintf(
+			&builder, "Норма: %d • подход ≈ %d\n<i>Пройдите тест максимума, чтобы подобрать размер подхода под вас</i>\n\n",
+			plan.DailyNorm, plan.SetSize,
+		)
+	}
+
+	for i, set := range plan.Sets {
+		mark := "⬜"
+		if set.Done {
+			mark = "✅"
+		}
+		_, _ = fmt.Fprintf(&builder, "%s %d. %d\n", mark, i+1, set.Reps)
+	}
+
+	_, _ = fmt.Fprintf(&builder, "\nСделано: %d/%d\n", plan.Total, plan.DailyNorm)
+
+	if len(plan.Remaining) == 0 {
+		_, _ = builder.WriteString("🎯 Норма на сегодня выполнена!")
+		return builder.String()
+	}
+
+	_, _ = fmt.Fprintf(&builder, "Осталось: %s\n\n", formatRemainingSets(plan.Remaining))
+	_, _ = builder.WriteString("💡 Отдыхайте между подходами 1–2 минуты, записывайте каждый подход отдельно через «➕ Добавить отжимания» — так он отметится в плане")
+
+	return builder.String()
+}
+
+// FormatPrograms формирует каталог программ тренировок
+func FormatPrograms(programs []model.Program) string {
+	var builder strings.Builder
+
+	_, _ = builder.WriteString("🗓 <b>Программы тренировок</b>\n\n")
+
+	for _, program := range programs {
+		_, _ = fmt.Fprintf(
+			&builder, "<b>%s</b> — %s\n%s\n\n",
+			program.Name,
+			formatTimeUnit(len(program.Weeks), "неделя", "недели", "недель"),
+			program.Description,
+		)
+	}
+
+	_, _ = builder.WriteString("Уровень подбирается по вашему последнему тесту максимума. Если тренировка не далась, неделя повторится.")
+	return builder.String()
+}
+
+// formatProgramSets выводит подходы тренировки: "2 – 3 – 2 – 2 – 3+"
+func formatProgramSets(workout *model.ProgramWorkout) string {
+	parts := make([]string, len(workout.Sets))
+	for i, reps := range workout.Sets {
+		parts[i] = strconv.Itoa(reps)
+	}
+	if workout.LastSetMax && len(parts) > 0 {
+		parts[len(parts)-1] += "+"
+	}
+	return strings.Join(parts, " – ")
+}
+
+// FormatProgramWorkout формирует текущую тренировку по программе
+func FormatProgramWorkout(workout *model.ProgramWorkout, now time.Time) string {
+	var builder strings.Builder
+
+	_, _ = fmt.Fprintf(
+		&builder,
+		"🗓 <b>%s</b>\nНеделя %d из %d • тренировка %d из %d\n\n",
+		workout.ProgramName,
+		workout.Week, workout.TotalWeeks,
+		workout.Day, workout.DaysInWeek,
+	)
+
+	_, _ = fmt.Fprintf(&builder, "Подходы: <b>%s</b>\n", formatProgramSets(workout))
+	if workout.LastSetMax {
+		_, _ = builder.WriteString("<i>«+» — последний подход на максимум, но не меньше указанного</i>\n")
+	}
+
+	total := 0
+	for _, reps := range workout.Sets {
+		total += reps
+	}
+	_, _ = fmt.Fprintf(&builder, "Всего: %d\n\n", total)
+
+	if workout.RestUntil != nil && now.Before(*workout.RestUntil) {
+		_, _ = fmt.Fprintf(
+			&builder,
+			"😴 После прошлой тренировки лучше отдохнуть до %s\n\n",
+			workout.RestUntil.Format("02.01 15:04"),
+		)
+	}
+
+	_, _ = builder.WriteString("Отдыхайте между подходами 1–2 минуты и записывайте их через «➕ Добавить отжимания». После тренировки отметьте результат кнопкой ниже.")
+	return builder.String()
+}
+
+// FormatProgramSessionResult сообщает итог тренировки и что будет дальше
+func FormatProgramSessionResult(result *model.ProgramSessionResult) string {
+	var builder strings.Builder
+
+	if result.Completed {
+		_, _ = builder.WriteString("✅ Тренировка засчитана!\n\n")
+	} else {
+		_, _ = builder.WriteString("⚠️ Тренировка отмечена как невыполненная — ничего страшного.\n\n")
+	}
+
+	switch result.Outcome {
+	case model.ProgramOutcomeNextDay:
+		_, _ = fmt.Fprintf(&builder, "Следующая — тренировка %d недели %d после дня отдыха.", result.Next.Day, result.Next.Week)
+	case model.ProgramOutcomeNextWeek:
+		_, _ = fmt.Fprintf(&builder, "🎉 Неделя пройдена! Переходим к неделе %d из %d.", result.Next.Week, result.Next.TotalWeeks)
+	case model.ProgramOutcomeRepeatWeek:
+		_, _ = fmt.Fprintf(&builder, "🔁 На этой неделе были невыполненные тренировки — повторим неделю %d, чтобы закрепить результат.", result.Next.Week)
+	case model.ProgramOutcomeFinished:
+		_, _ = fmt.Fprintf(
+			&builder,
+			"🏆 Программа «%s» завершена! Отдохните пару дней и пройдите «🎯 Тест максимальных отжиманий», чтобы увидеть результат.",
+			result.ProgramName,
+		)
+	}
+
+	return builder.String()
+}
+
+// formatClock выводит минуты от полуночи как "ЧЧ:ММ"
+func formatClock(minutes int) string {
+	return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
+}
+
+// FormatGTGStatus описывает режим grease-the-groove и как его настроить
+func FormatGTGStatus(gtg model.GTGPrompt) string {
+	var builder strings.Builder
+
+	_, _ = builder.WriteString("🔁 <b>Grease the groove</b>\n\n")
+	_, _ = builder.WriteString("Частые лёгкие подходы в течение дня — примерно половина максимума, без отказа. " +
+		"Так растёт выносливость без перегрузки.\n\n")
+
+	if gtg.Settings.Enabled {
+		_, _ = fmt.Fprintf(
+			&builder,
+			"Включено: с %s до %s, каждые %d мин по <b>%d</b>\n",
+			formatClock(gtg.Settings.WindowStart),
+			formatClock(gtg.Settings.WindowEnd),
+			gtg.Settings.IntervalMinutes,
+			gtg.SetSize,
+		)
+		if gtg.Settings.NextPromptAt != nil {
+			_, _ = fmt.Fprintf(&builder, "Следующее напоминание: %s\n", gtg.Settings.NextPromptAt.Format("02.01 15:04"))
+		}
+		_, _ = builder.WriteString("\nВыключить: <code>/gtg off</code>")
+		return builder.String()
+	}
+
+	_, _ = builder.WriteString("Включить: <code>/gtg 10:00-18:00 60</code> — окно и интервал в минутах.\n")
+	_, _ = builder.WriteString("Размер подхода можно задать третьим числом: <code>/gtg 10:00-18:00 60 8</code>")
+	return builder.String()
+}
+
+// FormatGTGPrompt формирует напоминание о микроподходе
+func FormatGTGPrompt(gtg model.GTGPrompt) string {
+	return fmt.Sprintf(
+		"🔁 Время микроподхода: <b>%d</b> отжиманий без отказа.\nНажмите «✅ Сделал», и подход запишется.",
+		gtg.SetSize,
+	)
+}
+
+// FormatRestTimer показывает оставшееся время отдыха: "⏱ Отдых: 1:25"
+func FormatRestTimer(remaining time.Duration) string {
+	if remaining <= 0 {
+		return "⏱ Отдых окончен"
+	}
+
+	// Округляем вверх, чтобы 0:00 не показывалось раньше конца отдыха
+	seconds := int((remaining + time.Second - 1) / time.Second)
+	return fmt.Sprintf("⏱ Отдых: %d:%02d", seconds/60, seconds%60)
+}
+
+// FormatRestTimerDone сообщает, что пора делать следующий подход
+func FormatRestTimerDone() string {
+	return "🔔 Отдых окончен — время следующего подхода! Запишите его через «➕ Добавить отжимания»."
+}
+
+// formatWorkoutDuration выводит длительность тренировки: "47 мин" или "1 ч 05 мин"
+func formatWorkoutDuration(d time.Duration) string {
+	minutes := int(d.Round(time.Minute) / time.Minute)
+	if minutes < 60 {
+		return fmt.Sprintf("%d мин", minutes)
+	}
+	return fmt.Sprintf("%d ч %02d мин", minutes/60, minutes%60)
+}
+
+// formatDelta выводит разницу со знаком: "+12", "−3" или "="
+func formatDelta(delta int) string {
+	switch {
+	case delta > 0:
+		return fmt.Sprintf("+%d", delta)
+	case delta < 0:
+		return fmt.Sprintf("−%d", -delta)
+	default:
+		return "="
+	}
+}
+
+// FormatWorkoutStarted подтверждает начало тренировки
+func FormatWorkoutStarted(session model.WorkoutSession) string {
+	return fmt.Sprintf(
+		"▶️ Тренировка началась в %s\n\nЗаписывайте подходы через «➕ Добавить отжимания» — они попадут в эту тренировку. "+
+			"В конце нажмите «⏹ Завершить», чтобы увидеть итоги.",
+		session.StartedAt.Format("15:04"),
+	)
+}
+
+// FormatWorkoutSummary формирует итоги тренировки и сравнение с прошлой
+func FormatWorkoutSummary(summary *model.WorkoutSummary) string {
+	var builder strings.Builder
+	session := summary.Session
+
+	_, _ = builder.WriteString("⏹ <b>Тренировка завершена</b>\n\n")
+
+	if summary.AutoClosed {
+		_, _ = fmt.Fprintf(
+			&builder,
+			"<i>Тренировку не завершили за %s — она закрыта на последнем подходе, более поздние подходы записаны без неё.</i>\n\n",
+			formatWorkoutDuration(model.WorkoutSessionTimeout),
+		)
+	}
+
+	if session.Sets == 0 {
+		_, _ = fmt.Fprintf(&builder, "⏱ Длительность: %s\n\nПодходов не записано.", formatWorkoutDuration(summary.Duration))
+		return builder.String()
+	}
+
+	_, _ = fmt.Fprintf(&builder, "⏱ Длительность: %s\n", formatWorkoutDuration(summary.Duration))
+	_, _ = fmt.Fprintf(&builder, "🔢 Подходов: %d\n", session.Sets)
+	_, _ = fmt.Fprintf(&builder, "💪 Объём: <b>%d</b>\n", session.Total)
+	_, _ = fmt.Fprintf(&builder, "📏 Средний подход: %.1f\n", summary.Average)
+	_, _ = fmt.Fprintf(&builder, "🏆 Лучший подход: %d\n", session.BestSet)
+
+	if previous := summary.Previous; previous != nil {
+		_, _ = fmt.Fprintf(
+			&builder,
+			"\n📊 По сравнению с прошлой тренировкой (%s):\nобъём %s, подходов %s, лучший подход %s",
+			previous.StartedAt.Format("02.01"),
+			formatDelta(session.Total-previous.Total),
+			formatDelta(session.Sets-previous.Sets),
+			formatDelta(session.BestSet-previous.BestSet),
+		)
+	}
+
+	return builder.String()
+}
+
+// formatRPEHint поясняет субъективную тяжесть теста
+func formatRPEHint(rpe int) string {
+	switch {
+	case rpe >= 10:
+		return "настоящий максимум"
+	case rpe == 9:
+		return "оставался запас в 1 повторение"
+	default:
+		return "запас был — в следующий раз можно выложиться сильнее"
+	}
+}
+
+// FormatMaxTestIntro формирует разминку и проверку готовности перед тестом максимума
+func FormatMaxTestIntro(readiness model.MaxTestReadiness, now time.Time) string {
+	var builder strings.Builder
+
+	_, _ = builder.WriteString("🎯 <b>Тест максимальных отжиманий</b>\n\n")
+
+	if !readiness.Ready() {
+		_, _ = builder.WriteString("⚠️ <b>Сейчас результат может оказаться заниженным:</b>\n")
+		if readiness.TestedRecently && readiness.LastTestAt != nil {
+			_, _ = fmt.Fprintf(
+				&builder, "• прошлый тест был %s — мышцы ещё восстанавливаются\n",
+				readiness.LastTestAt.Format("02.01 в 15:04"),
+			)
+		}
+		if !readiness.Recovered {
+			_, _ = fmt.Fprintf(
+				&builder, "• за последние 2 дня был объёмный день (норма %d выполнена), всего %d отжиманий\n",
+				readiness.DailyNorm, readiness.RecentVolume,
+			)
+		}
+		if readiness.TrainedToday > 0 {
+			_, _ = fmt.Fprintf(&builder, "• сегодня уже сделано %d отжиманий\n", readiness.TrainedToday)
+		}
+		_, _ = builder.WriteString("Лучше отдохнуть день-два, но решать вам.\n\n")
+	} else {
+		_, _ = builder.WriteString("✅ Вы отдохнули — хорошее время для теста.\n\n")
+	}
+
+	_, _ = builder.WriteString("🔥 <b>Разминка (5–7 минут):</b>\n")
+	_, _ = builder.WriteString("• вращения в плечах, локтях и запястьях — по 10 раз\n")
+	_, _ = builder.WriteString("• 1–2 минуты лёгкого кардио: прыжки, бег на месте\n")
+	_, _ = builder.WriteString("• 2 лёгких подхода: 5 отжиманий от стены и 5 с колен\n")
+	_, _ = builder.WriteString("• отдых 2–3 минуты перед тестом\n\n")
+
+	_, _ = builder.WriteString("📏 <b>Правила:</b> один подход до отказа, полная амплитуда, без остановок дольше 2 секунд.")
+
+	if readiness.MaxReps > 0 {
+		_, _ = fmt.Fprintf(&builder, "\n\nТекущий максимум: <b>%d</b>", readiness.MaxReps)
+	}
+
+	return builder.String()
+}
+
+// FormatMaxTestRPEQuestion спрашивает субъективную тяжесть теста
+func FormatMaxTestRPEQuestion(count int) string {
+	return fmt.Sprintf(
+		"Результат: %d. Насколько тяжело было? (RPE)\n\n"+
+			"10 — больше ни одного повторения\n9 — мог ещё одно\n8 — мог ещё 2\n6–7 — был запас\n\n"+
+			"Можно пропустить.",
+		count,
+	)
+}
+
+// FormatMaxTestDeviation предупреждает о результате, сильно отличающемся от истории
+func FormatMaxTestDeviation(check model.MaxTestCheck) string {
+	baseline := fmt.Sprintf("текущего максимума (%d)", check.Previous)
+	if check.Tests > 1 {
+		baseline = fmt.Sprintf("вашего обычного результата (%d — медиана последних %d тестов)", check.Previous, check.Tests)
+	} else if check.Tests == 1 {
+		baseline = fmt.Sprintf("прошлого теста (%d)", check.Previous)
+	}
+
+	if check.Count > check.Previous {
+		return fmt.Sprintf(
+			"⚠️ %d — это на %d больше %s.\n\n"+
+				"Такой скачок бывает, но проверьте, нет ли опечатки. Норма будет пересчитана по новому результату.",
+			check.Count, check.Count-check.Previous, baseline,
+		)
+	}
+
+	return fmt.Sprintf(
+		"⚠️ %d — это на %d меньше %s.\n\n"+
+			"Возможно, мышцы не восстановились или сказывается усталость. "+
+			"Если сохранить, норма и ранг снизятся — может, лучше повторить тест через пару дней?",
+		check.Count, check.Previous-check.Count, baseline,
+	)
+}
+
+// FormatMaxRepsHistoryChange сообщает результат исправления истории теста максимума
+func FormatMaxRepsHistoryChange(change *model.MaxRepsHistoryChange) string {
+	var builder strings.Builder
+
+	_, _ = builder.WriteString("✅ История обновлена.\n\n")
+
+	if change.MaxReps == 0 {
+		_, _ = builder.WriteString("В истории больше нет результатов — пройдите «🎯 Тест максимальных отжиманий».")
+		return builder.String()
+	}
+
+	_, _ = fmt.Fprintf(&builder, "💪 Текущий максимум: %d\n🎖️ Ранг: %s", change.MaxReps, change.Rank)
+
+	if change.RankChange != nil {
+		_, _ = fmt.Fprintf(&builder, "\n🔁 Ранг изменён: %s → %s", change.RankChange.FromRank, change.RankChange.ToRank)
+	}
+
+	if change.LatestChanged {
+		_, _ = builder.WriteString("\n\nПоследний результат изменился. Пересчитать дневную норму по нему?")
+	}
+
+	return builder.String()
+}
+
+// FormatInlineToday — сообщение о сегодняшнем прогрессе для отправки из inline-режима
+func FormatInlineToday(name string, vm *model.FullStatViewModel) string {
+	if vm.DailyNorm <= 0 {
+		return fmt.Sprintf("💪 %s сегодня: %d отжиманий", name, vm.TodayTotal)
+	}
+
+	text := fmt.Sprintf("💪 %s сегодня: %d из %d отжиманий (%d%%)",
+		name, vm.TodayTotal, vm.DailyNorm, vm.TodayTotal*100/vm.DailyNorm)
+	if vm.TodayTotal >= vm.DailyNorm {
+		text += "\n✅ Дневная норма выполнена!"
+	}
+	return text
+}
+
+// FormatInlineTodayShort — описание результата «сегодня» в списке inline-режима
+func FormatInlineTodayShort(vm *model.FullStatViewModel) string {
+	if vm.DailyNorm <= 0 {
+		return fmt.Sprintf("%d отжиманий", vm.TodayTotal)
+	}
+	return fmt.Sprintf("%d из %d", vm.TodayTotal, vm.DailyNorm)
+}
+
+// FormatInlineRecord — сообщение о рекорде и ранге для отправки из inline-режима
+func FormatInlineRecord(name string, card *model.StatsCard) string {
+	if card.MaxReps <= 0 {
+		return fmt.Sprintf("💪 %s ещё не проходил тест максимума\n📊 Всего отжиманий: %d", name, card.TotalAllTime)
+	}
+
+	return fmt.Sprintf(
+		"🏆 %s: %d отжиманий за подход\n🎖️ Ранг: %s\n🔥 Серия нормы: %d дн.\n📊 Всего отжиманий: %d",
+		name, card.MaxReps, card.Rank, card.Streak, card.TotalAllTime,
+	)
+}
+
+// FormatInlineRecordShort — описание результата «рекорд» в списке inline-режима
+func FormatInlineRecordShort(card *model.StatsCard) string {
+	if card.MaxReps <= 0 {
+		return "Тест максимума ещё не пройден"
+	}
+	return fmt.Sprintf("%d за подход · %s", card.MaxReps, card.Rank)
+}
+
+// FormatInlineLeaderboard — рейтинг за сегодня для отправки из inline-режима
+func FormatInlineLeaderboard(items []model.LeaderboardItem) string {
+	if len(items) == 0 {
+		return "🏆 Сегодня ещё никто не отжимался — будь первым!"
+	}
+
+	var builder strings.Builder
+	_, _ = builder.WriteString("🏆 Рейтинг за сегодня:\n\n")
+	for i, item := range items {
+		_, _ = fmt.Fprintf(&builder, "%d. %s: %d\n", i+1, item.Username, item.Count)
+	}
+	return strings.TrimRight(builder.String(), "\n")
+}
@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"trackerbot/model"
+)
+
+// AddNormCompletion отмечает выполнение дневной нормы за сегодня.
+// Первый за день пользователь получает is_first = true: единственность «первого»
+// держит частичный уникальный индекс idx_norm_completions_first, поэтому из двух
+// одновременно выполнивших первым станет только один, а второй запишется обычным.
+// Объём, исключённый админом, не засчитывается
+func (r *pushupRepository) AddNormCompletion(ctx context.Context, userID int64, dailyNorm int) error {
+	query := `
+    INSERT INTO norm_completions (user_id, date, daily_norm, is_first)
+    SELECT $1, CURRENT_DATE, $2, $3
+    WHERE COALESCE((
+        SELECT count FROM pushups
+        WHERE user_id = $1 AND date = CURRENT_DATE AND exercise = 'pushups'
+    ), 0) - (
+        SELECT COALESCE(SUM(count), 0) FROM flagged_pushups
+        WHERE user_id = $1 AND date = CURRENT_DATE AND status = 'excluded'
+    ) >= $2
+    ON CONFLICT DO NOTHING`
+
+	// Сначала пробуем стать первым: конфликт и по первичному ключу, и по индексу «первого» пропускается
+	tag, err := r.pool.Exec(ctx, query, userID, dailyNorm, true)
+	if err != nil || tag.RowsAffected() > 0 {
+		return err
+	}
+
+	_, err = r.pool.Exec(ctx, query, userID, dailyNorm, false)
+	return err
+}
+
+// GetNormCompletionDates возвращает даты выполнения нормы, начиная с последней
+func (r *pushupRepository) GetNormCompletionDates(ctx context.Context, userID int64) ([]time.Time, error) {
+	query := `SELECT date FROM norm_completions WHERE user_id = $1 ORDER BY date DESC`
+
+	rows, err := r.pool.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var dates []time.Time
+	for rows.Next() {
+		var date time.Time
+		if err := rows.Scan(&date); err != nil {
+			return nil, err
+		}
+		dates = append(dates, date)
+	}
+	return dates, rows.Err()
+}
+
+// GetAchievementStats собирает показатели для проверки достижений.
+// Серия выполнения нормы считается в сервисе по GetNormCompletionDates
+func (r *pushupRepository) GetAchievementStats(ctx context.Context, userID int64) (model.AchievementStats, error) {
+	query := `
+WITH monthly AS (
+    SELECT MAX(max_reps) AS best, MIN(max_reps) AS worst
+    FROM max_reps_history
+    WHERE user_id = $1 AND exercise = 'pushups' AND date > CURRENT_DATE - 30
+),
+base AS (
+    SELECT max_reps
+    FROM max_reps_history
+    WHERE user_id = $1 AND exercise = 'pushups' AND date <= CURRENT_DATE - 30
+    ORDER BY date DESC
+    LIMIT 1
+)
+SELECT
+    (SELECT COALESCE(SUM(count), 0) FROM pushups WHERE user_id = $1 AND exercise = 'pushups')
+        - (SELECT COALESCE(SUM(count), 0) FROM flagged_pushups WHERE user_id = $1 AND status = 'excluded'),
+    (SELECT COUNT(*) FROM norm_completions WHERE user_id = $1 AND is_first),
+    COALESCE(m.best - COALESCE((SELECT max_reps FROM base), m.worst), 0),
+    u.max_reps
+FROM users u
+CROSS JOIN monthly m
+WHERE u.user_id = $1`
+
+	var stats model.AchievementStats
+	err := r.pool.QueryRow(ctx, query, userID).Scan(
+		&stats.TotalPushups,
+		&stats.FirstCompletions,
+		&stats.MonthlyMaxGain,
+		&stats.MaxReps,
+	)
+	return stats, err
+}
+
+// GetUserAchievements возвращает открытые достижения пользователя
+func (r *pushupRepository) GetUserAchievements(ctx context.Context, userID int64) ([]model.UserAchievement, error) {
+	query := `SELECT code, unlocked_at FROM user_achievements WHERE user_id = $1 ORDER BY unlocked_at`
+
+	rows, err := r.pool.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var achievements []model.UserAchievement
+	for rows.Next() {
+		var item model.UserAchievement
+		if err := rows.Scan(&item.Code, &item.UnlockedAt); err != nil {
+			return nil, err
+		}
+		achievements = append(achievements, item)
+	}
+	return achievements, rows.Err()
+}
+
+// UnlockAchievement открывает достижение. Возвращает false, если оно уже было открыто
+func (r *pushupRepository) UnlockAchievement(ctx context.Context, userID int64, code string) (bool, error) {
+	query := `
+    INSERT INTO user_achievements (user_id, code)
+    VALUES ($1, $2)
+    ON CONFLICT (user_id, code) DO NOTHING`
+
+	tag, err := r.pool.Exec(ctx, query, userID, code)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// RevokeAchievement закрывает достижение, условие которого перестало выполняться
+func (r *pushupRepository) RevokeAchievement(ctx context.Context, userID int64, code string) error {
+	query := `DELETE FROM user_achievements WHERE user_id = $1 AND code = $2`
+
+	_, err := r.pool.Exec(ctx, query, userID, code)
+	return err
+}
@@ -0,0 +1,182 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"trackerbot/model"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// GetExercises возвращает каталог упражнений
+func (r *pushupRepository) GetExercises(ctx context.Context) ([]model.Exercise, error) {
+	query := `
+    SELECT code, name, emoji, unit, norm_factor::float8
+    FROM exercises
+    ORDER BY sort_order, code`
+
+	rows, err := r.pool.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var exercises []model.Exercise
+	for rows.Next() {
+		var ex model.Exercise
+		if err := rows.Scan(&ex.Code, &ex.Name, &ex.Emoji, &ex.Unit, &ex.NormFactor); err != nil {
+			return nil, err
+		}
+		exercises = append(exercises, ex)
+	}
+	return exercises, rows.Err()
+}
+
+// GetExercise возвращает упражнение по коду
+func (r *pushupRepository) GetExercise(ctx context.Context, code string) (model.Exercise, error) {
+	query := `
+    SELECT code, name, emoji, unit, norm_factor::float8
+    FROM exercises
+    WHERE code = $1`
+
+	var ex model.Exercise
+	err := r.pool.QueryRow(ctx, query, code).
+		Scan(&ex.Code, &ex.Name, &ex.Emoji, &ex.Unit, &ex.NormFactor)
+	if err != nil {
+		return model.Exercise{}, fmt.Errorf("ошибка получения упражнения %s: %w", code, err)
+	}
+	return ex, nil
+}
+
+// AddExerciseReps добавляет повторения (или секунды) упражнения за сегодня и возвращает сумму за день
+func (r *pushupRepository) AddExerciseReps(ctx context.Context, userID int64, code string, count int) (int, error) {
+	query := `
+	INSERT INTO pushups (user_id, date, count, exercise)
+	VALUES ($1, CURRENT_DATE, $2, $3)
+	ON CONFLICT (user_id, date, exercise)
+	DO UPDATE SET 
+		count = pushups.count + $2
+	RETURNING count;
+	`
+
+	var total int
+	err := r.pool.QueryRow(ctx, query, userID, count, code).Scan(&total)
+	return total, err
+}
+
+// GetUserExercise возвращает максимум и норму пользователя по упражнению.
+// Если пользователь ещё не проходил тест, возвращаются нулевые значения.
+func (r *pushupRepository) GetUserExercise(ctx context.Context, userID int64, code string) (model.UserExercise, error) {
+	ex, err := r.GetExercise(ctx, code)
+	if err != nil {
+		return model.UserExercise{}, err
+	}
+
+	query := `SELECT max_reps, daily_norm FROM user_exercises WHERE user_id = $1 AND exercise = $2`
+
+	result := model.UserExercise{Exercise: ex}
+	err = r.pool.QueryRow(ctx, query, userID, code).Scan(&result.MaxReps, &result.DailyNorm)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return model.UserExercise{}, err
+	}
+
+	return result, nil
+}
+
+// SetExerciseMax сохраняет максимум и норму по упражнению и добавляет запись в историю
+func (r *pushupRepository) SetExerciseMax(
+	ctx context.Context,
+	userID int64,
+	code string,
+	maxReps int,
+	dailyNorm int,
+) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	_, err = tx.Exec(ctx, `
+    INSERT INTO user_exercises (user_id, exercise, max_reps, daily_norm, last_updated_max_reps)
+    VALUES ($1, $2, $3, $4, CURRENT_TIMESTAMP)
+    ON CONFLICT (user_id, exercise)
+    DO UPDATE SET
+        max_reps = EXCLUDED.max_reps,
+        daily_norm = EXCLUDED.daily_norm,
+        last_updated_max_reps = CURRENT_TIMESTAMP`,
+		userID, code, maxReps, dailyNorm,
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, `
+    INSERT INTO max_reps_history (user_id, date, max_reps, exercise)
+    VALUES ($1, CURRENT_DATE, $2, $3)
+    ON CONFLICT (user_id, date, exercise)
+    DO UPDATE SET max_reps = $2`,
+		userID, maxReps, code,
+	)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// SetExerciseNorm устанавливает дневную норму по упражнению вручную
+func (r *pushupRepository) SetExerciseNorm(ctx context.Context, userID int64, code string, dailyNorm int) error {
+	query := `
+    INSERT INTO user_exercises (user_id, exercise, daily_norm)
+    VALUES ($1, $2, $3)
+    ON CONFLICT (user_id, exercise)
+    DO UPDATE SET daily_norm = EXCLUDED.daily_norm`
+
+	_, err := r.pool.Exec(ctx, query, userID, code, dailyNorm)
+	return err
+}
+
+// GetExerciseHistory возвращает историю максимумов по упражнению
+func (r *pushupRepository) GetExerciseHistory(ctx context.Context, userID int64, code string) ([]model.MaxRepsHistoryItem, error) {
+	query := `
+    SELECT date, max_reps 
+    FROM max_reps_history 
+    WHERE user_id = $1 AND exercise = $2
+    ORDER BY date DESC`
+
+	rows, err := r.pool.Query(ctx, query, userID, code)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var history []model.MaxRepsHistoryItem
+	for rows.Next() {
+		var item model.MaxRepsHistoryItem
+		if err := rows.Scan(&item.Date, &item.MaxReps); err != nil {
+			return nil, err
+		}
+		history = append(history, item)
+	}
+	return history, rows.Err()
+}
+
+// GetExerciseRecord возвращает лучший результат по упражнению
+func (r *pushupRepository) GetExerciseRecord(ctx context.Context, userID int64, code string) (model.MaxRepsHistoryItem, error) {
+	query := `
+    SELECT date, max_reps 
+	FROM max_reps_history 
+	WHERE user_id = $1 AND exercise = $2
+	ORDER BY max_reps DESC, date DESC 
+	LIMIT 1`
+
+	var record model.MaxRepsHistoryItem
+	err := r.pool.QueryRow(ctx, query, userID, code).Scan(&record.Date, &record.MaxReps)
+	if err != nil {
+		return model.MaxRepsHistoryItem{}, err
+	}
+	return record, nil
+}
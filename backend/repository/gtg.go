@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"trackerbot/model"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// GetGTGSettings возвращает настройки grease-the-groove (выключенный режим, если их нет)
+func (r *pushupRepository) GetGTGSettings(ctx context.Context, userID int64) (model.GTGSettings, error) {
+	query := `
+    SELECT enabled, window_start, window_end, interval_minutes, set_size, next_prompt_at
+    FROM gtg_settings
+    WHERE user_id = $1`
+
+	var s model.GTGSettings
+	err := r.pool.QueryRow(ctx, query, userID).Scan(
+		&s.Enabled, &s.WindowStart, &s.WindowEnd, &s.IntervalMinutes, &s.SetSize, &s.NextPromptAt,
+	)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return model.GTGSettings{}, err
+	}
+
+	return s, nil
+}
+
+// SaveGTGSettings сохраняет настройки grease-the-groove
+func (r *pushupRepository) SaveGTGSettings(ctx context.Context, userID int64, s model.GTGSettings) error {
+	query := `
+    INSERT INTO gtg_settings (user_id, enabled, window_start, window_end, interval_minutes, set_size, next_prompt_at)
+    VALUES ($1, $2, $3, $4, $5, $6, $7)
+    ON CONFLICT (user_id) DO UPDATE SET
+        enabled = EXCLUDED.enabled,
+        window_start = EXCLUDED.window_start,
+        window_end = EXCLUDED.window_end,
+        interval_minutes = EXCLUDED.interval_minutes,
+        set_size = EXCLUDED.set_size,
+        next_prompt_at = EXCLUDED.next_prompt_at`
+
+	_, err := r.pool.Exec(ctx, query,
+		userID, s.Enabled, s.WindowStart, s.WindowEnd, s.IntervalMinutes, s.SetSize, s.NextPromptAt,
+	)
+	return err
+}
+
+// GetDueGTGPrompts возвращает пользователей, которым пора напомнить о микроподходе.
+// Пользователи на отдыхе (rest_periods) пропускаются
+func (r *pushupRepository) GetDueGTGPrompts(ctx context.Context, now time.Time) ([]model.GTGPrompt, error) {
+	query := `
+    SELECT g.user_id, g.window_start, g.window_end, g.interval_minutes, g.set_size, g.next_prompt_at, u.max_reps
+    FROM gtg_settings g
+    JOIN users u ON u.user_id = g.user_id
+    WHERE g.enabled
+      AND g.next_prompt_at <= $1
+      AND NOT EXISTS (
+          SELECT 1 FROM rest_periods r
+          WHERE r.user_id = g.user_id
+            AND CURRENT_DATE BETWEEN r.start_date AND r.end_date
+      )`
+
+	rows, err := r.pool.Query(ctx, query, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var prompts []model.GTGPrompt
+	for rows.Next() {
+		item := model.GTGPrompt{Settings: model.GTGSettings{Enabled: true}}
+		if err := rows.Scan(
+			&item.UserID,
+			&item.Settings.WindowStart,
+			&item.Settings.WindowEnd,
+			&item.Settings.IntervalMinutes,
+			&item.Settings.SetSize,
+			&item.Settings.NextPromptAt,
+			&item.MaxReps,
+		); err != nil {
+			return nil, err
+		}
+		prompts = append(prompts, item)
+	}
+	return prompts, rows.Err()
+}
+
+// SetGTGNextPrompt переносит следующее напоминание
+func (r *pushupRepository) SetGTGNextPrompt(ctx context.Context, userID int64, next time.Time) error {
+	query := `UPDATE gtg_settings SET next_prompt_at = $1 WHERE user_id = $2`
+	_, err := r.pool.Exec(ctx, query, next, userID)
+	return err
+}
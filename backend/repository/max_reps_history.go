@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// UpdateMaxRepsEntry исправляет результат в истории теста максимума
+func (r *pushupRepository) UpdateMaxRepsEntry(ctx context.Context, userID int64, recordID int64, maxReps int) error {
+	query := `
+    UPDATE max_reps_history
+    SET max_reps = $3
+    WHERE record_id = $1 AND user_id = $2 AND exercise = 'pushups'`
+
+	tag, err := r.pool.Exec(ctx, query, recordID, userID, maxReps)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("запись %d не найдена", recordID)
+	}
+	return nil
+}
+
+// DeleteMaxRepsEntry удаляет ошибочную запись из истории теста максимума
+func (r *pushupRepository) DeleteMaxRepsEntry(ctx context.Context, userID int64, recordID int64) error {
+	query := `
+    DELETE FROM max_reps_history
+    WHERE record_id = $1 AND user_id = $2 AND exercise = 'pushups'`
+
+	tag, err := r.pool.Exec(ctx, query, recordID, userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("запись %d не найдена", recordID)
+	}
+	return nil
+}
+
+// RestoreMaxReps выставляет max_reps по истории без отметки нового теста.
+// testedAt (если задан) переносит дату последнего теста, например после удаления последней записи
+func (r *pushupRepository) RestoreMaxReps(ctx context.Context, userID int64, maxReps int, testedAt *time.Time) error {
+	query := `
+    UPDATE users
+    SET max_reps = $2,
+        last_updated_max_reps = COALESCE($3, last_updated_max_reps)
+    WHERE user_id = $1`
+
+	_, err := r.pool.Exec(ctx, query, userID, maxReps, testedAt)
+	return err
+}
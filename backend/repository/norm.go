@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"trackerbot/model"
+)
+
+// GetAutoNorm сообщает, включена ли у пользователя автоподстройка нормы
+func (r *pushupRepository) GetAutoNorm(ctx context.Context, userID int64) (bool, error) {
+	query := `SELECT auto_norm FROM users WHERE user_id = $1`
+	var enabled bool
+	err := r.pool.QueryRow(ctx, query, userID).Scan(&enabled)
+	return enabled, err
+}
+
+// SetAutoNorm включает или выключает автоподстройку нормы
+func (r *pushupRepository) SetAutoNorm(ctx context.Context, userID int64, enabled bool) error {
+	query := `UPDATE users SET auto_norm = $1 WHERE user_id = $2`
+	_, err := r.pool.Exec(ctx, query, enabled, userID)
+	return err
+}
+
+// GetAutoNormCandidates возвращает пользователей с автоподстройкой, которых сегодня ещё не проверяли
+func (r *pushupRepository) GetAutoNormCandidates(ctx context.Context) ([]model.AutoNormCandidate, error) {
+	query := `
+    SELECT user_id, daily_norm
+    FROM users
+    WHERE auto_norm
+      AND auto_norm_checked_on IS DISTINCT FROM CURRENT_DATE`
+
+	rows, err := r.pool.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var candidates []model.AutoNormCandidate
+	for rows.Next() {
+		var item model.AutoNormCandidate
+		if err := rows.Scan(&item.UserID, &item.DailyNorm); err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, item)
+	}
+	return candidates, rows.Err()
+}
+
+// ApplyNormAdjustment меняет норму и записывает причину в историю одной транзакцией
+func (r *pushupRepository) ApplyNormAdjustment(
+	ctx context.Context,
+	adjustment model.NormAdjustment,
+	source string,
+) error {
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	_, err = tx.Exec(ctx, `
+    UPDATE users
+    SET daily_norm = $1, auto_norm_checked_on = CURRENT_DATE
+    WHERE user_id = $2`,
+		adjustment.NewNorm, adjustment.UserID,
+	)
+	if err != nil {
+		return fmt.Errorf("ошибка обновления нормы: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+    INSERT INTO norm_history (user_id, old_norm, new_norm, reason, source)
+    VALUES ($1, $2, $3, $4, $5)`,
+		adjustment.UserID, adjustment.OldNorm, adjustment.NewNorm, adjustment.Reason, source,
+	)
+	if err != nil {
+		return fmt.Errorf("ошибка записи истории нормы: %w", err)
+	}
+
+	return tx.Commit(ctx)
+}
+
+// MarkAutoNormChecked отмечает, что норма пользователя сегодня проверена без изменений
+func (r *pushupRepository) MarkAutoNormChecked(ctx context.Context, userID int64) error {
+	query := `UPDATE users SET auto_norm_checked_on = CURRENT_DATE WHERE user_id = $1`
+	_, err := r.pool.Exec(ctx, query, userID)
+	return err
+}
+
+// GetNormHistory возвращает историю изменений дневной нормы, начиная с самого раннего
+func (r *pushupRepository) GetNormHistory(ctx context.Context, userID int64) ([]model.NormChange, error) {
+	query := `
+    SELECT changed_at, old_norm, new_norm
+    FROM norm_history
+    WHERE user_id = $1
+    ORDER BY changed_at`
+
+	rows, err := r.pool.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var changes []model.NormChange
+	for rows.Next() {
+		var item model.NormChange
+		if err := rows.Scan(&item.ChangedAt, &item.OldNorm, &item.NewNorm); err != nil {
+			return nil, err
+		}
+		changes = append(changes, item)
+	}
+	return changes, rows.Err()
+}
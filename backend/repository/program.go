@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"trackerbot/model"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// GetProgramEnrollment возвращает текущую программу пользователя или nil, если он ни на какой не записан
+func (r *pushupRepository) GetProgramEnrollment(ctx context.Context, userID int64) (*model.ProgramEnrollment, error) {
+	query := `
+    SELECT program_code, level, week, day, failed_in_week, started_at, last_session_at
+    FROM user_programs
+    WHERE user_id = $1`
+
+	var e model.ProgramEnrollment
+	err := r.pool.QueryRow(ctx, query, userID).Scan(
+		&e.ProgramCode, &e.Level, &e.Week, &e.Day, &e.FailedInWeek, &e.StartedAt, &e.LastSessionAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &e, nil
+}
+
+// SaveProgramEnrollment записывает пользователя на программу (заменяя предыдущую)
+func (r *pushupRepository) SaveProgramEnrollment(ctx context.Context, userID int64, e model.ProgramEnrollment) error {
+	query := `
+    INSERT INTO user_programs (user_id, program_code, level, week, day, failed_in_week, started_at, last_session_at)
+    VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+    ON CONFLICT (user_id) DO UPDATE SET
+        program_code = EXCLUDED.program_code,
+        level = EXCLUDED.level,
+        week = EXCLUDED.week,
+        day = EXCLUDED.day,
+        failed_in_week = EXCLUDED.failed_in_week,
+        started_at = EXCLUDED.started_at,
+        last_session_at = EXCLUDED.last_session_at`
+
+	_, err := r.pool.Exec(ctx, query,
+		userID, e.ProgramCode, e.Level, e.Week, e.Day, e.FailedInWeek, e.StartedAt, e.LastSessionAt,
+	)
+	return err
+}
+
+// RecordProgramSession сохраняет тренировку и переводит пользователя на следующую одной транзакцией.
+// Если next == nil, программа завершена и запись о ней удаляется
+func (r *pushupRepository) RecordProgramSession(
+	ctx context.Context,
+	userID int64,
+	session model.ProgramSession,
+	next *model.ProgramEnrollment,
+) error {
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	// Записываемся только поверх той тренировки, которую отмечают:
+	// если запись уже сдвинулась (двойное нажатие), ничего не сохраняем
+	var tag pgconn.CommandTag
+	if next == nil {
+		tag, err = tx.Exec(ctx, `
+        DELETE FROM user_programs
+        WHERE user_id = $1 AND program_code = $2 AND week = $3 AND day = $4`,
+			userID, session.ProgramCode, session.Week, session.Day,
+		)
+	} else {
+		tag, err = tx.Exec(ctx, `
+        UPDATE user_programs
+        SET week = $1, day = $2, failed_in_week = $3, last_session_at = $4
+        WHERE user_id = $5 AND program_code = $6 AND week = $7 AND day = $8`,
+			next.Week, next.Day, next.FailedInWeek, next.LastSessionAt,
+			userID, session.ProgramCode, session.Week, session.Day,
+		)
+	}
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return model.ErrProgramSessionOutdated
+	}
+
+	_, err = tx.Exec(ctx, `
+    INSERT INTO program_sessions (user_id, program_code, week, day, planned, completed)
+    VALUES ($1, $2, $3, $4, $5, $6)`,
+		userID, session.ProgramCode, session.Week, session.Day, session.Planned, session.Completed,
+	)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// DeleteProgramEnrollment выписывает пользователя из программы (история тренировок сохраняется)
+func (r *pushupRepository) DeleteProgramEnrollment(ctx context.Context, userID int64) error {
+	_, err := r.pool.Exec(ctx, `DELETE FROM user_programs WHERE user_id = $1`, userID)
+	return err
+}
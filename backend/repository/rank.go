@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+
+	"trackerbot/model"
+)
+
+// AddRankChange сохраняет смену ранга пользователя
+func (r *pushupRepository) AddRankChange(ctx context.Context, userID int64, change model.RankChange) error {
+	query := `
+    INSERT INTO rank_history (user_id, from_rank, to_rank, max_reps, promoted)
+    VALUES ($1, $2, $3, $4, $5)`
+
+	_, err := r.pool.Exec(ctx, query, userID, change.FromRank, change.ToRank, change.MaxReps, change.Promoted)
+	return err
+}
+
+// GetRankHistory возвращает историю смены рангов, начиная с последней
+func (r *pushupRepository) GetRankHistory(ctx context.Context, userID int64) ([]model.RankChange, error) {
+	query := `
+    SELECT changed_at, from_rank, to_rank, max_reps, promoted
+    FROM rank_history
+    WHERE user_id = $1
+    ORDER BY changed_at DESC`
+
+	rows, err := r.pool.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var history []model.RankChange
+	for rows.Next() {
+		var item model.RankChange
+		if err := rows.Scan(&item.Date, &item.FromRank, &item.ToRank, &item.MaxReps, &item.Promoted); err != nil {
+			return nil, err
+		}
+		history = append(history, item)
+	}
+	return history, rows.Err()
+}
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"trackerbot/model"
+)
+
+// GetMaxTestReminderCandidates возвращает пользователей, которые проходили тест максимума
+// раньше testedBefore, которым с того момента ещё не отправлялось напоминание
+// и которые сейчас не отдыхают (см. rest_periods)
+func (r *pushupRepository) GetMaxTestReminderCandidates(
+	ctx context.Context,
+	testedBefore time.Time,
+) ([]model.MaxTestReminder, error) {
+
+	query := `
+    SELECT user_id, max_reps, daily_norm, last_updated_max_reps
+    FROM users
+    WHERE max_reps > 0
+      AND last_updated_max_reps < $1
+      AND (max_test_reminded_at IS NULL OR max_test_reminded_at < $1)
+      AND NOT EXISTS (
+          SELECT 1 FROM rest_periods r
+          WHERE r.user_id = users.user_id
+            AND CURRENT_DATE BETWEEN r.start_date AND r.end_date
+      )`
+
+	rows, err := r.pool.Query(ctx, query, testedBefore)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var candidates []model.MaxTestReminder
+	for rows.Next() {
+		var item model.MaxTestReminder
+		if err := rows.Scan(&item.UserID, &item.MaxReps, &item.DailyNorm, &item.LastMaxTest); err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, item)
+	}
+	return candidates, rows.Err()
+}
+
+// MarkMaxTestReminded запоминает время отправки напоминания о тесте
+func (r *pushupRepository) MarkMaxTestReminded(ctx context.Context, userID int64) error {
+	query := `UPDATE users SET max_test_reminded_at = CURRENT_TIMESTAMP WHERE user_id = $1`
+	_, err := r.pool.Exec(ctx, query, userID)
+	return err
+}
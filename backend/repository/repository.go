@@ -5,11 +5,13 @@ package repository
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 
 	"time"
 	"trackerbot/model"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -17,19 +19,79 @@ type PushupRepository interface {
 	Pool() *pgxpool.Pool
 	EnsureUser(ctx context.Context, userID int64, username string) error
 	AddPushups(ctx context.Context, userID int64, count int) (int, error)
+	AddPushupSet(ctx context.Context, userID int64, variant string, count int, equivalent int, gtg bool) (int, error)
+	GetTodaySets(ctx context.Context, userID int64) ([]model.LoggedSet, error)
+	GetVariantTotals(ctx context.Context, userID int64) ([]model.VariantTotal, error)
 	GetFullStat(ctx context.Context, userID int64) (*model.FullStatViewModel, error)
 	GetTodayStat(ctx context.Context, userID int64) (int, error)
+	GetDailyTotals(ctx context.Context, userID int64, from time.Time) ([]model.DailyTotal, error)
 	GetUsername(ctx context.Context, userID int64) (string, error)
 	SetMaxReps(ctx context.Context, userID int64, count int) error
 	SetDateCompletionOfDailyNorm(ctx context.Context, userID int64) error
+	GetLastMaxRepsUpdate(ctx context.Context, userID int64) (time.Time, error)
 	GetUserMaxReps(ctx context.Context, userID int64) (int, error)
 	ResetDailyNorm(ctx context.Context, userID int64) error
-	SetDailyNorm(ctx context.Context, userID int64, dailyNorm int) error
+	SetDailyNorm(ctx context.Context, userID int64, dailyNorm int, source string) error
 	GetDailyNorm(ctx context.Context, userID int64) (int, error)
 	GetFirstNormCompleter(ctx context.Context) (int64, error)
 	AddMaxRepsHistory(ctx context.Context, userID int64, maxReps int) error
 	GetMaxRepsHistory(ctx context.Context, userID int64) ([]model.MaxRepsHistoryItem, error)
 	GetMaxRepsRecord(ctx context.Context, userID int64) (model.MaxRepsHistoryItem, error)
+	AddFlaggedPushups(ctx context.Context, userID int64, count int, reason string) error
+	GetFlaggedPushups(ctx context.Context, status string) ([]model.FlaggedEntry, error)
+	SetFlagStatus(ctx context.Context, flagID int64, status string) error
+	ExcludeFlaggedPushups(ctx context.Context, flagID int64) (int64, error)
+	GetExercises(ctx context.Context) ([]model.Exercise, error)
+	GetExercise(ctx context.Context, code string) (model.Exercise, error)
+	AddExerciseReps(ctx context.Context, userID int64, code string, count int) (int, error)
+	GetUserExercise(ctx context.Context, userID int64, code string) (model.UserExercise, error)
+	SetExerciseMax(ctx context.Context, userID int64, code string, maxReps int, dailyNorm int) error
+	SetExerciseNorm(ctx context.Context, userID int64, code string, dailyNorm int) error
+	GetExerciseHistory(ctx context.Context, userID int64, code string) ([]model.MaxRepsHistoryItem, error)
+	GetExerciseRecord(ctx context.Context, userID int64, code string) (model.MaxRepsHistoryItem, error)
+	GetMaxTestReminderCandidates(ctx context.Context, testedBefore time.Time) ([]model.MaxTestReminder, error)
+	MarkMaxTestReminded(ctx context.Context, userID int64) error
+	GetWeeklyTargets(ctx context.Context, userID int64) ([]model.WeeklyTarget, error)
+	SetWeeklyTarget(ctx context.Context, userID int64, weekStart time.Time, baseMax int, targetMax int) error
+	UpdateWeeklyTarget(ctx context.Context, targetID int64, status string, achievedMax int) error
+	AddNormCompletion(ctx context.Context, userID int64, dailyNorm int) error
+	GetNormCompletionDates(ctx context.Context, userID int64) ([]time.Time, error)
+	GetAchievementStats(ctx context.Context, userID int64) (model.AchievementStats, error)
+	GetUserAchievements(ctx context.Context, userID int64) ([]model.UserAchievement, error)
+	UnlockAchievement(ctx context.Context, userID int64, code string) (bool, error)
+	RevokeAchievement(ctx context.Context, userID int64, code string) error
+	AddRankChange(ctx context.Context, userID int64, change model.RankChange) error
+	GetRankHistory(ctx context.Context, userID int64) ([]model.RankChange, error)
+	GetNormStrategy(ctx context.Context, userID int64) (string, error)
+	SetNormStrategy(ctx context.Context, userID int64, code string) error
+	GetAutoNorm(ctx context.Context, userID int64) (bool, error)
+	SetAutoNorm(ctx context.Context, userID int64, enabled bool) error
+	GetAutoNormCandidates(ctx context.Context) ([]model.AutoNormCandidate, error)
+	ApplyNormAdjustment(ctx context.Context, adjustment model.NormAdjustment, source string) error
+	MarkAutoNormChecked(ctx context.Context, userID int64) error
+	GetNormHistory(ctx context.Context, userID int64) ([]model.NormChange, error)
+	GetWeeklyGoal(ctx context.Context, userID int64) (model.WeeklyGoal, error)
+	SetWeeklyGoal(ctx context.Context, userID int64, goal model.WeeklyGoal) error
+	AddRestPeriod(ctx context.Context, userID int64, period model.RestPeriod) error
+	GetRestPeriods(ctx context.Context, userID int64) ([]model.RestPeriod, error)
+	EndRestPeriods(ctx context.Context, userID int64) error
+	GetProgramEnrollment(ctx context.Context, userID int64) (*model.ProgramEnrollment, error)
+	SaveProgramEnrollment(ctx context.Context, userID int64, enrollment model.ProgramEnrollment) error
+	RecordProgramSession(ctx context.Context, userID int64, session model.ProgramSession, next *model.ProgramEnrollment) error
+	DeleteProgramEnrollment(ctx context.Context, userID int64) error
+	GetGTGSettings(ctx context.Context, userID int64) (model.GTGSettings, error)
+	SaveGTGSettings(ctx context.Context, userID int64, settings model.GTGSettings) error
+	GetDueGTGPrompts(ctx context.Context, now time.Time) ([]model.GTGPrompt, error)
+	SetGTGNextPrompt(ctx context.Context, userID int64, next time.Time) error
+	GetOpenWorkoutSession(ctx context.Context, userID int64) (*model.WorkoutSession, error)
+	StartWorkoutSession(ctx context.Context, userID int64) (*model.WorkoutSession, error)
+	GetWorkoutSessionSets(ctx context.Context, sessionID int64) ([]int, error)
+	FinishWorkoutSession(ctx context.Context, session model.WorkoutSession) error
+	GetLastWorkoutSession(ctx context.Context, userID int64) (*model.WorkoutSession, error)
+	SetMaxTestRPE(ctx context.Context, userID int64, rpe int) error
+	UpdateMaxRepsEntry(ctx context.Context, userID int64, recordID int64, maxReps int) error
+	DeleteMaxRepsEntry(ctx context.Context, userID int64, recordID int64) error
+	RestoreMaxReps(ctx context.Context, userID int64, maxReps int, testedAt *time.Time) error
 }
 
 // PushupRepository предоставляет методы для работы с данными отжиманий в БД
@@ -61,30 +123,110 @@ func (r *pushupRepository) EnsureUser(ctx context.Context, userID int64, usernam
 	return err
 }
 
+// AddPushups добавляет обычные отжимания за сегодня и возвращает сумму за день
 func (r *pushupRepository) AddPushups(
 	ctx context.Context,
 	userID int64,
 	count int,
 ) (int, error) {
+	return r.AddPushupSet(ctx, userID, model.VariantStandard, count, count, false)
+}
+
+// AddPushupSet записывает подход в журнал и добавляет его эквивалент в дневную сумму.
+// gtg отмечает микроподход из напоминания GTG
+func (r *pushupRepository) AddPushupSet(
+	ctx context.Context,
+	userID int64,
+	variant string,
+	count int,
+	equivalent int,
+	gtg bool,
+) (int, error) {
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	_, err = tx.Exec(ctx, `
+	INSERT INTO pushup_sets (user_id, date, variant, count, equivalent, gtg, session_id)
+	VALUES ($1, CURRENT_DATE, $2, $3, $4, $5, (
+		SELECT session_id FROM workout_sessions
+		WHERE user_id = $1 AND finished_at IS NULL AND started_at > $6
+	))`,
+		userID, variant, count, equivalent, gtg, time.Now().Add(-model.WorkoutSessionTimeout),
+	)
+	if err != nil {
+		return 0, err
+	}
 
 	query := `
-	INSERT INTO pushups (user_id, date, count)
-	VALUES ($1, CURRENT_DATE, $2)
-	ON CONFLICT (user_id, date)
+	INSERT INTO pushups (user_id, date, count, exercise)
+	VALUES ($1, CURRENT_DATE, $2, 'pushups')
+	ON CONFLICT (user_id, date, exercise)
 	DO UPDATE SET 
 		count = pushups.count + $2
 	RETURNING count;
 	`
 
 	var total int
-	err := r.pool.QueryRow(
-		ctx,
-		query,
-		userID,
-		count,
-	).Scan(&total)
+	if err := tx.QueryRow(ctx, query, userID, equivalent).Scan(&total); err != nil {
+		return 0, err
+	}
 
-	return total, err
+	return total, tx.Commit(ctx)
+}
+
+// GetTodaySets возвращает подходы пользователя за сегодня в порядке записи
+func (r *pushupRepository) GetTodaySets(ctx context.Context, userID int64) ([]model.LoggedSet, error) {
+	query := `
+    SELECT variant, count, gtg
+    FROM pushup_sets
+    WHERE user_id = $1 AND date = CURRENT_DATE
+    ORDER BY set_id`
+
+	rows, err := r.pool.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sets []model.LoggedSet
+	for rows.Next() {
+		var item model.LoggedSet
+		if err := rows.Scan(&item.Variant, &item.Count, &item.GTG); err != nil {
+			return nil, err
+		}
+		sets = append(sets, item)
+	}
+	return sets, rows.Err()
+}
+
+// GetVariantTotals возвращает суммы отжиманий пользователя по вариантам за всё время
+func (r *pushupRepository) GetVariantTotals(ctx context.Context, userID int64) ([]model.VariantTotal, error) {
+	query := `
+    SELECT variant, SUM(count), SUM(equivalent)
+    FROM pushup_sets
+    WHERE user_id = $1
+    GROUP BY variant
+    ORDER BY SUM(equivalent) DESC`
+
+	rows, err := r.pool.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var totals []model.VariantTotal
+	for rows.Next() {
+		var item model.VariantTotal
+		if err := rows.Scan(&item.Code, &item.Count, &item.Equivalent); err != nil {
+			return nil, err
+		}
+		totals = append(totals, item)
+	}
+	return totals, rows.Err()
 }
 
 func (r *pushupRepository) GetFullStat(
@@ -96,10 +238,14 @@ func (r *pushupRepository) GetFullStat(
 WITH user_stats AS (
     SELECT
         COALESCE(SUM(count) FILTER (WHERE date = CURRENT_DATE), 0) AS today_total,
-        COALESCE(SUM(count), 0) AS total_all_time,
+        COALESCE(SUM(count), 0) - (
+            SELECT COALESCE(SUM(count), 0)
+            FROM flagged_pushups
+            WHERE user_id = $1 AND status = 'excluded'
+        ) AS total_all_time,
         MIN(date) AS first_date
     FROM pushups
-    WHERE user_id = $1
+    WHERE user_id = $1 AND exercise = 'pushups'
 ),
 leaderboard AS (
     SELECT json_agg(
@@ -110,11 +256,17 @@ leaderboard AS (
         ORDER BY total_count DESC
     ) AS data
     FROM (
-        SELECT u.username, SUM(p.count) AS total_count
+        SELECT u.username, SUM(p.count) - COALESCE(MAX(ex.excluded), 0) AS total_count
         FROM pushups p
         JOIN users u ON u.user_id = p.user_id
-        WHERE p.date = CURRENT_DATE
-        GROUP BY u.username
+        LEFT JOIN (
+            SELECT user_id, SUM(count) AS excluded
+            FROM flagged_pushups
+            WHERE date = CURRENT_DATE AND status = 'excluded'
+            GROUP BY user_id
+        ) ex ON ex.user_id = p.user_id
+        WHERE p.date = CURRENT_DATE AND p.exercise = 'pushups'
+        GROUP BY u.user_id, u.username
     ) t
 )
 SELECT
@@ -156,12 +308,44 @@ WHERE u.user_id = $1;
 
 // GetTodayStat возвращает суммарное количество отжиманий пользователя за указанную дату
 func (r *pushupRepository) GetTodayStat(ctx context.Context, userID int64) (int, error) {
-	query := `SELECT COALESCE(SUM(count), 0) FROM pushups WHERE user_id = $1 AND date = CURRENT_DATE`
+	query := `SELECT COALESCE(SUM(count), 0) FROM pushups WHERE user_id = $1 AND date = CURRENT_DATE AND exercise = 'pushups'`
 	var total int
 	err := r.pool.QueryRow(ctx, query, userID).Scan(&total)
 	return total, err
 }
 
+// GetDailyTotals возвращает суммы отжиманий пользователя по дням начиная с даты from
+func (r *pushupRepository) GetDailyTotals(ctx context.Context, userID int64, from time.Time) ([]model.DailyTotal, error) {
+	query := `
+    SELECT p.date, SUM(p.count) - COALESCE(MAX(ex.excluded), 0)
+    FROM pushups p
+    LEFT JOIN (
+        SELECT date, SUM(count) AS excluded
+        FROM flagged_pushups
+        WHERE user_id = $1 AND status = 'excluded'
+        GROUP BY date
+    ) ex ON ex.date = p.date
+    WHERE p.user_id = $1 AND p.exercise = 'pushups' AND p.date >= $2::date
+    GROUP BY p.date
+    ORDER BY p.date`
+
+	rows, err := r.pool.Query(ctx, query, userID, from)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var totals []model.DailyTotal
+	for rows.Next() {
+		var item model.DailyTotal
+		if err := rows.Scan(&item.Date, &item.Count); err != nil {
+			return nil, err
+		}
+		totals = append(totals, item)
+	}
+	return totals, rows.Err()
+}
+
 // GetUsername возвращает username пользователя
 func (r *pushupRepository) GetUsername(ctx context.Context, userID int64) (string, error) {
 	query := `SELECT username FROM users WHERE user_id = $1`
@@ -207,14 +391,55 @@ func (r *pushupRepository) GetUserMaxReps(ctx context.Context, userID int64) (in
 
 // ResetMaxReps сбрасывает max_reps и daily_norm пользователя на значение по умолчанию
 func (r *pushupRepository) ResetDailyNorm(ctx context.Context, userID int64) error {
-	query := `UPDATE users SET daily_norm = 40 WHERE user_id = $1`
-	_, err := r.pool.Exec(ctx, query, userID)
-	return err
+	return r.SetDailyNorm(ctx, userID, 40, model.NormSourceReset)
+}
+
+// SetDailyNorm меняет дневную норму и записывает изменение в norm_history одной транзакцией.
+// source — откуда пришло изменение (model.NormSource*)
+func (r *pushupRepository) SetDailyNorm(ctx context.Context, userID int64, dailyNorm int, source string) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var oldNorm int
+	err = tx.QueryRow(ctx, `SELECT daily_norm FROM users WHERE user_id = $1 FOR UPDATE`, userID).Scan(&oldNorm)
+	if err != nil {
+		return fmt.Errorf("ошибка получения нормы: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `UPDATE users SET daily_norm = $1 WHERE user_id = $2`, dailyNorm, userID)
+	if err != nil {
+		return fmt.Errorf("ошибка обновления нормы: %w", err)
+	}
+
+	if oldNorm != dailyNorm {
+		_, err = tx.Exec(ctx, `
+    INSERT INTO norm_history (user_id, old_norm, new_norm, source)
+    VALUES ($1, $2, $3, $4)`,
+			userID, oldNorm, dailyNorm, source,
+		)
+		if err != nil {
+			return fmt.Errorf("ошибка записи истории нормы: %w", err)
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// GetNormStrategy возвращает код стратегии расчёта нормы пользователя
+func (r *pushupRepository) GetNormStrategy(ctx context.Context, userID int64) (string, error) {
+	query := `SELECT norm_strategy FROM users WHERE user_id = $1`
+	var code string
+	err := r.pool.QueryRow(ctx, query, userID).Scan(&code)
+	return code, err
 }
 
-func (r *pushupRepository) SetDailyNorm(ctx context.Context, userID int64, dailyNorm int) error {
-	query := `UPDATE users SET daily_norm = $1 WHERE user_id = $2`
-	_, err := r.pool.Exec(ctx, query, dailyNorm, userID)
+// SetNormStrategy сохраняет стратегию расчёта нормы пользователя
+func (r *pushupRepository) SetNormStrategy(ctx context.Context, userID int64, code string) error {
+	query := `UPDATE users SET norm_strategy = $1 WHERE user_id = $2`
+	_, err := r.pool.Exec(ctx, query, code, userID)
 	return err
 }
 
@@ -231,9 +456,15 @@ func (r *pushupRepository) GetFirstNormCompleter(ctx context.Context) (int64, er
 	query := `
         SELECT user_id 
         FROM pushups 
-        WHERE date = CURRENT_DATE 
+        WHERE date = CURRENT_DATE AND exercise = 'pushups'
         GROUP BY user_id 
-        HAVING SUM(count) >= (SELECT daily_norm FROM users WHERE user_id = pushups.user_id)
+        HAVING SUM(count) - COALESCE((
+            SELECT SUM(f.count)
+            FROM flagged_pushups f
+            WHERE f.user_id = pushups.user_id
+              AND f.date = CURRENT_DATE
+              AND f.status = 'excluded'
+        ), 0) >= (SELECT daily_norm FROM users WHERE user_id = pushups.user_id)
         ORDER BY MIN(record_id) 
         LIMIT 1
     `
@@ -249,23 +480,33 @@ func (r *pushupRepository) GetFirstNormCompleter(ctx context.Context) (int64, er
 // AddMaxRepsHistory добавляет запись об отжиманиях за подход в историю
 func (r *pushupRepository) AddMaxRepsHistory(ctx context.Context, userID int64, maxReps int) error {
 	query := `
-    INSERT INTO max_reps_history (user_id, date, max_reps) 
-    VALUES ($1, CURRENT_DATE, $2)
-    ON CONFLICT (user_id, date) 
+    INSERT INTO max_reps_history (user_id, date, max_reps, exercise) 
+    VALUES ($1, CURRENT_DATE, $2, 'pushups')
+    ON CONFLICT (user_id, date, exercise) 
     DO UPDATE SET max_reps = $2`
 
 	_, err := r.pool.Exec(ctx, query, userID, maxReps)
 	return err
 }
 
+// SetMaxTestRPE сохраняет субъективную тяжесть сегодняшнего теста максимума
+func (r *pushupRepository) SetMaxTestRPE(ctx context.Context, userID int64, rpe int) error {
+	query := `
+    UPDATE max_reps_history
+    SET rpe = $2
+    WHERE user_id = $1 AND date = CURRENT_DATE AND exercise = 'pushups'`
+
+	_, err := r.pool.Exec(ctx, query, userID, rpe)
+	return err
+}
+
 // GetMaxRepsHistory возвращает историю об отжиманий за подход пользователя
 func (r *pushupRepository) GetMaxRepsHistory(ctx context.Context, userID int64) ([]model.MaxRepsHistoryItem, error) {
 	query := `
-    SELECT date, max_reps 
+    SELECT record_id, date, max_reps 
     FROM max_reps_history 
-    WHERE user_id = $1 
-    ORDER BY date DESC 
-    LIMIT 60` // Ограничиваем 60 последними записями
+    WHERE user_id = $1 AND exercise = 'pushups'
+    ORDER BY date DESC`
 
 	rows, err := r.pool.Query(ctx, query, userID)
 	if err != nil {
@@ -276,19 +517,19 @@ func (r *pushupRepository) GetMaxRepsHistory(ctx context.Context, userID int64)
 	var history []model.MaxRepsHistoryItem
 	for rows.Next() {
 		var item model.MaxRepsHistoryItem
-		if err := rows.Scan(&item.Date, &item.MaxReps); err != nil {
+		if err := rows.Scan(&item.ID, &item.Date, &item.MaxReps); err != nil {
 			return nil, err
 		}
 		history = append(history, item)
 	}
-	return history, nil
+	return history, rows.Err()
 }
 
 func (r *pushupRepository) GetMaxRepsRecord(ctx context.Context, userID int64) (model.MaxRepsHistoryItem, error) {
 	query := `
     SELECT date, max_reps 
 	FROM max_reps_history 
-	WHERE user_id = $1 
+	WHERE user_id = $1 AND exercise = 'pushups'
 	ORDER BY max_reps DESC, date DESC 
 	LIMIT 1`
 
@@ -301,3 +542,119 @@ func (r *pushupRepository) GetMaxRepsRecord(ctx context.Context, userID int64) (
 
 	return maxRepsRecord, nil
 }
+
+// AddFlaggedPushups сохраняет подтверждённую пользователем подозрительную запись для проверки админом
+func (r *pushupRepository) AddFlaggedPushups(ctx context.Context, userID int64, count int, reason string) error {
+	query := `
+    INSERT INTO flagged_pushups (user_id, date, count, reason)
+    VALUES ($1, CURRENT_DATE, $2, $3)`
+
+	_, err := r.pool.Exec(ctx, query, userID, count, reason)
+	return err
+}
+
+// GetFlaggedPushups возвращает подозрительные записи с указанным статусом
+func (r *pushupRepository) GetFlaggedPushups(ctx context.Context, status string) ([]model.FlaggedEntry, error) {
+	query := `
+    SELECT f.flag_id, f.user_id, u.username, f.date, f.count, f.reason, f.status
+    FROM flagged_pushups f
+    JOIN users u ON u.user_id = f.user_id
+    WHERE f.status = $1
+    ORDER BY f.created_at
+    LIMIT 20`
+
+	rows, err := r.pool.Query(ctx, query, status)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []model.FlaggedEntry
+	for rows.Next() {
+		var item model.FlaggedEntry
+		if err := rows.Scan(
+			&item.ID,
+			&item.UserID,
+			&item.Username,
+			&item.Date,
+			&item.Count,
+			&item.Reason,
+			&item.Status,
+		); err != nil {
+			return nil, err
+		}
+		entries = append(entries, item)
+	}
+	return entries, rows.Err()
+}
+
+// ExcludeFlaggedPushups исключает подозрительную запись и пересматривает выполнение нормы за её день:
+// если без исключённого объёма норма не набирается, отметка снимается, а «первым за день»
+// становится следующий выполнивший. Возвращает пользователя записи
+func (r *pushupRepository) ExcludeFlaggedPushups(ctx context.Context, flagID int64) (int64, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var (
+		userID int64
+		date   time.Time
+	)
+	err = tx.QueryRow(ctx, `
+    UPDATE flagged_pushups SET status = 'excluded'
+    WHERE flag_id = $1
+    RETURNING user_id, date`, flagID).Scan(&userID, &date)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, fmt.Errorf("запись %d не найдена", flagID)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	var wasFirst bool
+	err = tx.QueryRow(ctx, `
+    DELETE FROM norm_completions c
+    WHERE c.user_id = $1 AND c.date = $2
+      AND COALESCE((
+          SELECT count FROM pushups
+          WHERE user_id = $1 AND date = $2 AND exercise = 'pushups'
+      ), 0) - (
+          SELECT COALESCE(SUM(count), 0) FROM flagged_pushups
+          WHERE user_id = $1 AND date = $2 AND status = 'excluded'
+      ) < c.daily_norm
+    RETURNING c.is_first`, userID, date).Scan(&wasFirst)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return 0, err
+	}
+
+	if wasFirst {
+		_, err = tx.Exec(ctx, `
+    UPDATE norm_completions SET is_first = TRUE
+    WHERE (user_id, date) = (
+        SELECT user_id, date FROM norm_completions
+        WHERE date = $1
+        ORDER BY created_at, user_id
+        LIMIT 1
+    )`, date)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return userID, tx.Commit(ctx)
+}
+
+// SetFlagStatus обновляет статус подозрительной записи (одобрена / исключена из рейтинга)
+func (r *pushupRepository) SetFlagStatus(ctx context.Context, flagID int64, status string) error {
+	query := `UPDATE flagged_pushups SET status = $1 WHERE flag_id = $2`
+	tag, err := r.pool.Exec(ctx, query, status, flagID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("запись %d не найдена", flagID)
+	}
+	return nil
+}
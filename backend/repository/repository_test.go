@@ -9,6 +9,7 @@ import (
 
 	"trackerbot/config"
 	"trackerbot/db"
+	"trackerbot/model"
 
 	"github.com/joho/godotenv"
 	"github.com/stretchr/testify/assert"
@@ -157,7 +158,7 @@ func TestPushupRepository_CRUD(t *testing.T) {
 	assert.Equal(t, 40, dailyNorm)
 
 	// 8️⃣ SetDailyNorm
-	err = repo.SetDailyNorm(ctx, userID, 60)
+	err = repo.SetDailyNorm(ctx, userID, 60, model.NormSourceManual)
 	assert.NoError(t, err)
 	dailyNorm, _ = repo.GetDailyNorm(ctx, userID)
 	assert.Equal(t, 60, dailyNorm)
@@ -193,3 +194,123 @@ func TestPushupRepository_CRUD(t *testing.T) {
 
 	assert.True(t, found)
 }
+
+func TestPushupRepository_FlaggedPushups(t *testing.T) {
+	ctx := context.Background()
+	repo := setupRepo(t)
+
+	userID := int64(99998)
+
+	cleanUpUser(ctx, repo, userID)
+	defer cleanUpUser(ctx, repo, userID)
+
+	assert.NoError(t, repo.EnsureUser(ctx, userID, "flaggeduser"))
+
+	_, err := repo.AddPushups(ctx, userID, 300)
+	assert.NoError(t, err)
+
+	err = repo.AddFlaggedPushups(ctx, userID, 300, "test")
+	assert.NoError(t, err)
+
+	pending, err := repo.GetFlaggedPushups(ctx, model.FlagStatusPending)
+	assert.NoError(t, err)
+
+	var flagID int64
+	for _, f := range pending {
+		if f.UserID == userID {
+			flagID = f.ID
+		}
+	}
+	assert.NotZero(t, flagID)
+
+	// Исключённая запись не учитывается в рейтинге
+	assert.NoError(t, repo.SetFlagStatus(ctx, flagID, model.FlagStatusExcluded))
+
+	fullStat, err := repo.GetFullStat(ctx, userID)
+	assert.NoError(t, err)
+	for _, u := range fullStat.Leaderboard {
+		if u.Username == "flaggeduser" {
+			assert.Equal(t, 0, u.Count)
+		}
+	}
+
+	assert.Error(t, repo.SetFlagStatus(ctx, -1, model.FlagStatusApproved))
+}
+
+func TestPushupRepository_Exercises(t *testing.T) {
+	ctx := context.Background()
+	repo := setupRepo(t)
+
+	userID := int64(99997)
+
+	cleanUpUser(ctx, repo, userID)
+	defer cleanUpUser(ctx, repo, userID)
+
+	assert.NoError(t, repo.EnsureUser(ctx, userID, "exerciseuser"))
+
+	exercises, err := repo.GetExercises(ctx)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, exercises)
+	assert.Equal(t, model.ExercisePushups, exercises[0].Code)
+
+	// Приседания не смешиваются с отжиманиями
+	total, err := repo.AddExerciseReps(ctx, userID, "squats", 30)
+	assert.NoError(t, err)
+	assert.Equal(t, 30, total)
+
+	pushupsToday, err := repo.GetTodayStat(ctx, userID)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, pushupsToday)
+
+	// Без теста максимум и норма нулевые
+	userExercise, err := repo.GetUserExercise(ctx, userID, "squats")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, userExercise.MaxReps)
+
+	assert.NoError(t, repo.SetExerciseMax(ctx, userID, "squats", 40, 120))
+
+	userExercise, err = repo.GetUserExercise(ctx, userID, "squats")
+	assert.NoError(t, err)
+	assert.Equal(t, 40, userExercise.MaxReps)
+	assert.Equal(t, 120, userExercise.DailyNorm)
+
+	history, err := repo.GetExerciseHistory(ctx, userID, "squats")
+	assert.NoError(t, err)
+	assert.Len(t, history, 1)
+
+	pushupsHistory, err := repo.GetMaxRepsHistory(ctx, userID)
+	assert.NoError(t, err)
+	assert.Empty(t, pushupsHistory)
+}
+
+func TestPushupRepository_PushupSets(t *testing.T) {
+	ctx := context.Background()
+	repo := setupRepo(t)
+
+	userID := int64(99996)
+
+	cleanUpUser(ctx, repo, userID)
+	defer cleanUpUser(ctx, repo, userID)
+
+	assert.NoError(t, repo.EnsureUser(ctx, userID, "variantuser"))
+
+	_, err := repo.AddPushups(ctx, userID, 10)
+	assert.NoError(t, err)
+
+	// В дневную сумму идёт эквивалент, а не фактическое количество
+	total, err := repo.AddPushupSet(ctx, userID, "diamond", 20, 30, false)
+	assert.NoError(t, err)
+	assert.Equal(t, 40, total)
+
+	totals, err := repo.GetVariantTotals(ctx, userID)
+	assert.NoError(t, err)
+	assert.Len(t, totals, 2)
+
+	byCode := make(map[string]model.VariantTotal)
+	for _, item := range totals {
+		byCode[item.Code] = item
+	}
+	assert.Equal(t, 20, byCode["diamond"].Count)
+	assert.Equal(t, 30, byCode["diamond"].Equivalent)
+	assert.Equal(t, 10, byCode[model.VariantStandard].Count)
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+
+	"trackerbot/model"
+)
+
+// AddRestPeriod сохраняет день отдыха или отпуск пользователя
+func (r *pushupRepository) AddRestPeriod(ctx context.Context, userID int64, period model.RestPeriod) error {
+	query := `
+    INSERT INTO rest_periods (user_id, kind, start_date, end_date)
+    VALUES ($1, $2, $3::date, $4::date)`
+	_, err := r.pool.Exec(ctx, query, userID, period.Kind, period.StartDate, period.EndDate)
+	return err
+}
+
+// GetRestPeriods возвращает все перерывы пользователя, начиная с последнего
+func (r *pushupRepository) GetRestPeriods(ctx context.Context, userID int64) ([]model.RestPeriod, error) {
+	query := `
+    SELECT period_id, kind, start_date, end_date
+    FROM rest_periods
+    WHERE user_id = $1
+    ORDER BY start_date DESC`
+
+	rows, err := r.pool.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var periods []model.RestPeriod
+	for rows.Next() {
+		var item model.RestPeriod
+		if err := rows.Scan(&item.ID, &item.Kind, &item.StartDate, &item.EndDate); err != nil {
+			return nil, err
+		}
+		periods = append(periods, item)
+	}
+	return periods, rows.Err()
+}
+
+// EndRestPeriods досрочно завершает текущие и будущие перерывы:
+// начавшиеся раньше заканчиваются вчерашним днём, остальные удаляются
+func (r *pushupRepository) EndRestPeriods(ctx context.Context, userID int64) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	_, err = tx.Exec(ctx, `
+    DELETE FROM rest_periods
+    WHERE user_id = $1 AND start_date >= CURRENT_DATE`,
+		userID,
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, `
+    UPDATE rest_periods
+    SET end_date = CURRENT_DATE - 1
+    WHERE user_id = $1 AND end_date >= CURRENT_DATE`,
+		userID,
+	)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"trackerbot/model"
+)
+
+// GetWeeklyTargets возвращает недельные цели пользователя, начиная с самой поздней недели
+func (r *pushupRepository) GetWeeklyTargets(ctx context.Context, userID int64) ([]model.WeeklyTarget, error) {
+	query := `
+    SELECT target_id, week_start, base_max, target_max, achieved_max, status
+    FROM weekly_targets
+    WHERE user_id = $1
+    ORDER BY week_start DESC`
+
+	rows, err := r.pool.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var targets []model.WeeklyTarget
+	for rows.Next() {
+		var item model.WeeklyTarget
+		if err := rows.Scan(
+			&item.ID,
+			&item.WeekStart,
+			&item.BaseMax,
+			&item.TargetMax,
+			&item.AchievedMax,
+			&item.Status,
+		); err != nil {
+			return nil, err
+		}
+		targets = append(targets, item)
+	}
+	return targets, rows.Err()
+}
+
+// SetWeeklyTarget ставит цель на неделю. Повторный тест до начала недели
+// перезаписывает цель
+func (r *pushupRepository) SetWeeklyTarget(
+	ctx context.Context,
+	userID int64,
+	weekStart time.Time,
+	baseMax int,
+	targetMax int,
+) error {
+
+	query := `
+    INSERT INTO weekly_targets (user_id, week_start, base_max, target_max, status)
+    VALUES ($1, $2, $3, $4, 'pending')
+    ON CONFLICT (user_id, week_start)
+    DO UPDATE SET base_max = EXCLUDED.base_max,
+                  target_max = EXCLUDED.target_max,
+                  achieved_max = 0,
+                  status = 'pending'`
+
+	_, err := r.pool.Exec(ctx, query, userID, weekStart, baseMax, targetMax)
+	return err
+}
+
+// UpdateWeeklyTarget обновляет статус цели и лучший результат за её неделю
+func (r *pushupRepository) UpdateWeeklyTarget(
+	ctx context.Context,
+	targetID int64,
+	status string,
+	achievedMax int,
+) error {
+
+	query := `UPDATE weekly_targets SET status = $2, achieved_max = $3 WHERE target_id = $1`
+	_, err := r.pool.Exec(ctx, query, targetID, status, achievedMax)
+	return err
+}
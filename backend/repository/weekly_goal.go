@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"context"
+
+	"trackerbot/model"
+)
+
+// GetWeeklyGoal возвращает режим цели пользователя и параметры недельной цели
+func (r *pushupRepository) GetWeeklyGoal(ctx context.Context, userID int64) (model.WeeklyGoal, error) {
+	query := `SELECT goal_mode, weekly_goal, weekly_days FROM users WHERE user_id = $1`
+	var goal model.WeeklyGoal
+	err := r.pool.QueryRow(ctx, query, userID).Scan(&goal.Mode, &goal.Volume, &goal.Days)
+	return goal, err
+}
+
+// SetWeeklyGoal сохраняет режим цели и параметры недельной цели
+func (r *pushupRepository) SetWeeklyGoal(ctx context.Context, userID int64, goal model.WeeklyGoal) error {
+	query := `
+    UPDATE users
+    SET goal_mode = $1, weekly_goal = $2, weekly_days = $3
+    WHERE user_id = $4`
+	_, err := r.pool.Exec(ctx, query, goal.Mode, goal.Volume, goal.Days, userID)
+	return err
+}
@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"trackerbot/model"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// GetOpenWorkoutSession возвращает идущую тренировку с текущими итогами или nil
+func (r *pushupRepository) GetOpenWorkoutSession(ctx context.Context, userID int64) (*model.WorkoutSession, error) {
+	query := `
+    SELECT w.session_id, w.started_at, MAX(s.created_at),
+           COUNT(s.set_id), COALESCE(SUM(s.equivalent), 0), COALESCE(MAX(s.equivalent), 0)
+    FROM workout_sessions w
+    LEFT JOIN pushup_sets s ON s.session_id = w.session_id
+    WHERE w.user_id = $1 AND w.finished_at IS NULL
+    GROUP BY w.session_id, w.started_at`
+
+	var session model.WorkoutSession
+	err := r.pool.QueryRow(ctx, query, userID).Scan(
+		&session.ID, &session.StartedAt, &session.LastSetAt, &session.Sets, &session.Total, &session.BestSet,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &session, nil
+}
+
+// StartWorkoutSession открывает новую тренировку; nil — у пользователя уже есть открытая
+func (r *pushupRepository) StartWorkoutSession(ctx context.Context, userID int64) (*model.WorkoutSession, error) {
+	query := `
+    INSERT INTO workout_sessions (user_id)
+    VALUES ($1)
+    ON CONFLICT (user_id) WHERE finished_at IS NULL DO NOTHING
+    RETURNING session_id, started_at`
+
+	var session model.WorkoutSession
+	err := r.pool.QueryRow(ctx, query, userID).Scan(&session.ID, &session.StartedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// GetWorkoutSessionSets возвращает подходы тренировки (в обычных отжиманиях) в порядке записи
+func (r *pushupRepository) GetWorkoutSessionSets(ctx context.Context, sessionID int64) ([]int, error) {
+	query := `
+    SELECT equivalent
+    FROM pushup_sets
+    WHERE session_id = $1
+    ORDER BY set_id`
+
+	rows, err := r.pool.Query(ctx, query, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sets []int
+	for rows.Next() {
+		var reps int
+		if err := rows.Scan(&reps); err != nil {
+			return nil, err
+		}
+		sets = append(sets, reps)
+	}
+	return sets, rows.Err()
+}
+
+// FinishWorkoutSession закрывает тренировку и сохраняет её итоги
+func (r *pushupRepository) FinishWorkoutSession(ctx context.Context, session model.WorkoutSession) error {
+	query := `
+    UPDATE workout_sessions
+    SET finished_at = $2, sets = $3, total = $4, best_set = $5
+    WHERE session_id = $1`
+
+	_, err := r.pool.Exec(ctx, query,
+		session.ID, session.FinishedAt, session.Sets, session.Total, session.BestSet,
+	)
+	return err
+}
+
+// GetLastWorkoutSession возвращает последнюю завершённую тренировку с подходами или nil
+func (r *pushupRepository) GetLastWorkoutSession(ctx context.Context, userID int64) (*model.WorkoutSession, error) {
+	query := `
+    SELECT session_id, started_at, finished_at, sets, total, best_set
+    FROM workout_sessions
+    WHERE user_id = $1 AND finished_at IS NOT NULL AND sets > 0
+    ORDER BY finished_at DESC
+    LIMIT 1`
+
+	var session model.WorkoutSession
+	err := r.pool.QueryRow(ctx, query, userID).Scan(
+		&session.ID, &session.StartedAt, &session.FinishedAt, &session.Sets, &session.Total, &session.BestSet,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &session, nil
+}
@@ -0,0 +1,274 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"trackerbot/model"
+)
+
+// Показатели, по которым открываются достижения
+const (
+	MetricTotalPushups     = "total_pushups"
+	MetricNormStreak       = "norm_streak"
+	MetricWeekStreak       = "week_streak"
+	MetricFirstCompletions = "first_completions"
+	MetricMonthlyMaxGain   = "monthly_max_gain"
+	MetricMaxReps          = "max_reps"
+)
+
+// AchievementRule описывает достижение: оно открывается, когда показатель Metric
+// достигает значения Goal
+type AchievementRule struct {
+	model.Achievement
+	Metric string
+	Goal   int
+}
+
+// Каталог достижений в порядке отображения
+var achievementCatalog = []AchievementRule{
+	{model.Achievement{Code: "first_set", Emoji: "👣", Name: "Первый шаг", Description: "Записать первые отжимания"}, MetricTotalPushups, 1},
+	{model.Achievement{Code: "total_1000", Emoji: "💯", Name: "Тысячник", Description: "1000 отжиманий за всё время"}, MetricTotalPushups, 1000},
+	{model.Achievement{Code: "total_10000", Emoji: "🏔", Name: "Десять тысяч", Description: "10 000 отжиманий за всё время"}, MetricTotalPushups, 10000},
+	{model.Achievement{Code: "streak_7", Emoji: "🔥", Name: "Неделя без пропусков", Description: "Норма 7 дней подряд"}, MetricNormStreak, 7},
+	{model.Achievement{Code: "streak_30", Emoji: "🌋", Name: "Железная дисциплина", Description: "Норма 30 дней подряд"}, MetricNormStreak, 30},
+	{model.Achievement{Code: "week_streak_4", Emoji: "📅", Name: "Месяц в ритме", Description: "Недельная цель 4 недели подряд"}, MetricWeekStreak, 4},
+	{model.Achievement{Code: "week_streak_12", Emoji: "🗿", Name: "Квартал в ритме", Description: "Недельная цель 12 недель подряд"}, MetricWeekStreak, 12},
+	{model.Achievement{Code: "first_finisher", Emoji: "🥇", Name: "Первый на финише", Description: "Первым выполнить норму за день"}, MetricFirstCompletions, 1},
+	{model.Achievement{Code: "first_finisher_10", Emoji: "👑", Name: "Вечный лидер", Description: "Первым выполнить норму 10 раз"}, MetricFirstCompletions, 10},
+	{model.Achievement{Code: "monthly_gain_10", Emoji: "📈", Name: "Рывок месяца", Description: "+10 к максимуму за 30 дней"}, MetricMonthlyMaxGain, 10},
+	{model.Achievement{Code: "max_50", Emoji: "💪", Name: "Полтинник", Description: "50 отжиманий за подход"}, MetricMaxReps, 50},
+	{model.Achievement{Code: "max_100", Emoji: "🌟", Name: "Сотка", Description: "100 отжиманий за подход"}, MetricMaxReps, 100},
+}
+
+// AchievementMetric возвращает значение показателя metric
+func AchievementMetric(stats model.AchievementStats, metric string) int {
+	switch metric {
+	case MetricTotalPushups:
+		return stats.TotalPushups
+	case MetricNormStreak:
+		return stats.NormStreak
+	case MetricWeekStreak:
+		return stats.WeekStreak
+	case MetricFirstCompletions:
+		return stats.FirstCompletions
+	case MetricMonthlyMaxGain:
+		return stats.MonthlyMaxGain
+	case MetricMaxReps:
+		return stats.MaxReps
+	default:
+		return 0
+	}
+}
+
+// EarnedAchievements возвращает достижения каталога, условия которых выполнены
+func EarnedAchievements(stats model.AchievementStats) []model.Achievement {
+	var earned []model.Achievement
+	for _, rule := range achievementCatalog {
+		if AchievementMetric(stats, rule.Metric) >= rule.Goal {
+			earned = append(earned, rule.Achievement)
+		}
+	}
+	return earned
+}
+
+// CalculateNormStreak считает, сколько дней подряд выполнялась норма.
+// Даты передаются от последней к первой. Серия не прерывается,
+// если сегодня норма ещё не выполнена, но была выполнена вчера,
+// а также из-за дней отдыха и отпуска (rest) — они просто пропускаются
+func CalculateNormStreak(dates []time.Time, rest []model.RestPeriod, now time.Time) int {
+	if len(dates) == 0 {
+		return 0
+	}
+
+	expected := dateOnly(now)
+	if dateOnly(dates[0]).Before(expected) {
+		expected = expected.AddDate(0, 0, -1)
+	}
+
+	streak := 0
+	for _, date := range dates {
+		day := dateOnly(date)
+		if day.After(expected) {
+			continue
+		}
+		for day.Before(expected) && IsRestDay(rest, expected) {
+			expected = expected.AddDate(0, 0, -1)
+		}
+		if !day.Equal(expected) {
+			break
+		}
+		streak++
+		expected = expected.AddDate(0, 0, -1)
+	}
+
+	return streak
+}
+
+// LongestNormStreak возвращает самую длинную серию выполнения нормы за всё время.
+// Даты передаются от последней к первой, дни отдыха серию не прерывают
+func LongestNormStreak(dates []time.Time, rest []model.RestPeriod) int {
+	longest, streak := 0, 0
+	var expected time.Time
+	for i, date := range dates {
+		day := dateOnly(date)
+		for i > 0 && day.Before(expected) && IsRestDay(rest, expected) {
+			expected = expected.AddDate(0, 0, -1)
+		}
+		if i == 0 || !day.Equal(expected) {
+			streak = 0
+		}
+		streak++
+		longest = max(longest, streak)
+		expected = day.AddDate(0, 0, -1)
+	}
+	return longest
+}
+
+// revocableMetrics — показатели, которые падают, когда админ исключает запись.
+// Серия нормы проверяется по самой длинной серии: текущая обрывается и сама по себе
+var revocableMetrics = map[string]bool{
+	MetricTotalPushups:     true,
+	MetricNormStreak:       true,
+	MetricFirstCompletions: true,
+}
+
+// revokeAchievements закрывает достижения, которые после исключения записи больше не заработаны
+func (s *pushupService) revokeAchievements(ctx context.Context, userID int64) error {
+	stats, err := s.repo.GetAchievementStats(ctx, userID)
+	if err != nil {
+		return err
+	}
+	dates, err := s.repo.GetNormCompletionDates(ctx, userID)
+	if err != nil {
+		return err
+	}
+	rest, err := s.repo.GetRestPeriods(ctx, userID)
+	if err != nil {
+		return err
+	}
+	stats.NormStreak = LongestNormStreak(dates, rest)
+
+	unlocked, err := s.repo.GetUserAchievements(ctx, userID)
+	if err != nil {
+		return err
+	}
+	has := make(map[string]bool, len(unlocked))
+	for _, item := range unlocked {
+		has[item.Code] = true
+	}
+
+	for _, rule := range achievementCatalog {
+		if !has[rule.Code] || !revocableMetrics[rule.Metric] || AchievementMetric(stats, rule.Metric) >= rule.Goal {
+			continue
+		}
+		if err := s.repo.RevokeAchievement(ctx, userID, rule.Code); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getAchievementStats собирает показатели пользователя вместе с серией выполнения нормы
+// и серией недельной цели, если пользователь выбрал её вместо дневной нормы
+func (s *pushupService) getAchievementStats(ctx context.Context, userID int64) (model.AchievementStats, error) {
+	stats, err := s.repo.GetAchievementStats(ctx, userID)
+	if err != nil {
+		return stats, err
+	}
+
+	dates, err := s.repo.GetNormCompletionDates(ctx, userID)
+	if err != nil {
+		return stats, err
+	}
+
+	rest, err := s.repo.GetRestPeriods(ctx, userID)
+	if err != nil {
+		return stats, err
+	}
+	stats.NormStreak = CalculateNormStreak(dates, rest, time.Now())
+
+	// Недели не пересчитываются в дни: за неделю можно тренироваться и 3 дня,
+	// поэтому у недельной серии свои достижения
+	weekly, err := s.weeklyProgress(ctx, userID)
+	if err != nil {
+		return stats, err
+	}
+	if weekly != nil {
+		stats.WeekStreak = weekly.Streak
+	}
+
+	return stats, nil
+}
+
+// unlockAchievements открывает заработанные достижения и возвращает только новые.
+// Ошибки не прерывают основное действие пользователя — они только логируются
+func (s *pushupService) unlockAchievements(ctx context.Context, userID int64) []model.Achievement {
+	stats, err := s.getAchievementStats(ctx, userID)
+	if err != nil {
+		log.Printf("Ошибка получения показателей достижений: %v", err)
+		return nil
+	}
+
+	unlocked, err := s.repo.GetUserAchievements(ctx, userID)
+	if err != nil {
+		log.Printf("Ошибка получения достижений: %v", err)
+		return nil
+	}
+
+	has := make(map[string]bool, len(unlocked))
+	for _, item := range unlocked {
+		has[item.Code] = true
+	}
+
+	var unlockedNow []model.Achievement
+	for _, achievement := range EarnedAchievements(stats) {
+		if has[achievement.Code] {
+			continue
+		}
+
+		isNew, err := s.repo.UnlockAchievement(ctx, userID, achievement.Code)
+		if err != nil {
+			log.Printf("Ошибка открытия достижения %s: %v", achievement.Code, err)
+			continue
+		}
+		if isNew {
+			unlockedNow = append(unlockedNow, achievement)
+		}
+	}
+
+	return unlockedNow
+}
+
+// GetAchievements возвращает весь каталог достижений с прогрессом пользователя
+func (s *pushupService) GetAchievements(ctx context.Context, userID int64) ([]model.AchievementStatus, error) {
+	stats, err := s.getAchievementStats(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	unlocked, err := s.repo.GetUserAchievements(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	unlockedAt := make(map[string]time.Time, len(unlocked))
+	for _, item := range unlocked {
+		unlockedAt[item.Code] = item.UnlockedAt
+	}
+
+	statuses := make([]model.AchievementStatus, 0, len(achievementCatalog))
+	for _, rule := range achievementCatalog {
+		date, ok := unlockedAt[rule.Code]
+		statuses = append(statuses, model.AchievementStatus{
+			Achievement: rule.Achievement,
+			Unlocked:    ok,
+			UnlockedAt:  date,
+			Progress:    AchievementMetric(stats, rule.Metric),
+			Goal:        rule.Goal,
+		})
+	}
+
+	return statuses, nil
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"trackerbot/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalculateNormStreak(t *testing.T) {
+	now := time.Date(2026, 3, 10, 18, 0, 0, 0, time.Local)
+	day := func(offset int) time.Time {
+		return time.Date(2026, 3, 10+offset, 0, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name  string
+		dates []time.Time
+		want  int
+	}{
+		{"Empty", nil, 0},
+		{"TodayOnly", []time.Time{day(0)}, 1},
+		{"ThroughToday", []time.Time{day(0), day(-1), day(-2)}, 3},
+		{"TodayNotYetDone", []time.Time{day(-1), day(-2)}, 2},
+		{"Gap", []time.Time{day(0), day(-1), day(-3)}, 2},
+		{"Broken", []time.Time{day(-2), day(-3)}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, CalculateNormStreak(tt.dates, nil, now))
+		})
+	}
+}
+
+func TestCalculateNormStreak_RestDays(t *testing.T) {
+	now := time.Date(2026, 3, 10, 18, 0, 0, 0, time.Local)
+	day := func(offset int) time.Time {
+		return time.Date(2026, 3, 10+offset, 0, 0, 0, 0, time.UTC)
+	}
+	rest := []model.RestPeriod{
+		{Kind: model.RestKindDay, StartDate: day(-1), EndDate: day(-1)},
+		{Kind: model.RestKindVacation, StartDate: day(-6), EndDate: day(-4)},
+	}
+
+	tests := []struct {
+		name  string
+		dates []time.Time
+		want  int
+	}{
+		{"RestDaySkipped", []time.Time{day(0), day(-2), day(-3)}, 3},
+		{"VacationSkipped", []time.Time{day(0), day(-2), day(-3), day(-7)}, 4},
+		{"RestYesterdayTodayNotDone", []time.Time{day(-2), day(-3)}, 2},
+		{"GapOutsideRestBreaks", []time.Time{day(0), day(-3)}, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, CalculateNormStreak(tt.dates, rest, now))
+		})
+	}
+}
+
+func TestLongestNormStreak(t *testing.T) {
+	day := func(offset int) time.Time {
+		return time.Date(2026, 3, 10+offset, 0, 0, 0, 0, time.UTC)
+	}
+	rest := []model.RestPeriod{{Kind: model.RestKindDay, StartDate: day(-8), EndDate: day(-8)}}
+
+	tests := []struct {
+		name  string
+		dates []time.Time
+		want  int
+	}{
+		{"Empty", nil, 0},
+		{"LongestInPast", []time.Time{day(0), day(-5), day(-6), day(-7), day(-9)}, 4},
+		{"CurrentIsLongest", []time.Time{day(0), day(-1), day(-2), day(-5)}, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, LongestNormStreak(tt.dates, rest))
+		})
+	}
+}
+
+func TestEarnedAchievements(t *testing.T) {
+	codes := func(achievements []model.Achievement) []string {
+		var result []string
+		for _, a := range achievements {
+			result = append(result, a.Code)
+		}
+		return result
+	}
+
+	assert.Empty(t, EarnedAchievements(model.AchievementStats{}))
+
+	earned := EarnedAchievements(model.AchievementStats{
+		TotalPushups:     1500,
+		NormStreak:       7,
+		WeekStreak:       3,
+		FirstCompletions: 10,
+		MonthlyMaxGain:   9,
+		MaxReps:          50,
+	})
+
+	assert.Equal(t, []string{
+		"first_set", "total_1000", "streak_7",
+		"first_finisher", "first_finisher_10", "max_50",
+	}, codes(earned))
+}
+
+func TestAchievementCatalog_UniqueCodes(t *testing.T) {
+	seen := make(map[string]bool)
+	for _, rule := range achievementCatalog {
+		assert.False(t, seen[rule.Code], "duplicate achievement code %s", rule.Code)
+		assert.Positive(t, rule.Goal)
+		assert.NotZero(t, AchievementMetric(model.AchievementStats{
+			TotalPushups: 1, NormStreak: 1, WeekStreak: 1, FirstCompletions: 1, MonthlyMaxGain: 1, MaxReps: 1,
+		}, rule.Metric), "unknown metric %s", rule.Metric)
+		seen[rule.Code] = true
+	}
+}
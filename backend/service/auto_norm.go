@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"trackerbot/config"
+	"trackerbot/model"
+)
+
+// AdjustNorm подстраивает норму по доле дней, в которые она была выполнена.
+// Учитываются только завершённые дни окна (сегодняшний день не входит),
+// дни отдыха и отпуска из окна исключаются.
+// Аргументы:
+//
+//	totals    - суммы по дням за окно
+//	rest      - дни отдыха и отпуска
+//	dailyNorm - текущая норма
+//	formula   - параметры формулы нормы
+//	cfg       - пороги и шаг автоподстройки
+//	now       - текущее время
+//
+// Возвращает:
+//
+//	изменение нормы или nil, если норма остаётся прежней
+func AdjustNorm(
+	totals []model.DailyTotal,
+	rest []model.RestPeriod,
+	dailyNorm int,
+	formula NormFormula,
+	cfg config.AutoNormConfig,
+	now time.Time,
+) *model.NormAdjustment {
+
+	if dailyNorm <= 0 || cfg.WindowDays <= 0 {
+		return nil
+	}
+
+	today := dateOnly(now)
+	from := today.AddDate(0, 0, -cfg.WindowDays)
+
+	days := cfg.WindowDays - CountRestDays(rest, from, today)
+	if days <= 0 {
+		return nil
+	}
+
+	window := windowTotals(totals, rest, from, today)
+	rate := CompletionRate(window, dailyNorm, days)
+	completed := int(rate*float64(days) + 0.5)
+
+	adjustment := &model.NormAdjustment{
+		OldNorm:       dailyNorm,
+		CompletedDays: completed,
+		WindowDays:    days,
+	}
+
+	switch {
+	case rate >= cfg.RaiseRate:
+		adjustment.NewNorm = max(formula.round(float64(dailyNorm)*(1+cfg.StepRatio)), min(dailyNorm+5, formula.MaxDaily))
+		adjustment.Reason = fmt.Sprintf(
+			"норма выполнена в %d из %d последних дней — пора добавить нагрузку",
+			completed, days,
+		)
+	case rate < cfg.LowerRate:
+		adjustment.NewNorm = min(formula.round(float64(dailyNorm)*(1-cfg.StepRatio)), max(dailyNorm-5, formula.MinDaily))
+		adjustment.Reason = fmt.Sprintf(
+			"норма выполнена только в %d из %d последних дней — немного снизим планку",
+			completed, days,
+		)
+	default:
+		return nil
+	}
+
+	if adjustment.NewNorm == dailyNorm {
+		return nil
+	}
+
+	return adjustment
+}
+
+// RunAutoNorm проверяет нормы пользователей с включённой автоподстройкой
+// и возвращает применённые изменения
+func (s *pushupService) RunAutoNorm(ctx context.Context, cfg config.AutoNormConfig) ([]model.NormAdjustment, error) {
+	now := time.Now()
+
+	candidates, err := s.repo.GetAutoNormCandidates(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	from := dateOnly(now).AddDate(0, 0, -cfg.WindowDays)
+
+	var adjustments []model.NormAdjustment
+	for _, candidate := range candidates {
+		totals, err := s.repo.GetDailyTotals(ctx, candidate.UserID, from)
+		if err != nil {
+			log.Printf("Ошибка получения сумм по дням пользователя %d: %v", candidate.UserID, err)
+			continue
+		}
+
+		rest, err := s.repo.GetRestPeriods(ctx, candidate.UserID)
+		if err != nil {
+			log.Printf("Ошибка получения дней отдыха пользователя %d: %v", candidate.UserID, err)
+			continue
+		}
+
+		adjustment := AdjustNorm(totals, rest, candidate.DailyNorm, s.norm, cfg, now)
+		if adjustment == nil {
+			if err := s.repo.MarkAutoNormChecked(ctx, candidate.UserID); err != nil {
+				log.Printf("MarkAutoNormChecked error: %v", err)
+			}
+			continue
+		}
+
+		adjustment.UserID = candidate.UserID
+		if err := s.repo.ApplyNormAdjustment(ctx, *adjustment, model.NormSourceAuto); err != nil {
+			log.Printf("Ошибка автоподстройки нормы пользователя %d: %v", candidate.UserID, err)
+			continue
+		}
+
+		adjustments = append(adjustments, *adjustment)
+	}
+
+	return adjustments, nil
+}
+
+// GetAutoNorm сообщает, включена ли у пользователя автоподстройка нормы
+func (s *pushupService) GetAutoNorm(ctx context.Context, userID int64) (bool, error) {
+	return s.repo.GetAutoNorm(ctx, userID)
+}
+
+// SetAutoNorm включает или выключает автоподстройку нормы
+func (s *pushupService) SetAutoNorm(ctx context.Context, userID int64, enabled bool) error {
+	return s.repo.SetAutoNorm(ctx, userID, enabled)
+}
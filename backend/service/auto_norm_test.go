@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"trackerbot/config"
+	"trackerbot/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAdjustNorm(t *testing.T) {
+	now := time.Date(2026, 3, 20, 3, 0, 0, 0, time.UTC)
+	cfg := config.AutoNormConfig{WindowDays: 7, RaiseRate: 0.85, LowerRate: 0.3, StepRatio: 0.1}
+
+	// days дней подряд до вчерашнего включительно с суммой count
+	pastDays := func(days, count int) []model.DailyTotal {
+		totals := make([]model.DailyTotal, 0, days)
+		for i := 1; i <= days; i++ {
+			totals = append(totals, model.DailyTotal{Date: now.AddDate(0, 0, -i), Count: count})
+		}
+		return totals
+	}
+
+	tests := []struct {
+		name   string
+		totals []model.DailyTotal
+		norm   int
+		want   int // 0 — норма не меняется
+	}{
+		{"AllCompletedRaises", pastDays(7, 120), 100, 110},
+		{"SmallNormRaisesAtLeastFive", pastDays(7, 60), 45, 50},
+		{"RarelyCompletedLowers", pastDays(1, 120), 100, 90},
+		{"NothingDoneLowers", nil, 100, 90},
+		{"InBetweenKeeps", pastDays(4, 120), 100, 0},
+		{"TodayIgnored", append(pastDays(1, 120), model.DailyTotal{Date: now, Count: 500}), 100, 90},
+		{"MaxNormNotRaised", pastDays(7, 1000), DefaultNormFormula.MaxDaily, 0},
+		{"MinNormNotLowered", nil, DefaultNormFormula.MinDaily, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			adjustment := AdjustNorm(tt.totals, nil, tt.norm, DefaultNormFormula, cfg, now)
+			if tt.want == 0 {
+				assert.Nil(t, adjustment)
+				return
+			}
+			if assert.NotNil(t, adjustment) {
+				assert.Equal(t, tt.norm, adjustment.OldNorm)
+				assert.Equal(t, tt.want, adjustment.NewNorm)
+				assert.NotEmpty(t, adjustment.Reason)
+			}
+		})
+	}
+}
+
+func TestAdjustNorm_RestDays(t *testing.T) {
+	now := time.Date(2026, 3, 20, 3, 0, 0, 0, time.UTC)
+	cfg := config.AutoNormConfig{WindowDays: 7, RaiseRate: 0.85, LowerRate: 0.3, StepRatio: 0.1}
+	totals := []model.DailyTotal{
+		{Date: now.AddDate(0, 0, -1), Count: 120},
+		{Date: now.AddDate(0, 0, -2), Count: 120},
+	}
+
+	// Без учёта отпуска 2 из 7 дней — норму бы снизили
+	vacation := []model.RestPeriod{{
+		Kind:      model.RestKindVacation,
+		StartDate: now.AddDate(0, 0, -7),
+		EndDate:   now.AddDate(0, 0, -3),
+	}}
+
+	adjustment := AdjustNorm(totals, vacation, 100, DefaultNormFormula, cfg, now)
+	if assert.NotNil(t, adjustment) {
+		assert.Equal(t, 110, adjustment.NewNorm)
+		assert.Equal(t, 2, adjustment.WindowDays)
+	}
+
+	// Всё окно в отпуске — норма не меняется
+	wholeWindow := []model.RestPeriod{{
+		Kind:      model.RestKindVacation,
+		StartDate: now.AddDate(0, 0, -10),
+		EndDate:   now,
+	}}
+	assert.Nil(t, AdjustNorm(nil, wholeWindow, 100, DefaultNormFormula, cfg, now))
+}
+
+func TestService_RunAutoNorm(t *testing.T) {
+	mockRepo := new(MockPushupRepository)
+	svc := NewPushupService(mockRepo, Config{})
+	ctx := context.Background()
+	cfg := config.AutoNormConfig{WindowDays: 7, RaiseRate: 0.85, LowerRate: 0.3, StepRatio: 0.1}
+
+	mockRepo.On("GetAutoNormCandidates", ctx).Return([]model.AutoNormCandidate{
+		{UserID: 1, DailyNorm: 100},
+		{UserID: 2, DailyNorm: 100},
+	}, nil).Once()
+
+	var completed []model.DailyTotal
+	for i := 1; i <= 7; i++ {
+		completed = append(completed, model.DailyTotal{Date: time.Now().AddDate(0, 0, -i), Count: 150})
+	}
+	var partial []model.DailyTotal
+	for i := 1; i <= 4; i++ {
+		partial = append(partial, model.DailyTotal{Date: time.Now().AddDate(0, 0, -i), Count: 150})
+	}
+
+	mockRepo.On("GetDailyTotals", ctx, int64(1), mock.Anything).Return(completed, nil).Once()
+	mockRepo.On("GetDailyTotals", ctx, int64(2), mock.Anything).Return(partial, nil).Once()
+	mockRepo.On("GetRestPeriods", ctx, mock.Anything).Return([]model.RestPeriod(nil), nil).Twice()
+	mockRepo.On("ApplyNormAdjustment", ctx, mock.MatchedBy(func(a model.NormAdjustment) bool {
+		return a.UserID == 1 && a.OldNorm == 100 && a.NewNorm == 110
+	}), model.NormSourceAuto).Return(nil).Once()
+	mockRepo.On("MarkAutoNormChecked", ctx, int64(2)).Return(nil).Once()
+
+	adjustments, err := svc.RunAutoNorm(ctx, cfg)
+
+	assert.NoError(t, err)
+	if assert.Len(t, adjustments, 1) {
+		assert.Equal(t, int64(1), adjustments[0].UserID)
+	}
+	mockRepo.AssertExpectations(t)
+}
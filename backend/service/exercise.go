@@ -0,0 +1,121 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"trackerbot/model"
+)
+
+// GetExercises возвращает каталог упражнений
+func (s *pushupService) GetExercises(ctx context.Context) ([]model.Exercise, error) {
+	return s.repo.GetExercises(ctx)
+}
+
+// GetExercise возвращает упражнение по коду
+func (s *pushupService) GetExercise(ctx context.Context, code string) (model.Exercise, error) {
+	return s.repo.GetExercise(ctx, code)
+}
+
+// AddExerciseReps добавляет повторения (секунды) упражнения.
+// Отжимания идут через AddPushups, чтобы сохранить проверки и рейтинг.
+func (s *pushupService) AddExerciseReps(
+	ctx context.Context,
+	userID int64,
+	code string,
+	count int,
+) (*model.AddPushupsViewModel, error) {
+
+	if code == model.ExercisePushups {
+		return s.AddPushups(ctx, userID, count)
+	}
+
+	userExercise, err := s.repo.GetUserExercise(ctx, userID, code)
+	if err != nil {
+		return nil, err
+	}
+
+	total, err := s.repo.AddExerciseReps(ctx, userID, code, count)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка сохранения в БД: %w", err)
+	}
+
+	vm := &model.AddPushupsViewModel{
+		AddedCount: count,
+		Total:      total,
+		DailyNorm:  userExercise.DailyNorm,
+		Completed:  userExercise.DailyNorm > 0 && total >= userExercise.DailyNorm,
+		Exercise:   &userExercise.Exercise,
+	}
+
+	return vm, nil
+}
+
+// UpdateExerciseMax сохраняет результат теста по упражнению и пересчитывает норму
+func (s *pushupService) UpdateExerciseMax(
+	ctx context.Context,
+	userID int64,
+	code string,
+	count int,
+) (*model.MaxRepsViewModel, error) {
+
+	if code == model.ExercisePushups {
+		return s.UpdateMaxReps(ctx, userID, count)
+	}
+
+	exercise, err := s.repo.GetExercise(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+
+	dailyNorm := s.norm.ExerciseNorm(exercise, count)
+	if err := s.repo.SetExerciseMax(ctx, userID, code, count, dailyNorm); err != nil {
+		return nil, fmt.Errorf("ошибка сохранения в историю: %w", err)
+	}
+
+	history, err := s.repo.GetExerciseHistory(ctx, userID, code)
+	if err != nil {
+		return nil, err
+	}
+	record, err := s.repo.GetExerciseRecord(ctx, userID, code)
+	if err != nil {
+		return nil, err
+	}
+
+	vm := &model.MaxRepsViewModel{
+		Count:     count,
+		DailyNorm: dailyNorm,
+		History:   history,
+		Record:    &record,
+		Exercise:  &exercise,
+	}
+
+	return vm, nil
+}
+
+// SetExerciseNorm устанавливает дневную норму по упражнению вручную
+func (s *pushupService) SetExerciseNorm(ctx context.Context, userID int64, code string, dailyNorm int) error {
+	if code == model.ExercisePushups {
+		return s.repo.SetDailyNorm(ctx, userID, dailyNorm, model.NormSourceManual)
+	}
+	return s.repo.SetExerciseNorm(ctx, userID, code, dailyNorm)
+}
+
+// GetExerciseHistory возвращает историю максимумов по упражнению
+func (s *pushupService) GetExerciseHistory(ctx context.Context, userID int64, code string) ([]model.MaxRepsHistoryItem, error) {
+	if code == model.ExercisePushups {
+		return s.repo.GetMaxRepsHistory(ctx, userID)
+	}
+	return s.repo.GetExerciseHistory(ctx, userID, code)
+}
+
+// BuildExerciseSchedule строит график прогресса по упражнению с учётом единиц измерения
+func (s *pushupService) BuildExerciseSchedule(
+	ctx context.Context,
+	exercise model.Exercise,
+	history []model.MaxRepsHistoryItem,
+) (bytes.Buffer, error) {
+
+	return SendExerciseSchedule(exercise, history)
+}
@@ -2,6 +2,9 @@ package service
 
 import (
 	"math"
+
+	"trackerbot/config"
+	"trackerbot/model"
 )
 
 const (
@@ -27,7 +30,66 @@ const (
 	RecoveryHours      = 48  // Часы отдыха между тренировками
 )
 
-// CalculateDailyNorm рассчитывает дневную норму с уменьшающимся коэффициентом
+// NormLevel — базовый коэффициент ACSM для уровня подготовки (максимум за подход ≤ MaxReps)
+type NormLevel struct {
+	MaxReps int
+	Base    float64
+}
+
+// NormFormula параметры формулы дневной нормы
+type NormFormula struct {
+	MinDaily        int         // Минимальная дневная норма
+	MaxDaily        int         // Максимальный безопасный предел
+	MaxRepsCap      int         // Выше этого максимума норма сразу равна MaxDaily
+	BaseCoefficient float64     // Стартовый коэффициент
+	CoefficientStep float64     // Шаг уменьшения коэффициента
+	MinCoefficient  float64     // Минимальный коэффициент
+	Levels          []NormLevel // Уровни подготовки по возрастанию
+	TopBase         float64     // Базовый коэффициент выше последнего уровня
+}
+
+// DefaultNormFormula используется, если в config.yml не задан раздел norm
+var DefaultNormFormula = NormFormula{
+	MinDaily:        MinDailyPushups,
+	MaxDaily:        MaxDailyPushups,
+	MaxRepsCap:      100,
+	BaseCoefficient: BaseCoefficient,
+	CoefficientStep: CoefficientStep,
+	MinCoefficient:  MinCoefficient,
+	Levels: []NormLevel{
+		{StartingThreshold, 4},        // Новички (ACSM: 30-50): (30+50)/2 / 10 = 4
+		{BeginnerThreshold, 2.5},      // Начальный уровень (ACSM: 40-60): (40+60)/2 / 20 = 2.5
+		{IntermediateThreshold, 2.33}, // Средний уровень (ACSM: 60-80): (60+80)/2 / 30 ≈ 2.33
+		{AdvancedThreshold, 2.5},      // Интенсивные тренировки (ACSM: 80-120): (80+120)/2 / 40 = 2.5
+		{ExpertThreshold, 2.7},        // Продвинутые (ACSM: 120-150): (120+150)/2 / 50 = 2.7
+	},
+	TopBase: 2.5, // Профессионалы (ACSM: 150-250): (150+250)/2 / 80 ≈ 2.5
+}
+
+// NewNormFormula собирает параметры формулы нормы из конфигурации;
+// если раздел norm не задан, возвращает DefaultNormFormula
+func NewNormFormula(cfg config.NormConfig) NormFormula {
+	if !cfg.IsSet() {
+		return DefaultNormFormula
+	}
+
+	formula := NormFormula{
+		MinDaily:        cfg.MinDaily,
+		MaxDaily:        cfg.MaxDaily,
+		MaxRepsCap:      cfg.MaxRepsCap,
+		BaseCoefficient: cfg.BaseCoefficient,
+		CoefficientStep: cfg.CoefficientStep,
+		MinCoefficient:  cfg.MinCoefficient,
+		TopBase:         cfg.TopBase,
+	}
+	for _, level := range cfg.Levels {
+		formula.Levels = append(formula.Levels, NormLevel{MaxReps: level.MaxReps, Base: level.Base})
+	}
+
+	return formula
+}
+
+// DailyNorm рассчитывает дневную норму с уменьшающимся коэффициентом
 // Аргументы:
 //
 //	maxReps - максимальное количество отжиманий за один подход
@@ -35,53 +97,63 @@ const (
 // Возвращает:
 //
 //	дневную норму (целое число, кратное 5)
-func CalculateDailyNorm(maxReps int) int {
+func (f NormFormula) DailyNorm(maxReps int) int {
 	// Защита от нереалистичных значений
 	if maxReps <= 0 {
-		return MinDailyPushups
+		return f.MinDaily
 	}
-	if maxReps > 100 {
-		return MaxDailyPushups
+	if maxReps > f.MaxRepsCap {
+		return f.MaxDaily
 	}
 
 	// Прогрессивная формула с уменьшающимся коэффициентом
-	coefficient := getSmoothCoefficient(maxReps)
+	coefficient := f.smoothCoefficient(maxReps)
 	rawNorm := float64(maxReps) * coefficient
 
 	// Округление до ближайшего кратного 5
 	norm := int(math.Round(rawNorm/5)) * 5
 
 	// Применение граничных условий
-	return clamp(norm, MinDailyPushups, MaxDailyPushups)
+	return clamp(norm, f.MinDaily, f.MaxDaily)
 }
 
-func getSmoothCoefficient(maxReps int) float64 {
+// ExerciseNorm рассчитывает дневную норму для упражнения из каталога.
+// Для отжиманий используется формула ACSM (DailyNorm),
+// для остальных упражнений — множитель norm_factor из каталога.
+// Результат кратен 5 и не меньше максимума за подход.
+func (f NormFormula) ExerciseNorm(exercise model.Exercise, maxReps int) int {
+	if exercise.Code == model.ExercisePushups || exercise.Code == "" {
+		return f.DailyNorm(maxReps)
+	}
+	if maxReps <= 0 {
+		return 0
+	}
+
+	norm := int(math.Round(float64(maxReps)*exercise.NormFactor/5)) * 5
+	if norm < maxReps {
+		return maxReps
+	}
+	return norm
+}
+
+func (f NormFormula) smoothCoefficient(maxReps int) float64 {
 	// Определяем базовый коэффициент на основе средних значений ACSM
 	// Согласно рекомендациям ACSM (American College of Sports Medicine)
-	var base float64
-
-	switch {
-	case maxReps <= StartingThreshold: // Новички (ACSM: 30-50)
-		base = 4 // (30+50)/2 / 10 = 4
-	case maxReps <= BeginnerThreshold: // Начальный уровень (ACSM: 40-60)
-		base = 2.5 // (40+60)/2 / 20 = 2.5
-	case maxReps <= IntermediateThreshold: // Средний уровень (ACSM: 60-80)
-		base = 2.33 // (60+80)/2 / 30 ≈ 2.33
-	case maxReps <= AdvancedThreshold: // Интенсивные тренировки (ACSM: 80-120)
-		base = 2.5 // (80+120)/2 / 40 = 2.5
-	case maxReps <= ExpertThreshold: // Продвинутые (ACSM: 120-150)
-		base = 2.7 // (120+150)/2 / 50 = 2.7
-	default: // Профессионалы (ACSM: 150-250)
-		base = 2.5 // (150+250)/2 / 80 ≈ 2.5 (для maxReps=80)
+	base := f.TopBase
+	for _, level := range f.Levels {
+		if maxReps <= level.MaxReps {
+			base = level.Base
+			break
+		}
 	}
 
 	// Плавное уменьшение коэффициента между границами
-	smoothBase := BaseCoefficient - CoefficientStep*float64(maxReps)
+	smoothBase := f.BaseCoefficient - f.CoefficientStep*float64(maxReps)
 
 	// Компромисс между плавностью и соответствием ACSM
 	finalCoeff := (base + smoothBase) / 2
 
-	return math.Max(math.Min(finalCoeff, BaseCoefficient), MinCoefficient)
+	return math.Max(math.Min(finalCoeff, f.BaseCoefficient), f.MinCoefficient)
 }
 
 // Вспомогательная функция для ограничения диапазона
@@ -95,8 +167,7 @@ func clamp(value, min, max int) int {
 	return value
 }
 
-// GetUserRank определяет ранг пользователя на основе его maxReps
-// Константы для порогов рангов
+// Константы для порогов рангов по умолчанию
 const (
 	RankSleepyFly    = 0
 	RankSprout       = 5   // +5
@@ -112,62 +183,67 @@ const (
 	LordOfPushUps    = 100 // +20
 )
 
-type UserRank struct {
-	threshold int
-	rank      string
-}
+// Ranks — лестница рангов в порядке возрастания порогов
+type Ranks []model.RankInfo
 
-// Ранги пользователя в порядке возрастания
-var userRanks = []UserRank{
-	{RankSleepyFly, "💤 Сонная муха"},
-	{RankSprout, "🌱 Росток силы"},
-	{RankWorker, "🐜 Трудяга"},
-	{RankTrainee, "🚀 Стажёр космоса"},
-	{RankRocket, "🚀 Ракета-носитель"},
-	{RankKnight, "⚔️ Рыцарь света"},
-	{RankImpenetrable, "🛡️ Непробиваемый"},
-	{RankThunder, "⚡ Гроза пола"},
-	{RankAdept, "🏹 Адепт упорства"},
-	{RankGravity, "🌌 Победитель гравитации"},
-	{RankLegend, "🏆 Легенда горизонтов"},
-	{LordOfPushUps, "🌟 ВЛАСТЕЛИН ОТЖИМАНИЙ"},
+// DefaultRanks используется, если в config.yml не задан раздел ranks
+var DefaultRanks = Ranks{
+	{Threshold: RankSleepyFly, Emoji: "💤", Name: "Сонная муха", Frequency: "1–2 тренировки в неделю"},
+	{Threshold: RankSprout, Emoji: "🌱", Name: "Росток силы", Frequency: "2–3 тренировки в неделю"},
+	{Threshold: RankWorker, Emoji: "🐜", Name: "Трудяга", Frequency: "2–3 тренировки в неделю"},
+	{Threshold: RankTrainee, Emoji: "🚀", Name: "Стажёр космоса", Frequency: "3 тренировки в неделю"},
+	{Threshold: RankRocket, Emoji: "🚀", Name: "Ракета-носитель", Frequency: "3–4 тренировки в неделю"},
+	{Threshold: RankKnight, Emoji: "⚔️", Name: "Рыцарь света", Frequency: "3–4 тренировки в неделю"},
+	{Threshold: RankImpenetrable, Emoji: "🛡️", Name: "Непробиваемый", Frequency: "3–4 тренировки в неделю"},
+	{Threshold: RankThunder, Emoji: "⚡", Name: "Гроза пола", Frequency: "4–5 тренировок в неделю"},
+	{Threshold: RankAdept, Emoji: "🏹", Name: "Адепт упорства", Frequency: "4–5 тренировок в неделю"},
+	{Threshold: RankGravity, Emoji: "🌌", Name: "Победитель гравитации", Frequency: "4–6 тренировок в неделю"},
+	{Threshold: RankLegend, Emoji: "🏆", Name: "Легенда горизонтов", Frequency: "4–6 тренировок в неделю"},
+	{Threshold: LordOfPushUps, Emoji: "🌟", Name: "ВЛАСТЕЛИН ОТЖИМАНИЙ", Frequency: "5–6 тренировок в неделю"},
 }
 
-// GetUserRank определяет ранг пользователя на основе его maxReps
-func GetUserRank(maxReps int) string {
-	for i := len(userRanks) - 1; i >= 0; i-- {
-		if maxReps >= userRanks[i].threshold {
-			return userRanks[i].rank
-		}
+// NewRanks собирает лестницу рангов из конфигурации;
+// если раздел ranks не задан, возвращает DefaultRanks
+func NewRanks(ranks []config.RankConfig) Ranks {
+	if len(ranks) == 0 {
+		return DefaultRanks
+	}
+
+	ladder := make(Ranks, 0, len(ranks))
+	for _, r := range ranks {
+		ladder = append(ladder, model.RankInfo{
+			Threshold: r.Threshold,
+			Emoji:     r.Emoji,
+			Name:      r.Name,
+			Frequency: r.Frequency,
+		})
 	}
-	return "🌟 ВЛАСТЕЛИН ОТЖИМАНИЙ"
+
+	return ladder
 }
 
-// GetRepsToNextRank возвращает количество отжиманий до следующего ранга
-func GetRepsToNextRank(maxReps int) int {
-	currentRankIndex := -1
+// Title определяет ранг пользователя на основе его maxReps
+func (r Ranks) Title(maxReps int) string {
+	return r[r.index(maxReps)].Title()
+}
 
-	// Находим индекс текущего ранга
-	for i := len(userRanks) - 1; i >= 0; i-- {
-		if maxReps >= userRanks[i].threshold {
-			currentRankIndex = i
-			break
-		}
-	}
+// Name возвращает название ранга без эмодзи
+func (r Ranks) Name(maxReps int) string {
+	return r[r.index(maxReps)].Name
+}
 
-	// Если текущий ранг - последний или пользователь вышел за пределы
-	if currentRankIndex == len(userRanks)-1 || maxReps > LordOfPushUps {
-		return 0
-	}
+// RepsToNext возвращает количество отжиманий до следующего ранга
+func (r Ranks) RepsToNext(maxReps int) int {
+	currentRankIndex := r.index(maxReps)
 
-	// Если не нашли ранг (маловероятно, но на всякий случай)
-	if currentRankIndex == -1 {
-		return RankSprout - maxReps
+	// Если текущий ранг - последний
+	if currentRankIndex == len(r)-1 {
+		return 0
 	}
 
 	// Следующий ранг
-	nextRank := userRanks[currentRankIndex+1]
-	return nextRank.threshold - maxReps
+	nextRank := r[currentRankIndex+1]
+	return nextRank.Threshold - maxReps
 }
 
 // CalculateNextTarget рассчитывает, на сколько минимум нужно увеличить maxReps на новой неделе.
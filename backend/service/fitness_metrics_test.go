@@ -3,10 +3,13 @@ package service
 import (
 	"testing"
 
+	"trackerbot/config"
+	"trackerbot/model"
+
 	"github.com/stretchr/testify/assert"
 )
 
-func TestCalculateDailyNorm(t *testing.T) {
+func TestNormFormula_DailyNorm(t *testing.T) {
 	tests := []struct {
 		maxReps int
 		want    int
@@ -25,13 +28,13 @@ func TestCalculateDailyNorm(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run("", func(t *testing.T) {
-			got := CalculateDailyNorm(tt.maxReps)
+			got := DefaultNormFormula.DailyNorm(tt.maxReps)
 			assert.Equal(t, tt.want, got)
 		})
 	}
 }
 
-func TestGetUserRank(t *testing.T) {
+func TestRanks_Title(t *testing.T) {
 	tests := []struct {
 		maxReps int
 		want    string
@@ -48,13 +51,13 @@ func TestGetUserRank(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run("", func(t *testing.T) {
-			got := GetUserRank(tt.maxReps)
+			got := DefaultRanks.Title(tt.maxReps)
 			assert.Equal(t, tt.want, got)
 		})
 	}
 }
 
-func TestGetRepsToNextRank(t *testing.T) {
+func TestRanks_RepsToNext(t *testing.T) {
 	tests := []struct {
 		maxReps int
 		want    int
@@ -69,7 +72,7 @@ func TestGetRepsToNextRank(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run("", func(t *testing.T) {
-			got := GetRepsToNextRank(tt.maxReps)
+			got := DefaultRanks.RepsToNext(tt.maxReps)
 			assert.Equal(t, tt.want, got)
 		})
 	}
@@ -102,3 +105,76 @@ func TestCalculateNextTarget(t *testing.T) {
 		})
 	}
 }
+
+func TestNormFormula_ExerciseNorm(t *testing.T) {
+	pushups := model.Exercise{Code: model.ExercisePushups}
+	pullups := model.Exercise{Code: "pullups", NormFactor: 2.5}
+	plank := model.Exercise{Code: "plank", Unit: model.UnitSeconds, NormFactor: 3}
+
+	tests := []struct {
+		name     string
+		exercise model.Exercise
+		maxReps  int
+		want     int
+	}{
+		{"PushupsUseACSM", pushups, 25, DefaultNormFormula.DailyNorm(25)},
+		{"PullupsZero", pullups, 0, 0},
+		{"Pullups", pullups, 8, 20},
+		{"PlankSeconds", plank, 45, 135},
+		{"NotBelowMax", model.Exercise{Code: "x", NormFactor: 0.5}, 20, 20},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, DefaultNormFormula.ExerciseNorm(tt.exercise, tt.maxReps))
+		})
+	}
+}
+
+func TestNewRanks(t *testing.T) {
+	ranks := NewRanks([]config.RankConfig{
+		{Threshold: 0, Emoji: "🐣", Name: "Новичок"},
+		{Threshold: 20, Emoji: "🦅", Name: "Орёл"},
+	})
+
+	assert.Equal(t, "🐣 Новичок", ranks.Title(19))
+	assert.Equal(t, "🦅 Орёл", ranks.Title(20))
+	assert.Equal(t, 5, ranks.RepsToNext(15))
+	assert.Equal(t, 0, ranks.RepsToNext(50))
+
+	assert.Equal(t, DefaultRanks, NewRanks(nil))
+}
+
+func TestNewNormFormula(t *testing.T) {
+	// Конфигурация по умолчанию из config.yml совпадает со встроенной формулой
+	formula := NewNormFormula(config.NormConfig{
+		MinDaily:        40,
+		MaxDaily:        250,
+		MaxRepsCap:      100,
+		BaseCoefficient: 5.0,
+		CoefficientStep: 0.025,
+		MinCoefficient:  2.5,
+		Levels: []config.NormLevelConfig{
+			{MaxReps: 10, Base: 4}, {MaxReps: 20, Base: 2.5}, {MaxReps: 30, Base: 2.33},
+			{MaxReps: 40, Base: 2.5}, {MaxReps: 50, Base: 2.7},
+		},
+		TopBase: 2.5,
+	})
+	assert.Equal(t, 85, formula.DailyNorm(25))
+	assert.Equal(t, 220, formula.DailyNorm(80))
+
+	// Пониженный потолок
+	formula = NewNormFormula(config.NormConfig{
+		MinDaily:        20,
+		MaxDaily:        100,
+		MaxRepsCap:      50,
+		BaseCoefficient: 3,
+		MinCoefficient:  3,
+		TopBase:         3,
+	})
+	assert.Equal(t, 20, formula.DailyNorm(0))
+	assert.Equal(t, 30, formula.DailyNorm(10))
+	assert.Equal(t, 100, formula.DailyNorm(60))
+
+	assert.Equal(t, DefaultNormFormula, NewNormFormula(config.NormConfig{}))
+}
@@ -0,0 +1,206 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"trackerbot/model"
+)
+
+const (
+	GTGSetRatio           = 0.5 // Микроподход — половина максимума, без отказа
+	MinGTGIntervalMinutes = 15  // Чаще напоминать нет смысла: мышцы не успевают восстановиться
+	MaxGTGIntervalMinutes = 240
+	MaxGTGSetSize         = 100
+)
+
+// GTGSetSize возвращает размер микроподхода: заданный пользователем или половину максимума
+func GTGSetSize(settings model.GTGSettings, maxReps int) int {
+	if settings.SetSize > 0 {
+		return settings.SetSize
+	}
+	return max(int(float64(maxReps)*GTGSetRatio), 1)
+}
+
+// ParseGTGWindow разбирает окно вида "10:00-18:00" в минуты от полуночи
+func ParseGTGWindow(window string) (int, int, error) {
+	from, to, ok := strings.Cut(strings.TrimSpace(window), "-")
+	if !ok {
+		return 0, 0, fmt.Errorf("окно задаётся так: 10:00-18:00")
+	}
+
+	start, err := parseClock(from)
+	if err != nil {
+		return 0, 0, err
+	}
+	end, err := parseClock(to)
+	if err != nil {
+		return 0, 0, err
+	}
+	if start >= end {
+		return 0, 0, fmt.Errorf("начало окна должно быть раньше конца")
+	}
+
+	return start, end, nil
+}
+
+// parseClock переводит "ЧЧ:ММ" в минуты от полуночи; "24:00" — конец суток
+func parseClock(value string) (int, error) {
+	value = strings.TrimSpace(value)
+	if value == "24:00" {
+		return 24 * 60, nil
+	}
+
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("неверное время: %s", value)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// InGTGWindow проверяет, что время попадает в окно [WindowStart, WindowEnd)
+func InGTGWindow(settings model.GTGSettings, t time.Time) bool {
+	minutes := t.Hour()*60 + t.Minute()
+	return minutes >= settings.WindowStart && minutes < settings.WindowEnd
+}
+
+// gtgWindowStart возвращает ближайшее после t начало окна (сегодня или завтра)
+func gtgWindowStart(settings model.GTGSettings, t time.Time) time.Time {
+	start := time.Date(t.Year(), t.Month(), t.Day(), 0, settings.WindowStart, 0, 0, t.Location())
+	if t.Before(start) {
+		return start
+	}
+	return start.AddDate(0, 0, 1)
+}
+
+// NextGTGPrompt считает время следующего напоминания после after:
+// через интервал, если он ещё попадает в окно, иначе — в начале следующего окна
+func NextGTGPrompt(settings model.GTGSettings, after time.Time) time.Time {
+	if InGTGWindow(settings, after) {
+		next := after.Add(time.Duration(settings.IntervalMinutes) * time.Minute)
+		if InGTGWindow(settings, next) && next.YearDay() == after.YearDay() {
+			return next
+		}
+		return gtgWindowStart(settings, next)
+	}
+	return gtgWindowStart(settings, after)
+}
+
+// GetGTG возвращает настройки grease-the-groove с рассчитанным размером подхода
+func (s *pushupService) GetGTG(ctx context.Context, userID int64) (model.GTGPrompt, error) {
+	settings, err := s.repo.GetGTGSettings(ctx, userID)
+	if err != nil {
+		return model.GTGPrompt{}, err
+	}
+
+	maxReps, err := s.repo.GetUserMaxReps(ctx, userID)
+	if err != nil {
+		return model.GTGPrompt{}, err
+	}
+
+	return model.GTGPrompt{
+		UserID:   userID,
+		Settings: settings,
+		MaxReps:  maxReps,
+		SetSize:  GTGSetSize(settings, maxReps),
+	}, nil
+}
+
+// EnableGTG включает напоминания о микроподходах
+// Аргументы:
+//
+//	window          - окно вида "10:00-18:00"
+//	intervalMinutes - интервал между напоминаниями
+//	setSize         - размер подхода (0 — половина максимума)
+func (s *pushupService) EnableGTG(
+	ctx context.Context,
+	userID int64,
+	window string,
+	intervalMinutes int,
+	setSize int,
+) (model.GTGPrompt, error) {
+
+	start, end, err := ParseGTGWindow(window)
+	if err != nil {
+		return model.GTGPrompt{}, err
+	}
+	if intervalMinutes < MinGTGIntervalMinutes || intervalMinutes > MaxGTGIntervalMinutes {
+		return model.GTGPrompt{}, fmt.Errorf("интервал должен быть от %d до %d минут", MinGTGIntervalMinutes, MaxGTGIntervalMinutes)
+	}
+	if setSize < 0 || setSize > MaxGTGSetSize {
+		return model.GTGPrompt{}, fmt.Errorf("размер подхода должен быть от 1 до %d", MaxGTGSetSize)
+	}
+
+	maxReps, err := s.repo.GetUserMaxReps(ctx, userID)
+	if err != nil {
+		return model.GTGPrompt{}, err
+	}
+	if setSize == 0 && maxReps <= 0 {
+		return model.GTGPrompt{}, fmt.Errorf("сначала пройдите тест максимальных отжиманий или укажите размер подхода")
+	}
+
+	settings := model.GTGSettings{
+		Enabled:         true,
+		WindowStart:     start,
+		WindowEnd:       end,
+		IntervalMinutes: intervalMinutes,
+		SetSize:         setSize,
+	}
+	next := NextGTGPrompt(settings, time.Now())
+	settings.NextPromptAt = &next
+
+	if err := s.repo.SaveGTGSettings(ctx, userID, settings); err != nil {
+		return model.GTGPrompt{}, err
+	}
+
+	return model.GTGPrompt{
+		UserID:   userID,
+		Settings: settings,
+		MaxReps:  maxReps,
+		SetSize:  GTGSetSize(settings, maxReps),
+	}, nil
+}
+
+// DisableGTG выключает напоминания; окно и интервал сохраняются
+func (s *pushupService) DisableGTG(ctx context.Context, userID int64) error {
+	settings, err := s.repo.GetGTGSettings(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	settings.Enabled = false
+	settings.NextPromptAt = nil
+	return s.repo.SaveGTGSettings(ctx, userID, settings)
+}
+
+// GetDueGTGPrompts возвращает напоминания, которые пора отправить.
+// Просроченные напоминания вне окна (например, после простоя бота) не отправляются,
+// а переносятся на начало следующего окна
+func (s *pushupService) GetDueGTGPrompts(ctx context.Context, now time.Time) ([]model.GTGPrompt, error) {
+	due, err := s.repo.GetDueGTGPrompts(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+
+	prompts := make([]model.GTGPrompt, 0, len(due))
+	for _, prompt := range due {
+		if !InGTGWindow(prompt.Settings, now) {
+			if err := s.repo.SetGTGNextPrompt(ctx, prompt.UserID, gtgWindowStart(prompt.Settings, now)); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		prompt.SetSize = GTGSetSize(prompt.Settings, prompt.MaxReps)
+		prompts = append(prompts, prompt)
+	}
+
+	return prompts, nil
+}
+
+// MarkGTGPrompted планирует следующее напоминание после отправленного
+func (s *pushupService) MarkGTGPrompted(ctx context.Context, prompt model.GTGPrompt, now time.Time) error {
+	return s.repo.SetGTGNextPrompt(ctx, prompt.UserID, NextGTGPrompt(prompt.Settings, now))
+}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"trackerbot/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestParseGTGWindow(t *testing.T) {
+	start, end, err := ParseGTGWindow("10:00-18:30")
+	assert.NoError(t, err)
+	assert.Equal(t, 600, start)
+	assert.Equal(t, 1110, end)
+
+	start, end, err = ParseGTGWindow("8:00-24:00")
+	assert.NoError(t, err)
+	assert.Equal(t, 480, start)
+	assert.Equal(t, 1440, end)
+
+	for _, window := range []string{"", "10:00", "18:00-10:00", "10:00-10:00", "25:00-26:00", "утро-вечер"} {
+		_, _, err := ParseGTGWindow(window)
+		assert.Error(t, err, window)
+	}
+}
+
+func TestGTGSetSize(t *testing.T) {
+	assert.Equal(t, 15, GTGSetSize(model.GTGSettings{}, 30))
+	assert.Equal(t, 8, GTGSetSize(model.GTGSettings{SetSize: 8}, 30))
+	assert.Equal(t, 1, GTGSetSize(model.GTGSettings{}, 1))
+}
+
+func TestNextGTGPrompt(t *testing.T) {
+	settings := model.GTGSettings{WindowStart: 10 * 60, WindowEnd: 18 * 60, IntervalMinutes: 90}
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2026, 3, day, hour, minute, 0, 0, time.Local)
+	}
+
+	tests := []struct {
+		name  string
+		after time.Time
+		want  time.Time
+	}{
+		{"внутри окна — через интервал", at(10, 12, 0), at(10, 13, 30)},
+		{"до окна — начало окна", at(10, 7, 15), at(10, 10, 0)},
+		{"после окна — завтра", at(10, 19, 0), at(11, 10, 0)},
+		{"интервал выходит за окно — завтра", at(10, 17, 0), at(11, 10, 0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, NextGTGPrompt(settings, tt.after))
+		})
+	}
+}
+
+func TestService_EnableGTG(t *testing.T) {
+	mockRepo := new(MockPushupRepository)
+	svc := NewPushupService(mockRepo, Config{})
+	ctx := context.Background()
+
+	mockRepo.On("GetUserMaxReps", ctx, int64(1)).Return(24, nil).Once()
+	mockRepo.On("SaveGTGSettings", ctx, int64(1), mock.MatchedBy(func(s model.GTGSettings) bool {
+		return s.Enabled && s.WindowStart == 600 && s.WindowEnd == 1080 &&
+			s.IntervalMinutes == 60 && s.SetSize == 0 && s.NextPromptAt != nil
+	})).Return(nil).Once()
+
+	gtg, err := svc.EnableGTG(ctx, 1, "10:00-18:00", 60, 0)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 12, gtg.SetSize)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestService_EnableGTG_Validation(t *testing.T) {
+	mockRepo := new(MockPushupRepository)
+	svc := NewPushupService(mockRepo, Config{})
+	ctx := context.Background()
+
+	_, err := svc.EnableGTG(ctx, 1, "10:00-18:00", 5, 0)
+	assert.Error(t, err)
+
+	_, err = svc.EnableGTG(ctx, 1, "18:00-10:00", 60, 0)
+	assert.Error(t, err)
+
+	// Без теста максимума размер подхода нужно указать явно
+	mockRepo.On("GetUserMaxReps", ctx, int64(1)).Return(0, nil).Once()
+	_, err = svc.EnableGTG(ctx, 1, "10:00-18:00", 60, 0)
+	assert.Error(t, err)
+
+	mockRepo.AssertNotCalled(t, "SaveGTGSettings", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestService_GetDueGTGPrompts_ReschedulesOutsideWindow(t *testing.T) {
+	mockRepo := new(MockPushupRepository)
+	svc := NewPushupService(mockRepo, Config{})
+	ctx := context.Background()
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.Local)
+
+	inWindow := model.GTGPrompt{
+		UserID:   1,
+		Settings: model.GTGSettings{Enabled: true, WindowStart: 600, WindowEnd: 1080, IntervalMinutes: 60},
+		MaxReps:  20,
+	}
+	// Окно уже закончилось (бот простаивал) — напоминание переносится на завтра
+	missed := model.GTGPrompt{
+		UserID:   2,
+		Settings: model.GTGSettings{Enabled: true, WindowStart: 480, WindowEnd: 660, IntervalMinutes: 30},
+		MaxReps:  30,
+	}
+
+	mockRepo.On("GetDueGTGPrompts", ctx, now).Return([]model.GTGPrompt{inWindow, missed}, nil).Once()
+	mockRepo.On("SetGTGNextPrompt", ctx, int64(2), time.Date(2026, 3, 11, 8, 0, 0, 0, time.Local)).Return(nil).Once()
+
+	prompts, err := svc.GetDueGTGPrompts(ctx, now)
+
+	assert.NoError(t, err)
+	assert.Len(t, prompts, 1)
+	assert.Equal(t, int64(1), prompts[0].UserID)
+	assert.Equal(t, 10, prompts[0].SetSize)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestService_AddGTGSet_MarksSet(t *testing.T) {
+	mockRepo := new(MockPushupRepository)
+
+	mockRepo.On("GetDailyNorm", mock.Anything, int64(1)).Return(100, nil).Once()
+	mockRepo.On("GetTodayStat", mock.Anything, int64(1)).Return(0, nil).Once()
+	mockRepo.On("GetUserMaxReps", mock.Anything, int64(1)).Return(30, nil).Twice()
+	mockRepo.On("GetMaxRepsRecord", mock.Anything, int64(1)).
+		Return(model.MaxRepsHistoryItem{MaxReps: 30}, nil).Once()
+	mockRepo.On("AddPushupSet", mock.Anything, int64(1), model.VariantStandard, 25, 25, true).Return(25, nil).Once()
+	mockRepo.On("GetTodaySets", mock.Anything, int64(1)).
+		Return([]model.LoggedSet{{Variant: model.VariantStandard, Count: 25, GTG: true}}, nil).Once()
+	mockRepo.On("GetFirstNormCompleter", mock.Anything).Return(int64(0), nil).Once()
+	expectNoAchievements(mockRepo, 1)
+
+	service := NewPushupService(mockRepo, Config{})
+
+	vm, err := service.AddGTGSet(context.Background(), 1, 25)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 25, vm.Total)
+	// Микроподход идёт в сумму, но план подходов не закрывает
+	if assert.NotNil(t, vm.Plan) {
+		assert.Zero(t, vm.Plan.DoneSets())
+	}
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,180 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image/color"
+	"time"
+
+	"trackerbot/model"
+
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/font"
+	"gonum.org/v1/plot/text"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+)
+
+// HeatmapWeeks — сколько недель показывает календарь активности
+const HeatmapWeeks = 53
+
+// Размеры календаря активности
+const (
+	heatmapCell   = vg.Length(11)
+	heatmapStep   = vg.Length(13)
+	heatmapLeft   = vg.Length(34)
+	heatmapTop    = vg.Length(44)
+	heatmapBottom = vg.Length(52)
+)
+
+// heatmapColors — цвета уровней активности, от пустого дня до полуторной нормы
+var heatmapColors = []color.Color{
+	color.RGBA{R: 235, G: 237, B: 240, A: 255},
+	color.RGBA{R: 155, G: 233, B: 168, A: 255},
+	color.RGBA{R: 64, G: 196, B: 99, A: 255},
+	color.RGBA{R: 48, G: 161, B: 78, A: 255},
+	color.RGBA{R: 33, G: 110, B: 57, A: 255},
+}
+
+var heatmapMonths = []string{"янв", "фев", "мар", "апр", "май", "июн", "июл", "авг", "сен", "окт", "ноя", "дек"}
+
+// HeatmapLevel возвращает уровень активности дня относительно нормы:
+// 0 — не тренировался, 1 — меньше половины нормы, 2 — норма не выполнена,
+// 3 — норма выполнена, 4 — полторы нормы и больше.
+// Выполнение берётся из day.Completed (norm_completions), а не из сравнения с восстановленной нормой,
+// чтобы цвет сходился с числом дней с нормой и серией. Если норма не задана, невыполненный день получает уровень 2
+func HeatmapLevel(day model.DailyVolume) int {
+	switch {
+	case day.Completed && day.Norm > 0 && day.Count*2 >= day.Norm*3:
+		return 4
+	case day.Completed:
+		return 3
+	case day.Count <= 0:
+		return 0
+	case day.Norm > 0 && day.Count*2 < day.Norm:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// SummarizeHeatmap подводит итоги календаря; streak — текущая серия выполнения нормы.
+// Дни с нормой и серия считаются по одним и тем же norm_completions
+func SummarizeHeatmap(days []model.DailyVolume, streak int) model.ActivityHeatmap {
+	heatmap := model.ActivityHeatmap{Days: days, Streak: streak}
+	for _, day := range days {
+		heatmap.Total += day.Count
+		if day.Count > 0 {
+			heatmap.ActiveDays++
+		}
+		if day.Completed {
+			heatmap.CompletedDays++
+		}
+	}
+	return heatmap
+}
+
+// SendHeatmap рисует календарь активности в духе GitHub: столбец — неделя,
+// строка — день недели, цвет — объём относительно нормы. Внизу — итоги года
+func SendHeatmap(heatmap model.ActivityHeatmap) (bytes.Buffer, error) {
+	weeks := max((len(heatmap.Days)+6)/7, 1)
+	width := heatmapLeft + heatmapStep*vg.Length(weeks) + 16
+	height := heatmapTop + heatmapStep*7 + heatmapBottom
+
+	canvas, err := draw.NewFormattedCanvas(width, height, "png")
+	if err != nil {
+		return bytes.Buffer{}, fmt.Errorf("ошибка создания холста: %w", err)
+	}
+	c := draw.New(canvas)
+	c.FillPolygon(color.White, []vg.Point{{X: 0, Y: 0}, {X: width, Y: 0}, {X: width, Y: height}, {X: 0, Y: height}})
+
+	style := func(size vg.Length, clr color.Color) draw.TextStyle {
+		return draw.TextStyle{
+			Color:   clr,
+			Font:    font.From(plot.DefaultFont, size),
+			Handler: plot.DefaultTextHandler,
+			YAlign:  text.YCenter,
+		}
+	}
+	labelStyle := style(8, color.Gray{Y: 110})
+
+	c.FillText(style(12, color.Black), vg.Point{X: heatmapLeft, Y: height - 14}, "Активность за год")
+
+	// Ячейка дня: неделя слева направо, понедельник сверху
+	cell := func(index int) vg.Point {
+		week, weekday := index/7, index%7
+		return vg.Point{
+			X: heatmapLeft + heatmapStep*vg.Length(week),
+			Y: height - heatmapTop - heatmapStep*vg.Length(weekday+1),
+		}
+	}
+
+	for i, day := range heatmap.Days {
+		pt := cell(i)
+		c.FillPolygon(heatmapColors[HeatmapLevel(day)], heatmapSquare(pt, heatmapCell))
+
+		if day.Date.Day() == 1 || i == 0 && day.Date.Day() < 8 {
+			c.FillText(labelStyle, vg.Point{X: pt.X, Y: height - heatmapTop + 8}, heatmapMonths[day.Date.Month()-1])
+		}
+	}
+
+	for weekday, label := range map[int]string{0: "Пн", 2: "Ср", 4: "Пт"} {
+		pt := cell(weekday)
+		c.FillText(labelStyle, vg.Point{X: 6, Y: pt.Y + heatmapCell/2}, label)
+	}
+
+	// Шкала уровней
+	legendY := heatmapBottom - 20
+	legendX := width - 16 - heatmapStep*vg.Length(len(heatmapColors)) - 40
+	c.FillText(labelStyle, vg.Point{X: legendX - 44, Y: legendY + heatmapCell/2}, "Меньше")
+	for i, clr := range heatmapColors {
+		c.FillPolygon(clr, heatmapSquare(vg.Point{X: legendX + heatmapStep*vg.Length(i), Y: legendY}, heatmapCell))
+	}
+	c.FillText(labelStyle, vg.Point{X: legendX + heatmapStep*vg.Length(len(heatmapColors)) + 2, Y: legendY + heatmapCell/2}, "Больше")
+
+	summary := "Пока нет ни одного подхода — самое время начать!"
+	if heatmap.ActiveDays > 0 {
+		summary = fmt.Sprintf("Всего: %d · Тренировок: %d дн. · Норма выполнена: %d дн. · Серия: %d дн.",
+			heatmap.Total, heatmap.ActiveDays, heatmap.CompletedDays, heatmap.Streak)
+	}
+	c.FillText(style(9, color.Black), vg.Point{X: heatmapLeft, Y: 14}, summary)
+
+	var buf bytes.Buffer
+	if _, err := canvas.WriteTo(&buf); err != nil {
+		return bytes.Buffer{}, err
+	}
+	return buf, nil
+}
+
+// heatmapSquare возвращает квадрат со стороной size от левого нижнего угла pt
+func heatmapSquare(pt vg.Point, size vg.Length) []vg.Point {
+	return []vg.Point{pt, {X: pt.X + size, Y: pt.Y}, {X: pt.X + size, Y: pt.Y + size}, {X: pt.X, Y: pt.Y + size}}
+}
+
+// BuildHeatmap строит календарь активности за последние HeatmapWeeks недель
+func (s *pushupService) BuildHeatmap(ctx context.Context, userID int64) (bytes.Buffer, error) {
+	now := time.Now()
+	from := WeekStart(now).AddDate(0, 0, -7*(HeatmapWeeks-1))
+
+	totals, err := s.repo.GetDailyTotals(ctx, userID, from)
+	if err != nil {
+		return bytes.Buffer{}, err
+	}
+	norms, err := s.normSeries(ctx, userID, from, now)
+	if err != nil {
+		return bytes.Buffer{}, err
+	}
+	rest, err := s.repo.GetRestPeriods(ctx, userID)
+	if err != nil {
+		return bytes.Buffer{}, err
+	}
+	completions, err := s.repo.GetNormCompletionDates(ctx, userID)
+	if err != nil {
+		return bytes.Buffer{}, err
+	}
+
+	days := DailyVolumes(totals, norms, completions, rest, from, dateOnly(now).AddDate(0, 0, 1))
+
+	return SendHeatmap(SummarizeHeatmap(days, CalculateNormStreak(completions, rest, now)))
+}
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"bytes"
+	"os"
+	"testing"
+	"time"
+
+	"trackerbot/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHeatmapLevel(t *testing.T) {
+	tests := []struct {
+		name  string
+		day   model.DailyVolume
+		level int
+	}{
+		{"не тренировался", model.DailyVolume{Norm: 100}, 0},
+		{"меньше половины нормы", model.DailyVolume{Count: 40, Norm: 100}, 1},
+		{"меньше нормы", model.DailyVolume{Count: 70, Norm: 100}, 2},
+		{"норма", model.DailyVolume{Count: 100, Norm: 100, Completed: true}, 3},
+		{"полторы нормы", model.DailyVolume{Count: 150, Norm: 100, Completed: true}, 4},
+		{"норма не задана", model.DailyVolume{Count: 10}, 2},
+		{"выполнена по прежней норме", model.DailyVolume{Count: 70, Norm: 100, Completed: true}, 3},
+		{"выше нормы, но не выполнена", model.DailyVolume{Count: 160, Norm: 100}, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.level, HeatmapLevel(tt.day))
+		})
+	}
+}
+
+func TestSummarizeHeatmap(t *testing.T) {
+	days := []model.DailyVolume{
+		{Count: 120, Norm: 100, Completed: true},
+		{Count: 0, Norm: 100},
+		{Count: 30, Norm: 100},
+	}
+
+	heatmap := SummarizeHeatmap(days, 1)
+
+	assert.Equal(t, 150, heatmap.Total)
+	assert.Equal(t, 2, heatmap.ActiveDays)
+	assert.Equal(t, 1, heatmap.CompletedDays)
+	assert.Equal(t, 1, heatmap.Streak)
+}
+
+func TestSummarizeHeatmap_NormCompletions(t *testing.T) {
+	now := time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC)
+	from := dateOnly(now).AddDate(0, 0, -3)
+	totals := []model.DailyTotal{
+		{Date: from, Count: 100},
+		{Date: from.AddDate(0, 0, 2), Count: 60},
+		{Date: from.AddDate(0, 0, 3), Count: 70},
+	}
+	// Восстановленная норма 100 выше той, что действовала в дни выполнения
+	norms := []model.NormPoint{{Date: from, Norm: 100}}
+	completions := []time.Time{from.AddDate(0, 0, 3), from.AddDate(0, 0, 2), from}
+
+	days := DailyVolumes(totals, norms, completions, nil, from, dateOnly(now).AddDate(0, 0, 1))
+	heatmap := SummarizeHeatmap(days, CalculateNormStreak(completions, nil, now))
+
+	assert.Equal(t, 3, heatmap.CompletedDays)
+	assert.Equal(t, 2, heatmap.Streak)
+	for _, day := range days {
+		assert.Equal(t, day.Completed, HeatmapLevel(day) >= 3, day.Date)
+	}
+}
+
+func TestSendHeatmap(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	from := WeekStart(now).AddDate(0, 0, -7*(HeatmapWeeks-1))
+
+	t.Run("редкие тренировки", func(t *testing.T) {
+		var totals []model.DailyTotal
+		for day := from; day.Before(now); day = day.AddDate(0, 0, 3) {
+			totals = append(totals, model.DailyTotal{Date: day, Count: day.YearDay() % 160})
+		}
+		days := DailyVolumes(totals, []model.NormPoint{{Date: from, Norm: 100}}, nil, nil, from, dateOnly(now).AddDate(0, 0, 1))
+
+		buf, err := SendHeatmap(SummarizeHeatmap(days, 0))
+
+		assert.NoError(t, err)
+		assert.True(t, bytes.HasPrefix(buf.Bytes(), pngSignature))
+		if path := os.Getenv("DUMP_HEATMAP"); path != "" {
+			_ = os.WriteFile(path, buf.Bytes(), 0o644)
+		}
+	})
+
+	t.Run("без данных", func(t *testing.T) {
+		days := DailyVolumes(nil, nil, nil, nil, from, dateOnly(now).AddDate(0, 0, 1))
+
+		buf, err := SendHeatmap(SummarizeHeatmap(days, 0))
+
+		assert.NoError(t, err)
+		assert.True(t, bytes.HasPrefix(buf.Bytes(), pngSignature))
+	})
+
+	t.Run("пустой календарь", func(t *testing.T) {
+		_, err := SendHeatmap(model.ActivityHeatmap{})
+		assert.NoError(t, err)
+	})
+}
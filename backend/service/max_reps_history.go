@@ -0,0 +1,201 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"trackerbot/model"
+)
+
+// HistoryPageSize — сколько записей истории теста максимума показывается на одной странице
+const HistoryPageSize = 8
+
+// HistoryRangeStart возвращает первый день диапазона истории (нулевое время — без ограничения)
+func HistoryRangeStart(rangeCode string, now time.Time) (time.Time, error) {
+	switch rangeCode {
+	case model.HistoryRangeMonth:
+		return dateOnly(now).AddDate(0, -1, 0), nil
+	case model.HistoryRangeYear:
+		return dateOnly(now).AddDate(-1, 0, 0), nil
+	case model.HistoryRangeAll:
+		return time.Time{}, nil
+	default:
+		return time.Time{}, fmt.Errorf("неизвестный диапазон истории: %s", rangeCode)
+	}
+}
+
+// PageMaxRepsHistory отбирает записи диапазона и возвращает нужную страницу.
+// history отсортирована от новых к старым; номер страницы приводится к допустимому
+func PageMaxRepsHistory(history []model.MaxRepsHistoryItem, rangeCode string, page int, now time.Time) (model.MaxRepsHistoryPage, error) {
+	start, err := HistoryRangeStart(rangeCode, now)
+	if err != nil {
+		return model.MaxRepsHistoryPage{}, err
+	}
+
+	inRange := history
+	if !start.IsZero() {
+		inRange = make([]model.MaxRepsHistoryItem, 0, len(history))
+		for _, item := range history {
+			if !item.Date.Before(start) {
+				inRange = append(inRange, item)
+			}
+		}
+	}
+
+	result := model.MaxRepsHistoryPage{
+		Range: rangeCode,
+		Total: len(inRange),
+		Pages: max((len(inRange)+HistoryPageSize-1)/HistoryPageSize, 1),
+	}
+	if len(inRange) == 0 {
+		return result, nil
+	}
+
+	result.Page = min(max(page, 0), result.Pages-1)
+	result.Offset = result.Page * HistoryPageSize
+	result.Items = inRange[result.Offset:min(result.Offset+HistoryPageSize, len(inRange))]
+	result.First = inRange[len(inRange)-1].MaxReps
+	result.Last = inRange[0].MaxReps
+
+	return result, nil
+}
+
+// GetMaxRepsHistoryPage возвращает страницу истории теста максимума в выбранном диапазоне
+func (s *pushupService) GetMaxRepsHistoryPage(
+	ctx context.Context,
+	userID int64,
+	rangeCode string,
+	page int,
+) (*model.MaxRepsHistoryPage, error) {
+
+	history, err := s.repo.GetMaxRepsHistory(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := PageMaxRepsHistory(history, rangeCode, page, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// EditMaxRepsEntry исправляет запись в истории теста максимума и
+// пересчитывает текущий максимум и ранг, если изменилась последняя запись
+func (s *pushupService) EditMaxRepsEntry(
+	ctx context.Context,
+	userID int64,
+	recordID int64,
+	count int,
+) (*model.MaxRepsHistoryChange, error) {
+
+	if count <= 0 {
+		return nil, fmt.Errorf("результат должен быть положительным")
+	}
+
+	before, err := s.repo.GetMaxRepsHistory(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdateMaxRepsEntry(ctx, userID, recordID, count); err != nil {
+		return nil, err
+	}
+
+	return s.syncMaxRepsWithHistory(ctx, userID, before)
+}
+
+// DeleteMaxRepsEntry удаляет ошибочную запись из истории теста максимума
+func (s *pushupService) DeleteMaxRepsEntry(ctx context.Context, userID int64, recordID int64) (*model.MaxRepsHistoryChange, error) {
+	before, err := s.repo.GetMaxRepsHistory(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.DeleteMaxRepsEntry(ctx, userID, recordID); err != nil {
+		return nil, err
+	}
+
+	return s.syncMaxRepsWithHistory(ctx, userID, before)
+}
+
+// syncMaxRepsWithHistory приводит users.max_reps к последней записи истории
+// и фиксирует смену ранга. before — история до изменения
+func (s *pushupService) syncMaxRepsWithHistory(
+	ctx context.Context,
+	userID int64,
+	before []model.MaxRepsHistoryItem,
+) (*model.MaxRepsHistoryChange, error) {
+
+	prevMaxReps, err := s.repo.GetUserMaxReps(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	history, err := s.repo.GetMaxRepsHistory(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	change := &model.MaxRepsHistoryChange{
+		History:     history,
+		PrevMaxReps: prevMaxReps,
+	}
+
+	var latest *model.MaxRepsHistoryItem
+	if len(history) > 0 {
+		latest = &history[0]
+		change.MaxReps = latest.MaxReps
+	}
+	change.Rank = s.ranks.Title(change.MaxReps)
+
+	// Последняя запись удалена — датой последнего теста становится предыдущая
+	latestRemoved := len(before) > 0 && (latest == nil || latest.ID != before[0].ID)
+	if change.MaxReps == prevMaxReps && !latestRemoved {
+		return change, nil
+	}
+
+	change.LatestChanged = true
+
+	var testedAt *time.Time
+	if latestRemoved && latest != nil {
+		testedAt = &latest.Date
+	}
+	if err := s.repo.RestoreMaxReps(ctx, userID, change.MaxReps, testedAt); err != nil {
+		return nil, err
+	}
+
+	change.RankChange, err = s.recordRankChange(ctx, userID, prevMaxReps, change.MaxReps)
+	if err != nil {
+		return nil, err
+	}
+
+	return change, nil
+}
+
+// RecalculateNorm пересчитывает дневную норму по текущему максимуму и выбранной стратегии
+func (s *pushupService) RecalculateNorm(ctx context.Context, userID int64) (int, error) {
+	maxReps, err := s.repo.GetUserMaxReps(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+	if maxReps <= 0 {
+		return 0, fmt.Errorf("в истории нет результатов теста максимума")
+	}
+
+	strategy, err := s.userNormStrategy(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+	input, err := s.collectNormInput(ctx, userID, maxReps)
+	if err != nil {
+		return 0, err
+	}
+
+	dailyNorm := strategy.Calculate(input)
+	if err := s.repo.SetDailyNorm(ctx, userID, dailyNorm, model.NormSourceHistory); err != nil {
+		return 0, err
+	}
+	return dailyNorm, nil
+}
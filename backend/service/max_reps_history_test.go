@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"trackerbot/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestDeleteMaxRepsEntry_LatestRestoresPrevious(t *testing.T) {
+	mockRepo := new(MockPushupRepository)
+	svc := NewPushupService(mockRepo, Config{})
+
+	ctx := context.Background()
+	userID := int64(1)
+	previousDate := time.Date(2026, 3, 1, 0, 0, 0, 0, time.Local)
+
+	before := []model.MaxRepsHistoryItem{
+		{ID: 2, Date: time.Date(2026, 3, 8, 0, 0, 0, 0, time.Local), MaxReps: 80},
+		{ID: 1, Date: previousDate, MaxReps: 30},
+	}
+	after := before[1:]
+
+	mockRepo.On("GetMaxRepsHistory", ctx, userID).Return(before, nil).Once()
+	mockRepo.On("DeleteMaxRepsEntry", ctx, userID, int64(2)).Return(nil).Once()
+	mockRepo.On("GetUserMaxReps", ctx, userID).Return(80, nil).Once()
+	mockRepo.On("GetMaxRepsHistory", ctx, userID).Return(after, nil).Once()
+	mockRepo.On("RestoreMaxReps", ctx, userID, 30, &previousDate).Return(nil).Once()
+	mockRepo.On("AddRankChange", ctx, userID, mock.Anything).Return(nil).Maybe()
+	mockRepo.On("GetUsername", ctx, userID).Return("user", nil).Maybe()
+
+	change, err := svc.DeleteMaxRepsEntry(ctx, userID, 2)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 80, change.PrevMaxReps)
+	assert.Equal(t, 30, change.MaxReps)
+	assert.True(t, change.LatestChanged)
+	assert.Equal(t, DefaultRanks.Title(30), change.Rank)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestEditMaxRepsEntry_OlderEntryKeepsMaxReps(t *testing.T) {
+	mockRepo := new(MockPushupRepository)
+	svc := NewPushupService(mockRepo, Config{})
+
+	ctx := context.Background()
+	userID := int64(1)
+
+	history := []model.MaxRepsHistoryItem{
+		{ID: 2, Date: time.Date(2026, 3, 8, 0, 0, 0, 0, time.Local), MaxReps: 40},
+		{ID: 1, Date: time.Date(2026, 3, 1, 0, 0, 0, 0, time.Local), MaxReps: 35},
+	}
+
+	mockRepo.On("GetMaxRepsHistory", ctx, userID).Return(history, nil)
+	mockRepo.On("UpdateMaxRepsEntry", ctx, userID, int64(1), 33).Return(nil).Once()
+	mockRepo.On("GetUserMaxReps", ctx, userID).Return(40, nil).Once()
+
+	change, err := svc.EditMaxRepsEntry(ctx, userID, 1, 33)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 40, change.MaxReps)
+	assert.False(t, change.LatestChanged)
+	mockRepo.AssertNotCalled(t, "RestoreMaxReps", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPageMaxRepsHistory(t *testing.T) {
+	now := time.Date(2026, 3, 10, 18, 0, 0, 0, time.Local)
+
+	// 20 тестов раз в неделю, новые первыми
+	history := make([]model.MaxRepsHistoryItem, 20)
+	for i := range history {
+		history[i] = model.MaxRepsHistoryItem{
+			ID:      int64(20 - i),
+			Date:    dateOnly(now).AddDate(0, 0, -7*i),
+			MaxReps: 40 - i,
+		}
+	}
+
+	t.Run("первая страница за всё время", func(t *testing.T) {
+		page, err := PageMaxRepsHistory(history, model.HistoryRangeAll, 0, now)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 20, page.Total)
+		assert.Equal(t, 3, page.Pages)
+		assert.Len(t, page.Items, HistoryPageSize)
+		assert.Equal(t, 21, page.First)
+		assert.Equal(t, 40, page.Last)
+	})
+
+	t.Run("номер страницы ограничивается", func(t *testing.T) {
+		page, err := PageMaxRepsHistory(history, model.HistoryRangeAll, 10, now)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, page.Page)
+		assert.Equal(t, 16, page.Offset)
+		assert.Len(t, page.Items, 4)
+	})
+
+	t.Run("последний месяц", func(t *testing.T) {
+		page, err := PageMaxRepsHistory(history, model.HistoryRangeMonth, 0, now)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 5, page.Total)
+		assert.Equal(t, 1, page.Pages)
+		assert.Equal(t, 36, page.First)
+	})
+
+	t.Run("пустой диапазон", func(t *testing.T) {
+		page, err := PageMaxRepsHistory(history[19:], model.HistoryRangeMonth, 0, now)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, page.Total)
+		assert.Empty(t, page.Items)
+	})
+
+	t.Run("неизвестный диапазон", func(t *testing.T) {
+		_, err := PageMaxRepsHistory(history, "week", 0, now)
+		assert.Error(t, err)
+	})
+}
+
+func TestDownsampleHistory(t *testing.T) {
+	history := make([]model.MaxRepsHistoryItem, 300)
+	for i := range history {
+		history[i] = model.MaxRepsHistoryItem{ID: int64(i), MaxReps: 300 - i}
+	}
+	history[150].MaxReps = 1000
+
+	sampled := DownsampleHistory(history, 50)
+
+	assert.Len(t, sampled, 50)
+	assert.Equal(t, history[0], sampled[0])
+	assert.Contains(t, sampled, history[150])
+	assert.Equal(t, history[:10], DownsampleHistory(history[:10], 50))
+}
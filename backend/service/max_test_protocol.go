@@ -0,0 +1,174 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"trackerbot/model"
+)
+
+const (
+	// Результат теста, отличающийся от обычного результата больше чем на 30% и 5 повторений,
+	// требует подтверждения: чаще всего это опечатка или тест «на уставших мышцах»
+	MaxTestDeviationRatio = 0.3
+	MaxTestDeviationReps  = 5
+
+	// Обычный результат — медиана последних тестов: одна ошибочная запись его не сдвигает
+	MaxTestBaselineTests = 5
+
+	MinRPE = 6 // Шкала RPE: 6 — было легко, 10 — больше ни одного повторения
+	MaxRPE = 10
+)
+
+// IsMaxTestDeviation проверяет, что результат теста сильно отличается от предыдущего максимума
+func IsMaxTestDeviation(count, previous int) bool {
+	if previous <= 0 {
+		return false
+	}
+
+	diff := count - previous
+	if diff < 0 {
+		diff = -diff
+	}
+	return diff > MaxTestDeviationReps && float64(diff) > float64(previous)*MaxTestDeviationRatio
+}
+
+// MaxTestBaseline возвращает медиану последних MaxTestBaselineTests тестов
+// (история — новые первыми) и число тестов, по которым она посчитана
+func MaxTestBaseline(history []model.MaxRepsHistoryItem) (int, int) {
+	recent := history[:min(len(history), MaxTestBaselineTests)]
+	if len(recent) == 0 {
+		return 0, 0
+	}
+
+	values := make([]int, len(recent))
+	for i, item := range recent {
+		values[i] = item.MaxReps
+	}
+	sort.Ints(values)
+
+	middle := len(values) / 2
+	if len(values)%2 == 1 {
+		return values[middle], len(values)
+	}
+	return int(math.Round(float64(values[middle-1]+values[middle]) / 2)), len(values)
+}
+
+// MaxTestReadinessFor оценивает готовность к тесту максимума
+// Аргументы:
+//
+//	totals     - суммы по дням за последние RecoveryHours
+//	dailyNorm  - дневная норма пользователя
+//	lastTestAt - время прошлого теста (nil, если тест не проходился)
+//	now        - текущее время
+func MaxTestReadinessFor(
+	totals []model.DailyTotal,
+	dailyNorm int,
+	lastTestAt *time.Time,
+	now time.Time,
+) model.MaxTestReadiness {
+
+	readiness := model.MaxTestReadiness{
+		DailyNorm:  dailyNorm,
+		LastTestAt: lastTestAt,
+		Recovered:  IsRecoveredForMaxTest(totals, dailyNorm, now),
+	}
+
+	today := dateOnly(now)
+	for _, day := range totals {
+		readiness.RecentVolume += day.Count
+		if dateOnly(day.Date).Equal(today) {
+			readiness.TrainedToday += day.Count
+		}
+	}
+
+	if lastTestAt != nil {
+		readiness.TestedRecently = now.Sub(*lastTestAt) < RecoveryHours*time.Hour
+	}
+
+	return readiness
+}
+
+// GetMaxTestReadiness проверяет, готов ли пользователь к тесту максимума:
+// восстановился ли после объёмных дней и прошлого теста
+func (s *pushupService) GetMaxTestReadiness(ctx context.Context, userID int64) (model.MaxTestReadiness, error) {
+	now := time.Now()
+
+	maxReps, err := s.repo.GetUserMaxReps(ctx, userID)
+	if err != nil {
+		return model.MaxTestReadiness{}, err
+	}
+
+	dailyNorm, err := s.repo.GetDailyNorm(ctx, userID)
+	if err != nil {
+		return model.MaxTestReadiness{}, err
+	}
+
+	totals, err := s.repo.GetDailyTotals(ctx, userID, now.Add(-RecoveryHours*time.Hour))
+	if err != nil {
+		return model.MaxTestReadiness{}, err
+	}
+
+	// Пока тест не пройден, дата обновления максимума — это дата регистрации
+	var lastTestAt *time.Time
+	if maxReps > 0 {
+		lastUpdate, err := s.repo.GetLastMaxRepsUpdate(ctx, userID)
+		if err != nil {
+			return model.MaxTestReadiness{}, err
+		}
+		lastTestAt = &lastUpdate
+	}
+
+	readiness := MaxTestReadinessFor(totals, dailyNorm, lastTestAt, now)
+	readiness.MaxReps = maxReps
+	return readiness, nil
+}
+
+// CheckMaxTestResult сравнивает результат теста с медианой последних тестов.
+// Если истории нет, сравнивает с текущим максимумом
+func (s *pushupService) CheckMaxTestResult(ctx context.Context, userID int64, count int) (model.MaxTestCheck, error) {
+	history, err := s.repo.GetMaxRepsHistory(ctx, userID)
+	if err != nil {
+		return model.MaxTestCheck{}, err
+	}
+
+	previous, tests := MaxTestBaseline(history)
+	if tests == 0 {
+		previous, err = s.repo.GetUserMaxReps(ctx, userID)
+		if err != nil {
+			return model.MaxTestCheck{}, err
+		}
+	}
+
+	return model.MaxTestCheck{
+		Count:    count,
+		Previous: previous,
+		Tests:    tests,
+		Deviates: IsMaxTestDeviation(count, previous),
+	}, nil
+}
+
+// RecordMaxTest сохраняет результат теста (пересчитывает норму через UpdateMaxReps)
+// и его субъективную тяжесть; rpe = 0 — тяжесть не указана
+func (s *pushupService) RecordMaxTest(ctx context.Context, userID int64, count, rpe int) (*model.MaxRepsViewModel, error) {
+	if rpe != 0 && (rpe < MinRPE || rpe > MaxRPE) {
+		return nil, fmt.Errorf("RPE должен быть от %d до %d", MinRPE, MaxRPE)
+	}
+
+	vm, err := s.UpdateMaxReps(ctx, userID, count)
+	if err != nil {
+		return nil, err
+	}
+
+	if rpe > 0 {
+		if err := s.repo.SetMaxTestRPE(ctx, userID, rpe); err != nil {
+			return nil, err
+		}
+		vm.RPE = rpe
+	}
+
+	return vm, nil
+}
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"trackerbot/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestIsMaxTestDeviation(t *testing.T) {
+	tests := []struct {
+		name     string
+		count    int
+		previous int
+		want     bool
+	}{
+		{"первый тест", 40, 0, false},
+		{"обычный прирост", 33, 30, false},
+		{"большой процент, но мало повторений", 9, 5, false},
+		{"скачок вверх", 45, 30, true},
+		{"провал", 15, 30, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsMaxTestDeviation(tt.count, tt.previous))
+		})
+	}
+}
+
+func TestMaxTestBaseline(t *testing.T) {
+	items := func(values ...int) []model.MaxRepsHistoryItem {
+		history := make([]model.MaxRepsHistoryItem, len(values))
+		for i, value := range values {
+			history[i] = model.MaxRepsHistoryItem{MaxReps: value}
+		}
+		return history
+	}
+
+	tests := []struct {
+		name      string
+		history   []model.MaxRepsHistoryItem
+		want      int
+		wantTests int
+	}{
+		{"нет истории", nil, 0, 0},
+		{"один тест", items(30), 30, 1},
+		{"ошибочная запись не сдвигает медиану", items(300, 31, 30, 29, 28), 30, 5},
+		{"чётное число тестов", items(32, 30, 29, 27), 30, 4},
+		{"берутся только последние тесты", items(40, 41, 42, 43, 44, 10, 10, 10), 42, 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, count := MaxTestBaseline(tt.history)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantTests, count)
+		})
+	}
+}
+
+func TestService_CheckMaxTestResult_UsesHistory(t *testing.T) {
+	mockRepo := new(MockPushupRepository)
+	svc := NewPushupService(mockRepo, Config{})
+
+	// Последний тест записан с опечаткой (300): текущий максимум испорчен,
+	// но нормальный результат 32 не должен требовать подтверждения
+	mockRepo.On("GetMaxRepsHistory", mock.Anything, int64(1)).Return([]model.MaxRepsHistoryItem{
+		{MaxReps: 300}, {MaxReps: 30}, {MaxReps: 29},
+	}, nil).Once()
+
+	check, err := svc.CheckMaxTestResult(context.Background(), 1, 32)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 30, check.Previous)
+	assert.False(t, check.Deviates)
+	mockRepo.AssertNotCalled(t, "GetUserMaxReps", mock.Anything, mock.Anything)
+}
+
+func TestService_CheckMaxTestResult_NoHistory(t *testing.T) {
+	mockRepo := new(MockPushupRepository)
+	svc := NewPushupService(mockRepo, Config{})
+
+	mockRepo.On("GetMaxRepsHistory", mock.Anything, int64(1)).Return(nil, nil).Once()
+	mockRepo.On("GetUserMaxReps", mock.Anything, int64(1)).Return(30, nil).Once()
+
+	check, err := svc.CheckMaxTestResult(context.Background(), 1, 15)
+
+	assert.NoError(t, err)
+	assert.Zero(t, check.Tests)
+	assert.True(t, check.Deviates)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestMaxTestReadinessFor(t *testing.T) {
+	now := time.Date(2026, 3, 10, 18, 0, 0, 0, time.Local)
+	day := func(offset int) time.Time { return dateOnly(now).AddDate(0, 0, offset) }
+
+	t.Run("отдохнул", func(t *testing.T) {
+		lastTest := now.AddDate(0, 0, -7)
+		readiness := MaxTestReadinessFor([]model.DailyTotal{{Date: day(-1), Count: 20}}, 100, &lastTest, now)
+
+		assert.True(t, readiness.Ready())
+		assert.Equal(t, 20, readiness.RecentVolume)
+	})
+
+	t.Run("вчера выполнена норма", func(t *testing.T) {
+		readiness := MaxTestReadinessFor([]model.DailyTotal{{Date: day(-1), Count: 120}}, 100, nil, now)
+
+		assert.False(t, readiness.Recovered)
+		assert.False(t, readiness.Ready())
+	})
+
+	t.Run("сегодня уже тренировался", func(t *testing.T) {
+		readiness := MaxTestReadinessFor([]model.DailyTotal{{Date: day(0), Count: 30}}, 100, nil, now)
+
+		assert.True(t, readiness.Recovered)
+		assert.Equal(t, 30, readiness.TrainedToday)
+		assert.False(t, readiness.Ready())
+	})
+
+	t.Run("тест был вчера", func(t *testing.T) {
+		lastTest := now.Add(-20 * time.Hour)
+		readiness := MaxTestReadinessFor(nil, 100, &lastTest, now)
+
+		assert.True(t, readiness.TestedRecently)
+		assert.False(t, readiness.Ready())
+	})
+}
+
+func TestService_RecordMaxTest_InvalidRPE(t *testing.T) {
+	mockRepo := new(MockPushupRepository)
+	svc := NewPushupService(mockRepo, Config{})
+
+	_, err := svc.RecordMaxTest(context.Background(), 1, 30, 3)
+
+	assert.Error(t, err)
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,272 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"trackerbot/model"
+)
+
+const (
+	// Коды стратегий расчёта нормы
+	NormStrategyACSM     = "acsm"
+	NormStrategyPercent  = "percent"
+	NormStrategyRamp     = "ramp"
+	NormStrategyAdaptive = "adaptive"
+
+	// Параметры стратегий
+	PercentOfMaxRatio  = 3.0 // Норма = 300% от максимума за подход
+	RampStepPerWeek    = 10  // Прибавка к норме за каждую неделю с прошлого теста
+	AdaptiveWindowDays = 14  // Окно для расчёта доли выполненных дней
+	AdaptiveHighRate   = 0.8 // Норма выполняется почти всегда — повышаем
+	AdaptiveLowRate    = 0.4 // Норма выполняется редко — понижаем
+	AdaptiveStepRatio  = 0.1 // Шаг изменения нормы (10%)
+)
+
+// NormInput — данные пользователя для расчёта нормы после теста максимума
+type NormInput struct {
+	MaxReps      int                // Новый максимум за подход
+	CurrentNorm  int                // Норма до теста
+	LastMaxTest  time.Time          // Дата предыдущего теста
+	RecentTotals []model.DailyTotal // Суммы по дням за AdaptiveWindowDays до сегодняшнего дня
+	RestPeriods  []model.RestPeriod // Дни отдыха не учитываются в доле выполненных дней
+	Formula      NormFormula        // Параметры формулы нормы
+	Now          time.Time
+}
+
+// NormStrategy рассчитывает дневную норму после теста максимума
+type NormStrategy interface {
+	Info() model.NormStrategyInfo
+	Calculate(input NormInput) int
+}
+
+// acsmStrategy — формула ACSM с уменьшающимся коэффициентом (NormFormula.DailyNorm)
+type acsmStrategy struct{}
+
+func (acsmStrategy) Info() model.NormStrategyInfo {
+	return model.NormStrategyInfo{
+		Code:        NormStrategyACSM,
+		Name:        "🧠 ACSM",
+		Description: "Формула по рекомендациям ACSM с учётом уровня подготовки",
+	}
+}
+
+func (acsmStrategy) Calculate(input NormInput) int {
+	return input.Formula.DailyNorm(input.MaxReps)
+}
+
+// percentStrategy — фиксированный процент от максимума за подход
+type percentStrategy struct {
+	ratio float64
+}
+
+func (s percentStrategy) Info() model.NormStrategyInfo {
+	return model.NormStrategyInfo{
+		Code:        NormStrategyPercent,
+		Name:        "📐 Процент от максимума",
+		Description: fmt.Sprintf("Норма — %.0f%% от максимума за подход", s.ratio*100),
+	}
+}
+
+func (s percentStrategy) Calculate(input NormInput) int {
+	return input.Formula.round(float64(input.MaxReps) * s.ratio)
+}
+
+// rampStrategy — линейный рост нормы на фиксированный шаг за каждую неделю
+// с прошлого теста, но не ниже формулы ACSM
+type rampStrategy struct {
+	stepPerWeek int
+}
+
+func (s rampStrategy) Info() model.NormStrategyInfo {
+	return model.NormStrategyInfo{
+		Code:        NormStrategyRamp,
+		Name:        "📈 Недельная прибавка",
+		Description: fmt.Sprintf("+%d к норме за каждую неделю с прошлого теста", s.stepPerWeek),
+	}
+}
+
+func (s rampStrategy) Calculate(input NormInput) int {
+	base := input.Formula.DailyNorm(input.MaxReps)
+	if input.CurrentNorm <= 0 || input.LastMaxTest.IsZero() {
+		return base
+	}
+
+	weeks := int(input.Now.Sub(input.LastMaxTest).Hours() / (24 * 7))
+	ramped := input.Formula.round(float64(input.CurrentNorm + weeks*s.stepPerWeek))
+
+	return max(base, ramped)
+}
+
+// adaptiveStrategy — формула ACSM с поправкой на долю дней с выполненной нормой
+type adaptiveStrategy struct{}
+
+func (adaptiveStrategy) Info() model.NormStrategyInfo {
+	return model.NormStrategyInfo{
+		Code: NormStrategyAdaptive,
+		Name: "🔄 Адаптивная",
+		Description: fmt.Sprintf(
+			"Формула ACSM ±%.0f%% в зависимости от того, как часто норма выполнялась за %d дней",
+			AdaptiveStepRatio*100, AdaptiveWindowDays,
+		),
+	}
+}
+
+func (adaptiveStrategy) Calculate(input NormInput) int {
+	base := input.Formula.DailyNorm(input.MaxReps)
+	if input.CurrentNorm <= 0 {
+		return base
+	}
+
+	// Сегодняшний день ещё не закончился, поэтому окно заканчивается вчерашним
+	today := dateOnly(input.Now)
+	from := today.AddDate(0, 0, -AdaptiveWindowDays)
+
+	days := AdaptiveWindowDays - CountRestDays(input.RestPeriods, from, today)
+	if days <= 0 {
+		return base
+	}
+
+	totals := windowTotals(input.RecentTotals, input.RestPeriods, from, today)
+	rate := CompletionRate(totals, input.CurrentNorm, days)
+	switch {
+	case rate >= AdaptiveHighRate:
+		return input.Formula.round(float64(base) * (1 + AdaptiveStepRatio))
+	case rate < AdaptiveLowRate:
+		return input.Formula.round(float64(base) * (1 - AdaptiveStepRatio))
+	default:
+		return base
+	}
+}
+
+// CompletionRate возвращает долю дней окна, в которые норма была выполнена
+func CompletionRate(totals []model.DailyTotal, dailyNorm int, days int) float64 {
+	if days <= 0 || dailyNorm <= 0 {
+		return 0
+	}
+
+	completed := 0
+	for _, day := range totals {
+		if day.Count >= dailyNorm {
+			completed++
+		}
+	}
+
+	return math.Min(float64(completed)/float64(days), 1)
+}
+
+// windowTotals оставляет суммы дней из полуинтервала [from, to) без дней отдыха
+func windowTotals(totals []model.DailyTotal, rest []model.RestPeriod, from, to time.Time) []model.DailyTotal {
+	var window []model.DailyTotal
+	for _, day := range ExcludeRestDays(totals, rest) {
+		date := dateOnly(day.Date)
+		if date.Before(from) || !date.Before(to) {
+			continue
+		}
+		window = append(window, day)
+	}
+	return window
+}
+
+// round округляет норму до кратного 5 и ограничивает её границами формулы
+func (f NormFormula) round(raw float64) int {
+	norm := int(math.Round(raw/5)) * 5
+	return clamp(norm, f.MinDaily, f.MaxDaily)
+}
+
+// normStrategies — доступные стратегии; первая используется по умолчанию
+var normStrategies = []NormStrategy{
+	acsmStrategy{},
+	percentStrategy{ratio: PercentOfMaxRatio},
+	rampStrategy{stepPerWeek: RampStepPerWeek},
+	adaptiveStrategy{},
+}
+
+// FindNormStrategy возвращает стратегию по коду
+func FindNormStrategy(code string) (NormStrategy, bool) {
+	for _, strategy := range normStrategies {
+		if strategy.Info().Code == code {
+			return strategy, true
+		}
+	}
+	return nil, false
+}
+
+// userNormStrategy возвращает стратегию пользователя; неизвестный код — стратегия по умолчанию
+func (s *pushupService) userNormStrategy(ctx context.Context, userID int64) (NormStrategy, error) {
+	code, err := s.repo.GetNormStrategy(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if strategy, ok := FindNormStrategy(code); ok {
+		return strategy, nil
+	}
+	return normStrategies[0], nil
+}
+
+// collectNormInput собирает данные для стратегии до сохранения нового максимума
+func (s *pushupService) collectNormInput(ctx context.Context, userID int64, maxReps int) (NormInput, error) {
+	now := time.Now()
+	input := NormInput{MaxReps: maxReps, Formula: s.norm, Now: now}
+
+	currentNorm, err := s.repo.GetDailyNorm(ctx, userID)
+	if err != nil {
+		return input, err
+	}
+	input.CurrentNorm = currentNorm
+
+	lastMaxTest, err := s.repo.GetLastMaxRepsUpdate(ctx, userID)
+	if err != nil {
+		return input, err
+	}
+	input.LastMaxTest = lastMaxTest
+
+	from := dateOnly(now).AddDate(0, 0, -AdaptiveWindowDays)
+	totals, err := s.repo.GetDailyTotals(ctx, userID, from)
+	if err != nil {
+		return input, err
+	}
+	input.RecentTotals = totals
+
+	rest, err := s.repo.GetRestPeriods(ctx, userID)
+	if err != nil {
+		return input, err
+	}
+	input.RestPeriods = rest
+
+	return input, nil
+}
+
+// GetNormStrategies возвращает список доступных стратегий
+func (s *pushupService) GetNormStrategies() []model.NormStrategyInfo {
+	infos := make([]model.NormStrategyInfo, 0, len(normStrategies))
+	for _, strategy := range normStrategies {
+		infos = append(infos, strategy.Info())
+	}
+	return infos
+}
+
+// GetUserNormStrategy возвращает выбранную пользователем стратегию
+func (s *pushupService) GetUserNormStrategy(ctx context.Context, userID int64) (model.NormStrategyInfo, error) {
+	strategy, err := s.userNormStrategy(ctx, userID)
+	if err != nil {
+		return model.NormStrategyInfo{}, err
+	}
+	return strategy.Info(), nil
+}
+
+// SetNormStrategy сохраняет стратегию пользователя. Она применится при следующем тесте максимума
+func (s *pushupService) SetNormStrategy(ctx context.Context, userID int64, code string) (model.NormStrategyInfo, error) {
+	strategy, ok := FindNormStrategy(code)
+	if !ok {
+		return model.NormStrategyInfo{}, fmt.Errorf("неизвестная стратегия нормы: %s", code)
+	}
+
+	if err := s.repo.SetNormStrategy(ctx, userID, code); err != nil {
+		return model.NormStrategyInfo{}, err
+	}
+	return strategy.Info(), nil
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"trackerbot/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormStrategies_Calculate(t *testing.T) {
+	now := time.Date(2026, 3, 20, 12, 0, 0, 0, time.UTC)
+
+	completedDays := func(n, count int) []model.DailyTotal {
+		totals := make([]model.DailyTotal, 0, n)
+		for i := 0; i < n; i++ {
+			totals = append(totals, model.DailyTotal{Date: now.AddDate(0, 0, -i-1), Count: count})
+		}
+		return totals
+	}
+
+	tests := []struct {
+		name     string
+		strategy string
+		input    NormInput
+		want     int
+	}{
+		{"ACSM", NormStrategyACSM, NormInput{MaxReps: 25}, DefaultNormFormula.DailyNorm(25)},
+		{"PercentOfMax", NormStrategyPercent, NormInput{MaxReps: 30}, 90},
+		{"PercentClampedToMin", NormStrategyPercent, NormInput{MaxReps: 5}, MinDailyPushups},
+		{"RampTwoWeeks", NormStrategyRamp, NormInput{
+			MaxReps: 20, CurrentNorm: 100, LastMaxTest: now.AddDate(0, 0, -15), Now: now,
+		}, 100 + 2*RampStepPerWeek},
+		{"RampNotBelowACSM", NormStrategyRamp, NormInput{
+			MaxReps: 40, CurrentNorm: 60, LastMaxTest: now.AddDate(0, 0, -7), Now: now,
+		}, DefaultNormFormula.DailyNorm(40)},
+		{"RampFirstTest", NormStrategyRamp, NormInput{MaxReps: 20, Now: now}, DefaultNormFormula.DailyNorm(20)},
+		{"AdaptiveOftenCompleted", NormStrategyAdaptive, NormInput{
+			MaxReps: 25, CurrentNorm: 80, RecentTotals: completedDays(12, 90), Now: now,
+		}, DefaultNormFormula.round(float64(DefaultNormFormula.DailyNorm(25)) * 1.1)},
+		{"AdaptiveRarelyCompleted", NormStrategyAdaptive, NormInput{
+			MaxReps: 25, CurrentNorm: 80, RecentTotals: completedDays(3, 90), Now: now,
+		}, DefaultNormFormula.round(float64(DefaultNormFormula.DailyNorm(25)) * 0.9)},
+		{"AdaptiveInBetween", NormStrategyAdaptive, NormInput{
+			MaxReps: 25, CurrentNorm: 80, RecentTotals: completedDays(8, 90), Now: now,
+		}, DefaultNormFormula.DailyNorm(25)},
+		{"AdaptiveIgnoresToday", NormStrategyAdaptive, NormInput{
+			MaxReps: 25, CurrentNorm: 80, Now: now,
+			RecentTotals: append(completedDays(11, 90), model.DailyTotal{Date: now, Count: 90}),
+		}, DefaultNormFormula.DailyNorm(25)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			strategy, ok := FindNormStrategy(tt.strategy)
+			assert.True(t, ok)
+			tt.input.Formula = DefaultNormFormula
+			assert.Equal(t, tt.want, strategy.Calculate(tt.input))
+		})
+	}
+}
+
+func TestCompletionRate(t *testing.T) {
+	totals := []model.DailyTotal{{Count: 50}, {Count: 30}, {Count: 60}}
+
+	assert.InDelta(t, 0.2, CompletionRate(totals, 50, 10), 0.001)
+	assert.Equal(t, 0.0, CompletionRate(totals, 0, 10))
+	assert.Equal(t, 1.0, CompletionRate(totals, 10, 2))
+}
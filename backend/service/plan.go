@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"math"
+
+	"trackerbot/model"
+)
+
+const DefaultPlanSetSize = 10 // Размер подхода, если тест максимума ещё не пройден
+
+// PlanSetSize возвращает рекомендуемый размер подхода: ACSMIntensityRatio от максимума
+func PlanSetSize(maxReps int) int {
+	if maxReps <= 0 {
+		return DefaultPlanSetSize
+	}
+	return max(int(math.Round(float64(maxReps)*ACSMIntensityRatio)), 1)
+}
+
+// SplitIntoSets делит объём на минимальное число подходов не больше setSize,
+// выравнивая их по размеру (разница между подходами — не больше 1)
+func SplitIntoSets(volume, setSize int) []int {
+	if volume <= 0 || setSize <= 0 {
+		return nil
+	}
+
+	count := (volume + setSize - 1) / setSize
+	base, extra := volume/count, volume%count
+
+	sets := make([]int, count)
+	for i := range sets {
+		sets[i] = base
+		if i < extra {
+			sets[i]++
+		}
+	}
+	return sets
+}
+
+// BuildDailyPlan разбивает норму на подходы и отмечает сделанные по журналу подходов за день.
+// Записанный подход обычных отжиманий закрывает один запланированный, если он не меньше его;
+// подходы других вариантов и микроподходы GTG план не закрывают, но идут в сумму.
+// Остаток нормы заново делится на подходы того же размера
+func BuildDailyPlan(dailyNorm, maxReps, total int, sets []model.LoggedSet) model.DailyPlan {
+	setSize := PlanSetSize(maxReps)
+	plan := model.DailyPlan{
+		DailyNorm: dailyNorm,
+		MaxReps:   maxReps,
+		SetSize:   setSize,
+		Total:     total,
+	}
+
+	for _, reps := range SplitIntoSets(dailyNorm, setSize) {
+		plan.Sets = append(plan.Sets, model.PlannedSet{Reps: reps})
+	}
+
+	for _, set := range sets {
+		if set.GTG || set.Variant != model.VariantStandard {
+			continue
+		}
+		for i := range plan.Sets {
+			if !plan.Sets[i].Done && set.Count >= plan.Sets[i].Reps {
+				plan.Sets[i].Done = true
+				break
+			}
+		}
+	}
+	plan.Remaining = SplitIntoSets(dailyNorm-total, setSize)
+
+	return plan
+}
+
+// GetDailyPlan возвращает план подходов на сегодня
+func (s *pushupService) GetDailyPlan(ctx context.Context, userID int64) (*model.DailyPlan, error) {
+	dailyNorm, err := s.repo.GetDailyNorm(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	total, err := s.repo.GetTodayStat(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.dailyPlan(ctx, userID, dailyNorm, total)
+}
+
+// dailyPlan строит план по уже известным норме и сумме за день
+func (s *pushupService) dailyPlan(ctx context.Context, userID int64, dailyNorm, total int) (*model.DailyPlan, error) {
+	maxReps, err := s.repo.GetUserMaxReps(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	sets, err := s.repo.GetTodaySets(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := BuildDailyPlan(dailyNorm, maxReps, total, sets)
+	return &plan, nil
+}
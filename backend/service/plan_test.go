@@ -0,0 +1,81 @@
+package service
+
+import (
+	"testing"
+
+	"trackerbot/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitIntoSets(t *testing.T) {
+	tests := []struct {
+		name    string
+		volume  int
+		setSize int
+		want    []int
+	}{
+		{"Even", 120, 20, []int{20, 20, 20, 20, 20, 20}},
+		{"Balanced", 120, 18, []int{18, 17, 17, 17, 17, 17, 17}},
+		{"SmallerThanSet", 7, 18, []int{7}},
+		{"Nothing", 0, 18, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, SplitIntoSets(tt.volume, tt.setSize))
+		})
+	}
+}
+
+func TestBuildDailyPlan(t *testing.T) {
+	// Максимум 25 → подход 18 (70%), норма 120 → 7 подходов
+	plan := BuildDailyPlan(120, 25, 40, []model.LoggedSet{
+		{Variant: model.VariantStandard, Count: 18},
+		{Variant: model.VariantStandard, Count: 17},
+		{Variant: "diamond", Count: 5},
+	})
+
+	assert.Equal(t, 18, plan.SetSize)
+	assert.Len(t, plan.Sets, 7)
+	// Закрыты подходы 18 и 17; ромбовидные идут только в сумму
+	assert.Equal(t, 2, plan.DoneSets())
+	assert.Equal(t, []int{16, 16, 16, 16, 16}, plan.Remaining)
+
+	done := BuildDailyPlan(120, 25, 130, nil)
+	assert.Empty(t, done.Remaining)
+
+	noTest := BuildDailyPlan(40, 0, 0, nil)
+	assert.Equal(t, DefaultPlanSetSize, noTest.SetSize)
+	assert.Len(t, noTest.Sets, 4)
+}
+
+func TestBuildDailyPlan_MatchesLoggedSets(t *testing.T) {
+	tests := []struct {
+		name string
+		sets []model.LoggedSet
+		want int
+	}{
+		// Один большой подход закрывает только один запланированный
+		{"OneBigSet", []model.LoggedSet{{Variant: model.VariantStandard, Count: 54}}, 1},
+		// Подход меньше запланированного не засчитывается
+		{"TooSmall", []model.LoggedSet{{Variant: model.VariantStandard, Count: 12}}, 0},
+		// Второй подход на 17 закрывает следующий план 17, а не первый на 18
+		{"SkipsLarger", []model.LoggedSet{
+			{Variant: model.VariantStandard, Count: 17},
+			{Variant: model.VariantStandard, Count: 18},
+		}, 2},
+		{"GTG", []model.LoggedSet{{Variant: model.VariantStandard, Count: 20, GTG: true}}, 0},
+		{"Variant", []model.LoggedSet{{Variant: "diamond", Count: 20}}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			total := 0
+			for _, set := range tt.sets {
+				total += set.Count
+			}
+			assert.Equal(t, tt.want, BuildDailyPlan(120, 25, total, tt.sets).DoneSets())
+		})
+	}
+}
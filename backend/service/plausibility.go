@@ -0,0 +1,40 @@
+package service
+
+import (
+	"fmt"
+)
+
+const (
+	// Пороги правдоподобности записи
+	MaxSetToMaxRepsRatio = 1.5 // Подход больше 1.5× максимума за подход считается подозрительным
+	MaxDailyToNormRatio  = 4   // Дневная сумма больше 4× нормы считается подозрительной
+)
+
+// CheckPlausibility проверяет, правдоподобна ли запись относительно максимума и нормы пользователя
+// Аргументы:
+//
+//	count      - количество отжиманий в добавляемом подходе
+//	todayTotal - сумма отжиманий за сегодня до добавления
+//	maxReps    - лучший результат пользователя за один подход (0, если тест не пройден)
+//	dailyNorm  - дневная норма пользователя
+//
+// Возвращает:
+//
+//	true, если запись выглядит правдоподобно; иначе false и причину
+func CheckPlausibility(count, todayTotal, maxReps, dailyNorm int) (bool, string) {
+	if maxReps > 0 && float64(count) > float64(maxReps)*MaxSetToMaxRepsRatio {
+		return false, fmt.Sprintf(
+			"подход %d больше %.1f× максимума за подход (%d)",
+			count, MaxSetToMaxRepsRatio, maxReps,
+		)
+	}
+
+	if dailyNorm > 0 && todayTotal+count > dailyNorm*MaxDailyToNormRatio {
+		return false, fmt.Sprintf(
+			"сумма за день %d больше %d× дневной нормы (%d)",
+			todayTotal+count, MaxDailyToNormRatio, dailyNorm,
+		)
+	}
+
+	return true, ""
+}
@@ -0,0 +1,35 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckPlausibility(t *testing.T) {
+	tests := []struct {
+		name       string
+		count      int
+		todayTotal int
+		maxReps    int
+		dailyNorm  int
+		want       bool
+	}{
+		{"NormalSet", 20, 0, 30, 100, true},
+		{"SetAtLimit", 45, 0, 30, 100, true},
+		{"SetAboveMax", 46, 0, 30, 100, false},
+		{"NoMaxTest", 200, 0, 0, 100, true},
+		{"DailyAtLimit", 20, 380, 30, 100, true},
+		{"DailyAboveNorm", 20, 390, 30, 100, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, reason := CheckPlausibility(tt.count, tt.todayTotal, tt.maxReps, tt.dailyNorm)
+			assert.Equal(t, tt.want, got)
+			if !tt.want {
+				assert.NotEmpty(t, reason)
+			}
+		})
+	}
+}
@@ -0,0 +1,212 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"trackerbot/model"
+)
+
+// FindProgram возвращает программу по коду
+func FindProgram(code string) (model.Program, bool) {
+	for _, program := range programCatalog {
+		if program.Code == code {
+			return program, true
+		}
+	}
+	return model.Program{}, false
+}
+
+// PlaceInProgram выбирает уровень программы по максимуму за подход
+func PlaceInProgram(program model.Program, maxReps int) int {
+	for level, limit := range program.Placement {
+		if maxReps <= limit {
+			return level
+		}
+	}
+	return len(program.Placement)
+}
+
+// ProgramWorkoutFor возвращает тренировку, на которой сейчас находится пользователь.
+// Если после прошлой тренировки не прошло RecoveryHours, заполняется RestUntil
+func ProgramWorkoutFor(program model.Program, enrollment model.ProgramEnrollment) model.ProgramWorkout {
+	days := program.Weeks[enrollment.Week-1].Levels[enrollment.Level]
+
+	workout := model.ProgramWorkout{
+		ProgramCode: program.Code,
+		ProgramName: program.Name,
+		Level:       enrollment.Level,
+		Week:        enrollment.Week,
+		Day:         enrollment.Day,
+		TotalWeeks:  len(program.Weeks),
+		DaysInWeek:  len(days),
+		Sets:        days[enrollment.Day-1],
+		LastSetMax:  program.LastSetMax,
+	}
+
+	if enrollment.LastSessionAt != nil {
+		restUntil := enrollment.LastSessionAt.Add(RecoveryHours * time.Hour)
+		workout.RestUntil = &restUntil
+	}
+
+	return workout
+}
+
+// AdvanceProgram переводит пользователя после тренировки.
+// Неделя, в которой хотя бы одна тренировка не выполнена, повторяется;
+// после последней недели программа завершается (next == nil)
+func AdvanceProgram(
+	program model.Program,
+	enrollment model.ProgramEnrollment,
+	completed bool,
+	now time.Time,
+) (*model.ProgramEnrollment, string) {
+
+	next := enrollment
+	next.LastSessionAt = &now
+	next.FailedInWeek = enrollment.FailedInWeek || !completed
+
+	daysInWeek := len(program.Weeks[enrollment.Week-1].Levels[enrollment.Level])
+
+	switch {
+	case enrollment.Day < daysInWeek:
+		next.Day++
+		return &next, model.ProgramOutcomeNextDay
+
+	case next.FailedInWeek:
+		next.Day = 1
+		next.FailedInWeek = false
+		return &next, model.ProgramOutcomeRepeatWeek
+
+	case enrollment.Week < len(program.Weeks):
+		next.Week++
+		next.Day = 1
+		return &next, model.ProgramOutcomeNextWeek
+
+	default:
+		return nil, model.ProgramOutcomeFinished
+	}
+}
+
+// sumSets возвращает запланированный объём тренировки
+func sumSets(sets []int) int {
+	total := 0
+	for _, reps := range sets {
+		total += reps
+	}
+	return total
+}
+
+// GetPrograms возвращает каталог программ
+func (s *pushupService) GetPrograms() []model.Program {
+	return programCatalog
+}
+
+// EnrollProgram записывает пользователя на программу с уровнем по последнему тесту максимума
+func (s *pushupService) EnrollProgram(ctx context.Context, userID int64, code string) (*model.ProgramWorkout, error) {
+	program, ok := FindProgram(code)
+	if !ok {
+		return nil, fmt.Errorf("неизвестная программа: %s", code)
+	}
+
+	maxReps, err := s.repo.GetUserMaxReps(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if maxReps <= 0 {
+		return nil, fmt.Errorf("сначала пройдите тест максимальных отжиманий")
+	}
+
+	enrollment := model.ProgramEnrollment{
+		ProgramCode: program.Code,
+		Level:       PlaceInProgram(program, maxReps),
+		Week:        1,
+		Day:         1,
+		StartedAt:   time.Now(),
+	}
+	if err := s.repo.SaveProgramEnrollment(ctx, userID, enrollment); err != nil {
+		return nil, err
+	}
+
+	workout := ProgramWorkoutFor(program, enrollment)
+	return &workout, nil
+}
+
+// currentProgram возвращает программу пользователя и его место в ней (nil, если не записан)
+func (s *pushupService) currentProgram(ctx context.Context, userID int64) (model.Program, *model.ProgramEnrollment, error) {
+	enrollment, err := s.repo.GetProgramEnrollment(ctx, userID)
+	if err != nil || enrollment == nil {
+		return model.Program{}, nil, err
+	}
+
+	program, ok := FindProgram(enrollment.ProgramCode)
+	if !ok {
+		return model.Program{}, nil, fmt.Errorf("программа %s больше не поддерживается", enrollment.ProgramCode)
+	}
+
+	return program, enrollment, nil
+}
+
+// GetProgramWorkout возвращает текущую тренировку по программе или nil, если пользователь не записан
+func (s *pushupService) GetProgramWorkout(ctx context.Context, userID int64) (*model.ProgramWorkout, error) {
+	program, enrollment, err := s.currentProgram(ctx, userID)
+	if err != nil || enrollment == nil {
+		return nil, err
+	}
+
+	workout := ProgramWorkoutFor(program, *enrollment)
+	return &workout, nil
+}
+
+// CompleteProgramSession отмечает тренировку week/day выполненной (или нет) и переводит пользователя дальше.
+// Если это уже не текущая тренировка, возвращает model.ErrProgramSessionOutdated
+func (s *pushupService) CompleteProgramSession(
+	ctx context.Context,
+	userID int64,
+	week, day int,
+	completed bool,
+) (*model.ProgramSessionResult, error) {
+
+	program, enrollment, err := s.currentProgram(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if enrollment == nil {
+		return nil, fmt.Errorf("вы не записаны ни на одну программу")
+	}
+	if enrollment.Week != week || enrollment.Day != day {
+		return nil, model.ErrProgramSessionOutdated
+	}
+
+	current := ProgramWorkoutFor(program, *enrollment)
+	next, outcome := AdvanceProgram(program, *enrollment, completed, time.Now())
+
+	session := model.ProgramSession{
+		ProgramCode: program.Code,
+		Week:        enrollment.Week,
+		Day:         enrollment.Day,
+		Planned:     sumSets(current.Sets),
+		Completed:   completed,
+	}
+	if err := s.repo.RecordProgramSession(ctx, userID, session, next); err != nil {
+		return nil, err
+	}
+
+	result := &model.ProgramSessionResult{
+		ProgramName: program.Name,
+		Completed:   completed,
+		Outcome:     outcome,
+	}
+	if next != nil {
+		workout := ProgramWorkoutFor(program, *next)
+		result.Next = &workout
+	}
+
+	return result, nil
+}
+
+// LeaveProgram выписывает пользователя из программы
+func (s *pushupService) LeaveProgram(ctx context.Context, userID int64) error {
+	return s.repo.DeleteProgramEnrollment(ctx, userID)
+}
@@ -0,0 +1,77 @@
+package service
+
+import "trackerbot/model"
+
+// Коды программ тренировок
+const (
+	ProgramHundred = "hundred"
+	ProgramStarter = "starter"
+)
+
+// programCatalog — встроенные программы. Каждая неделя задаёт тренировки для всех уровней:
+// Levels[уровень][день] = подходы. Уровень выбирается по тесту максимума при записи.
+var programCatalog = []model.Program{
+	{
+		Code:        ProgramHundred,
+		Name:        "💯 100 отжиманий за 6 недель",
+		Description: "Классический план: 3 тренировки в неделю по 5 подходов, последний — на максимум",
+		Placement:   []int{5, 10},
+		LastSetMax:  true,
+		Weeks: []model.ProgramWeek{
+			{Levels: [][][]int{
+				{{2, 3, 2, 2, 3}, {3, 4, 2, 3, 4}, {4, 5, 4, 4, 5}},
+				{{6, 6, 4, 4, 5}, {6, 8, 6, 6, 7}, {8, 10, 7, 7, 10}},
+				{{10, 12, 7, 7, 9}, {10, 12, 8, 8, 12}, {11, 13, 9, 9, 13}},
+			}},
+			{Levels: [][][]int{
+				{{4, 6, 4, 4, 6}, {5, 6, 4, 4, 7}, {5, 7, 5, 5, 8}},
+				{{9, 11, 8, 8, 11}, {10, 12, 9, 9, 13}, {12, 13, 10, 10, 15}},
+				{{14, 14, 10, 10, 15}, {14, 16, 12, 12, 17}, {16, 17, 14, 14, 20}},
+			}},
+			{Levels: [][][]int{
+				{{10, 12, 7, 7, 9}, {10, 12, 8, 8, 12}, {11, 13, 9, 9, 13}},
+				{{12, 17, 13, 13, 17}, {14, 19, 14, 14, 19}, {16, 21, 15, 15, 21}},
+				{{14, 18, 14, 14, 20}, {20, 25, 15, 15, 25}, {22, 30, 20, 20, 28}},
+			}},
+			{Levels: [][][]int{
+				{{12, 14, 11, 10, 16}, {14, 16, 12, 12, 18}, {16, 18, 13, 13, 20}},
+				{{18, 22, 16, 16, 25}, {20, 25, 20, 20, 28}, {23, 28, 23, 23, 33}},
+				{{21, 25, 21, 21, 32}, {25, 29, 25, 25, 36}, {29, 33, 29, 29, 40}},
+			}},
+			{Levels: [][][]int{
+				{{17, 19, 15, 15, 20}, {18, 20, 17, 17, 22}, {20, 22, 18, 18, 25}},
+				{{28, 35, 25, 22, 35}, {30, 35, 28, 25, 38}, {32, 38, 30, 28, 40}},
+				{{36, 40, 30, 24, 40}, {38, 42, 32, 28, 45}, {40, 45, 35, 30, 50}},
+			}},
+			{Levels: [][][]int{
+				{{22, 24, 20, 20, 28}, {24, 26, 22, 22, 30}, {26, 28, 24, 24, 32}},
+				{{35, 40, 30, 28, 45}, {38, 42, 32, 30, 50}, {40, 45, 35, 32, 55}},
+				{{45, 50, 40, 35, 55}, {48, 54, 42, 38, 60}, {50, 58, 45, 40, 65}},
+			}},
+		},
+	},
+	{
+		Code:        ProgramStarter,
+		Name:        "🌱 Старт за 4 недели",
+		Description: "Для тех, кто делает меньше 10 отжиманий: 3 тренировки в неделю по 4 подхода без отказа",
+		Placement:   []int{4},
+		Weeks: []model.ProgramWeek{
+			{Levels: [][][]int{
+				{{2, 2, 2, 2}, {2, 3, 2, 2}, {3, 3, 2, 2}},
+				{{4, 5, 4, 4}, {5, 5, 4, 4}, {5, 6, 5, 5}},
+			}},
+			{Levels: [][][]int{
+				{{3, 3, 3, 3}, {3, 4, 3, 3}, {4, 4, 3, 3}},
+				{{6, 6, 5, 5}, {6, 7, 6, 6}, {7, 7, 6, 6}},
+			}},
+			{Levels: [][][]int{
+				{{4, 4, 4, 4}, {4, 5, 4, 4}, {5, 5, 4, 4}},
+				{{7, 8, 7, 7}, {8, 8, 7, 7}, {8, 9, 8, 8}},
+			}},
+			{Levels: [][][]int{
+				{{5, 5, 5, 5}, {5, 6, 5, 5}, {6, 6, 5, 5}},
+				{{9, 9, 8, 8}, {9, 10, 9, 9}, {10, 10, 9, 9}},
+			}},
+		},
+	},
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"trackerbot/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestProgramCatalog_Consistent(t *testing.T) {
+	for _, program := range programCatalog {
+		levels := len(program.Placement) + 1
+		for w, week := range program.Weeks {
+			assert.Len(t, week.Levels, levels, "%s: неделя %d", program.Code, w+1)
+			for _, days := range week.Levels {
+				assert.NotEmpty(t, days, "%s: неделя %d", program.Code, w+1)
+				for _, sets := range days {
+					assert.NotEmpty(t, sets, "%s: неделя %d", program.Code, w+1)
+				}
+			}
+		}
+	}
+}
+
+func TestPlaceInProgram(t *testing.T) {
+	program, ok := FindProgram(ProgramHundred)
+	assert.True(t, ok)
+
+	assert.Equal(t, 0, PlaceInProgram(program, 3))
+	assert.Equal(t, 0, PlaceInProgram(program, 5))
+	assert.Equal(t, 1, PlaceInProgram(program, 8))
+	assert.Equal(t, 2, PlaceInProgram(program, 25))
+}
+
+func TestAdvanceProgram(t *testing.T) {
+	program, _ := FindProgram(ProgramHundred)
+	now := time.Date(2026, 3, 18, 19, 0, 0, 0, time.UTC)
+	at := func(week, day int, failed bool) model.ProgramEnrollment {
+		return model.ProgramEnrollment{ProgramCode: ProgramHundred, Week: week, Day: day, FailedInWeek: failed}
+	}
+
+	tests := []struct {
+		name       string
+		enrollment model.ProgramEnrollment
+		completed  bool
+		outcome    string
+		week, day  int
+		failed     bool
+	}{
+		{"NextDay", at(1, 1, false), true, model.ProgramOutcomeNextDay, 1, 2, false},
+		{"FailedDayContinuesWeek", at(1, 2, false), false, model.ProgramOutcomeNextDay, 1, 3, true},
+		{"NextWeek", at(1, 3, false), true, model.ProgramOutcomeNextWeek, 2, 1, false},
+		{"RepeatWeekAfterFailure", at(2, 3, true), true, model.ProgramOutcomeRepeatWeek, 2, 1, false},
+		{"RepeatWeekOnLastDay", at(2, 3, false), false, model.ProgramOutcomeRepeatWeek, 2, 1, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next, outcome := AdvanceProgram(program, tt.enrollment, tt.completed, now)
+			assert.Equal(t, tt.outcome, outcome)
+			if assert.NotNil(t, next) {
+				assert.Equal(t, tt.week, next.Week)
+				assert.Equal(t, tt.day, next.Day)
+				assert.Equal(t, tt.failed, next.FailedInWeek)
+				assert.Equal(t, now, *next.LastSessionAt)
+			}
+		})
+	}
+
+	next, outcome := AdvanceProgram(program, at(len(program.Weeks), 3, false), true, now)
+	assert.Nil(t, next)
+	assert.Equal(t, model.ProgramOutcomeFinished, outcome)
+}
+
+func TestService_EnrollProgram(t *testing.T) {
+	mockRepo := new(MockPushupRepository)
+	svc := NewPushupService(mockRepo, Config{})
+	ctx := context.Background()
+
+	mockRepo.On("GetUserMaxReps", ctx, int64(1)).Return(8, nil).Once()
+	mockRepo.On("SaveProgramEnrollment", ctx, int64(1), mock.MatchedBy(func(e model.ProgramEnrollment) bool {
+		return e.ProgramCode == ProgramHundred && e.Level == 1 && e.Week == 1 && e.Day == 1
+	})).Return(nil).Once()
+
+	workout, err := svc.EnrollProgram(ctx, 1, ProgramHundred)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []int{6, 6, 4, 4, 5}, workout.Sets)
+	assert.Nil(t, workout.RestUntil)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestService_EnrollProgram_RequiresMaxTest(t *testing.T) {
+	mockRepo := new(MockPushupRepository)
+	svc := NewPushupService(mockRepo, Config{})
+
+	mockRepo.On("GetUserMaxReps", mock.Anything, int64(1)).Return(0, nil).Once()
+
+	_, err := svc.EnrollProgram(context.Background(), 1, ProgramHundred)
+
+	assert.Error(t, err)
+	mockRepo.AssertNotCalled(t, "SaveProgramEnrollment")
+}
+
+func TestService_CompleteProgramSession(t *testing.T) {
+	mockRepo := new(MockPushupRepository)
+	svc := NewPushupService(mockRepo, Config{})
+	ctx := context.Background()
+
+	mockRepo.On("GetProgramEnrollment", ctx, int64(1)).Return(&model.ProgramEnrollment{
+		ProgramCode: ProgramHundred, Level: 0, Week: 1, Day: 3,
+	}, nil).Once()
+	mockRepo.On("RecordProgramSession", ctx, int64(1),
+		model.ProgramSession{ProgramCode: ProgramHundred, Week: 1, Day: 3, Planned: 22, Completed: true},
+		mock.MatchedBy(func(next *model.ProgramEnrollment) bool { return next.Week == 2 && next.Day == 1 }),
+	).Return(nil).Once()
+
+	result, err := svc.CompleteProgramSession(ctx, 1, 1, 3, true)
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.ProgramOutcomeNextWeek, result.Outcome)
+	if assert.NotNil(t, result.Next) {
+		assert.Equal(t, []int{4, 6, 4, 4, 6}, result.Next.Sets)
+		assert.NotNil(t, result.Next.RestUntil)
+	}
+	mockRepo.AssertExpectations(t)
+}
+
+func TestService_CompleteProgramSession_Outdated(t *testing.T) {
+	mockRepo := new(MockPushupRepository)
+	svc := NewPushupService(mockRepo, Config{})
+	ctx := context.Background()
+
+	mockRepo.On("GetProgramEnrollment", ctx, int64(1)).Return(&model.ProgramEnrollment{
+		ProgramCode: ProgramHundred, Level: 0, Week: 1, Day: 3,
+	}, nil).Once()
+
+	// Кнопка от тренировки 1/2, а пользователь уже на 1/3
+	_, err := svc.CompleteProgramSession(ctx, 1, 1, 2, true)
+
+	assert.ErrorIs(t, err, model.ErrProgramSessionOutdated)
+	mockRepo.AssertNotCalled(t, "RecordProgramSession", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"trackerbot/model"
+)
+
+// index возвращает индекс ранга в лестнице для maxReps
+func (r Ranks) index(maxReps int) int {
+	for i := len(r) - 1; i >= 0; i-- {
+		if maxReps >= r[i].Threshold {
+			return i
+		}
+	}
+	return 0
+}
+
+// DetectChange сравнивает ранги до и после теста.
+// Возвращает nil, если ранг не изменился
+func (r Ranks) DetectChange(prevMaxReps, newMaxReps int) *model.RankChange {
+	from := r.index(prevMaxReps)
+	to := r.index(newMaxReps)
+	if from == to {
+		return nil
+	}
+
+	return &model.RankChange{
+		Date:     time.Now(),
+		FromRank: r[from].Title(),
+		ToRank:   r[to].Title(),
+		MaxReps:  newMaxReps,
+		Promoted: to > from,
+	}
+}
+
+// recordRankChange сохраняет смену ранга, если она произошла
+func (s *pushupService) recordRankChange(
+	ctx context.Context,
+	userID int64,
+	prevMaxReps int,
+	newMaxReps int,
+) (*model.RankChange, error) {
+
+	change := s.ranks.DetectChange(prevMaxReps, newMaxReps)
+	if change == nil {
+		return nil, nil
+	}
+
+	if err := s.repo.AddRankChange(ctx, userID, *change); err != nil {
+		return nil, fmt.Errorf("ошибка сохранения смены ранга: %w", err)
+	}
+
+	username, err := s.repo.GetUsername(ctx, userID)
+	if err != nil || username == "" {
+		username = fmt.Sprintf("User%d", userID)
+	}
+	change.Username = username
+
+	return change, nil
+}
+
+// GetRankHistory возвращает историю смены рангов пользователя
+func (s *pushupService) GetRankHistory(ctx context.Context, userID int64) ([]model.RankChange, error) {
+	return s.repo.GetRankHistory(ctx, userID)
+}
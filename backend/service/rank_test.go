@@ -0,0 +1,38 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRanks_DetectChange(t *testing.T) {
+	tests := []struct {
+		name         string
+		prev, next   int
+		wantChange   bool
+		wantPromoted bool
+	}{
+		{"SameRank", 21, 24, false, false},
+		{"FirstTestPromotes", 0, 12, true, true},
+		{"RankUp", 24, 25, true, true},
+		{"Demotion", 31, 28, true, false},
+		{"SkipSeveralRanks", 10, 45, true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			change := DefaultRanks.DetectChange(tt.prev, tt.next)
+			if !tt.wantChange {
+				assert.Nil(t, change)
+				return
+			}
+
+			assert.NotNil(t, change)
+			assert.Equal(t, tt.wantPromoted, change.Promoted)
+			assert.Equal(t, DefaultRanks.Title(tt.prev), change.FromRank)
+			assert.Equal(t, DefaultRanks.Title(tt.next), change.ToRank)
+			assert.Equal(t, tt.next, change.MaxReps)
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"trackerbot/model"
+)
+
+// IsRecoveredForMaxTest проверяет, что за последние RecoveryHours не было тяжёлого
+// объёмного дня (выполненной дневной нормы) — иначе тест покажет заниженный результат
+func IsRecoveredForMaxTest(totals []model.DailyTotal, dailyNorm int, now time.Time) bool {
+	if dailyNorm <= 0 {
+		return true
+	}
+
+	recoveryDays := RecoveryHours / 24
+	cutoff := dateOnly(now).AddDate(0, 0, -(recoveryDays - 1))
+
+	for _, day := range totals {
+		if dateOnly(day.Date).Before(cutoff) {
+			continue
+		}
+		if day.Count >= dailyNorm {
+			return false
+		}
+	}
+
+	return true
+}
+
+// dateOnly отбрасывает время и часовой пояс, оставляя календарную дату
+func dateOnly(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// GetMaxTestReminders возвращает пользователей, которым пора пройти тест максимума:
+// последний тест старше interval и организм успел восстановиться после объёмных дней
+func (s *pushupService) GetMaxTestReminders(ctx context.Context, interval time.Duration) ([]model.MaxTestReminder, error) {
+	now := time.Now()
+
+	candidates, err := s.repo.GetMaxTestReminderCandidates(ctx, now.Add(-interval))
+	if err != nil {
+		return nil, err
+	}
+
+	from := now.Add(-RecoveryHours * time.Hour)
+
+	var reminders []model.MaxTestReminder
+	for _, candidate := range candidates {
+		totals, err := s.repo.GetDailyTotals(ctx, candidate.UserID, from)
+		if err != nil {
+			return nil, err
+		}
+
+		if !IsRecoveredForMaxTest(totals, candidate.DailyNorm, now) {
+			continue
+		}
+
+		reminders = append(reminders, candidate)
+	}
+
+	return reminders, nil
+}
+
+// MarkMaxTestReminded запоминает, что напоминание о тесте отправлено
+func (s *pushupService) MarkMaxTestReminded(ctx context.Context, userID int64) error {
+	return s.repo.MarkMaxTestReminded(ctx, userID)
+}
@@ -0,0 +1,35 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"trackerbot/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsRecoveredForMaxTest(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.Local)
+	day := func(offset int) time.Time {
+		return time.Date(2026, 3, 10+offset, 0, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name   string
+		totals []model.DailyTotal
+		want   bool
+	}{
+		{"NoData", nil, true},
+		{"LightYesterday", []model.DailyTotal{{Date: day(-1), Count: 30}}, true},
+		{"HeavyYesterday", []model.DailyTotal{{Date: day(-1), Count: 100}}, false},
+		{"HeavyToday", []model.DailyTotal{{Date: day(0), Count: 100}}, false},
+		{"HeavyTwoDaysAgo", []model.DailyTotal{{Date: day(-2), Count: 100}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsRecoveredForMaxTest(tt.totals, 100, now))
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"trackerbot/model"
+)
+
+const MaxRestDays = 30 // Самый длинный перерыв, который можно поставить за раз
+
+// IsRestDay сообщает, попадает ли день в один из перерывов
+func IsRestDay(periods []model.RestPeriod, date time.Time) bool {
+	for _, period := range periods {
+		if period.Covers(date) {
+			return true
+		}
+	}
+	return false
+}
+
+// CountRestDays считает дни отдыха в полуинтервале [from, to)
+func CountRestDays(periods []model.RestPeriod, from, to time.Time) int {
+	if len(periods) == 0 {
+		return 0
+	}
+
+	count := 0
+	for day := dateOnly(from); day.Before(dateOnly(to)); day = day.AddDate(0, 0, 1) {
+		if IsRestDay(periods, day) {
+			count++
+		}
+	}
+	return count
+}
+
+// ExcludeRestDays убирает из сумм по дням дни отдыха
+func ExcludeRestDays(totals []model.DailyTotal, periods []model.RestPeriod) []model.DailyTotal {
+	if len(periods) == 0 {
+		return totals
+	}
+
+	filtered := make([]model.DailyTotal, 0, len(totals))
+	for _, day := range totals {
+		if !IsRestDay(periods, day.Date) {
+			filtered = append(filtered, day)
+		}
+	}
+	return filtered
+}
+
+// ActiveRestPeriod возвращает перерыв, идущий сегодня (с самой поздней датой окончания), или nil
+func ActiveRestPeriod(periods []model.RestPeriod, now time.Time) *model.RestPeriod {
+	var active *model.RestPeriod
+	for i := range periods {
+		if !periods[i].Covers(now) {
+			continue
+		}
+		if active == nil || periods[i].EndDate.After(active.EndDate) {
+			active = &periods[i]
+		}
+	}
+	return active
+}
+
+// NewRestPeriod формирует перерыв на days дней начиная с сегодняшнего
+func NewRestPeriod(kind string, days int, now time.Time) (model.RestPeriod, error) {
+	if kind != model.RestKindDay && kind != model.RestKindVacation {
+		return model.RestPeriod{}, fmt.Errorf("неизвестный вид перерыва: %s", kind)
+	}
+	if days <= 0 || days > MaxRestDays {
+		return model.RestPeriod{}, fmt.Errorf("перерыв должен длиться от 1 до %d дней", MaxRestDays)
+	}
+
+	start := dateOnly(now)
+	return model.RestPeriod{
+		Kind:      kind,
+		StartDate: start,
+		EndDate:   start.AddDate(0, 0, days-1),
+	}, nil
+}
+
+// GetActiveRest возвращает текущий перерыв пользователя или nil
+func (s *pushupService) GetActiveRest(ctx context.Context, userID int64) (*model.RestPeriod, error) {
+	periods, err := s.repo.GetRestPeriods(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return ActiveRestPeriod(periods, time.Now()), nil
+}
+
+// StartRest ставит день отдыха или отпуск; перерыв заканчивается автоматически
+func (s *pushupService) StartRest(ctx context.Context, userID int64, kind string, days int) (model.RestPeriod, error) {
+	period, err := NewRestPeriod(kind, days, time.Now())
+	if err != nil {
+		return model.RestPeriod{}, err
+	}
+
+	if err := s.repo.AddRestPeriod(ctx, userID, period); err != nil {
+		return model.RestPeriod{}, err
+	}
+	return period, nil
+}
+
+// EndRest досрочно завершает текущий перерыв
+func (s *pushupService) EndRest(ctx context.Context, userID int64) error {
+	return s.repo.EndRestPeriods(ctx, userID)
+}
@@ -0,0 +1,47 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"trackerbot/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewRestPeriod(t *testing.T) {
+	now := time.Date(2026, 3, 18, 21, 30, 0, 0, time.UTC)
+
+	period, err := NewRestPeriod(model.RestKindVacation, 7, now)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 3, 18, 0, 0, 0, 0, time.UTC), period.StartDate)
+	assert.Equal(t, time.Date(2026, 3, 24, 0, 0, 0, 0, time.UTC), period.EndDate)
+
+	day, err := NewRestPeriod(model.RestKindDay, 1, now)
+	assert.NoError(t, err)
+	assert.Equal(t, day.StartDate, day.EndDate)
+
+	_, err = NewRestPeriod("sabbatical", 3, now)
+	assert.Error(t, err)
+	_, err = NewRestPeriod(model.RestKindVacation, MaxRestDays+1, now)
+	assert.Error(t, err)
+}
+
+func TestCountRestDaysAndActivePeriod(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 3, d, 0, 0, 0, 0, time.UTC) }
+	periods := []model.RestPeriod{
+		{Kind: model.RestKindDay, StartDate: day(10), EndDate: day(10)},
+		{Kind: model.RestKindVacation, StartDate: day(12), EndDate: day(15)},
+		{Kind: model.RestKindVacation, StartDate: day(14), EndDate: day(20)},
+	}
+
+	assert.Equal(t, 0, CountRestDays(nil, day(1), day(31)))
+	assert.Equal(t, 1, CountRestDays(periods, day(9), day(12)))
+	assert.Equal(t, 10, CountRestDays(periods, day(1), day(31)))
+
+	active := ActiveRestPeriod(periods, day(15).Add(18*time.Hour))
+	if assert.NotNil(t, active) {
+		assert.Equal(t, day(20), active.EndDate)
+	}
+	assert.Nil(t, ActiveRestPeriod(periods, day(11)))
+}
@@ -2,46 +2,251 @@ package service
 
 import (
 	"bytes"
-	"log"
+	"context"
+	"fmt"
+	"image/color"
+	"time"
+
 	"trackerbot/model"
 
 	"gonum.org/v1/plot"
 	"gonum.org/v1/plot/plotter"
 	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
 )
 
-func SendSchedule(chatID int64, items []model.MaxRepsHistoryItem) (bytes.Buffer, error) {
+// maxSchedulePoints — сколько точек помещается на график без наложения
+const maxSchedulePoints = 120
 
-	points := make(plotter.XYs, len(items))
+var (
+	scheduleRecordColor = color.RGBA{R: 220, G: 50, B: 47, A: 255}
+	scheduleRankColor   = color.RGBA{R: 150, G: 150, B: 150, A: 255}
+	scheduleNormColor   = color.RGBA{R: 38, G: 139, B: 210, A: 255}
+)
+
+// ScheduleOptions — что дополнительно нанести на график прогресса
+type ScheduleOptions struct {
+	Title  string
+	YLabel string
+	Ranks  []model.RankInfo  // Пороги рангов (пусто — без линий рангов)
+	Norm   []model.NormPoint // Дневная норма по датам (пусто — без наложения)
+}
+
+// SendSchedule строит график теста максимума по датам с рекордом и порогами рангов.
+// ranks — пороги рангов, norm накладывает дневную норму второй линией (nil — без неё)
+func SendSchedule(items []model.MaxRepsHistoryItem, ranks []model.RankInfo, norm []model.NormPoint) (bytes.Buffer, error) {
+	return renderSchedule(items, ScheduleOptions{
+		Title:  "Максимум за подход",
+		YLabel: "Количество отжиманий",
+		Ranks:  ranks,
+		Norm:   norm,
+	})
+}
+
+// SendExerciseSchedule строит график прогресса по упражнению из каталога
+func SendExerciseSchedule(exercise model.Exercise, items []model.MaxRepsHistoryItem) (bytes.Buffer, error) {
+	yLabel := "Повторений за подход"
+	if exercise.IsTimed() {
+		yLabel = "Секунд за подход"
+	}
+
+	return renderSchedule(items, ScheduleOptions{Title: exercise.Name, YLabel: yLabel})
+}
+
+// DownsampleHistory прореживает историю (новые первыми) до limit точек:
+// история делится на равные отрезки, от каждого берётся лучший результат.
+// Самая свежая запись сохраняется всегда
+func DownsampleHistory(items []model.MaxRepsHistoryItem, limit int) []model.MaxRepsHistoryItem {
+	if limit <= 0 || len(items) <= limit {
+		return items
+	}
+
+	sampled := make([]model.MaxRepsHistoryItem, 0, limit)
+	sampled = append(sampled, items[0])
+
+	rest := items[1:]
+	buckets := limit - 1
+	for i := 0; i < buckets; i++ {
+		bucket := rest[i*len(rest)/buckets : (i+1)*len(rest)/buckets]
+		if len(bucket) == 0 {
+			continue
+		}
+
+		best := bucket[0]
+		for _, item := range bucket[1:] {
+			if item.MaxReps > best.MaxReps {
+				best = item
+			}
+		}
+		sampled = append(sampled, best)
+	}
+
+	return sampled
+}
+
+// NormSeries восстанавливает дневную норму по датам из истории изменений (старые первыми):
+// от начала графика действует норма до первого изменения, в конце — текущая
+func NormSeries(changes []model.NormChange, currentNorm int, from, now time.Time) []model.NormPoint {
+	start := currentNorm
+	if len(changes) > 0 {
+		start = changes[0].OldNorm
+	}
+
+	series := []model.NormPoint{{Date: from, Norm: start}}
+	for _, change := range changes {
+		if change.ChangedAt.Before(from) {
+			series[0].Norm = change.NewNorm
+			continue
+		}
+		series = append(series, model.NormPoint{Date: change.ChangedAt, Norm: change.NewNorm})
+	}
+
+	return append(series, model.NormPoint{Date: now, Norm: currentNorm})
+}
 
-	for i := range items {
-		points[i].X = float64(i + 1)
-		points[i].Y = float64(items[len(items)-1-i].MaxReps)
+// normSeries загружает историю нормы пользователя и восстанавливает норму по датам [from, now].
+// В norm_history пишется каждое изменение нормы (SetDailyNorm и автоподстройка),
+// поэтому ступени совпадают с ручной установкой, тестами максимума и правками истории
+func (s *pushupService) normSeries(ctx context.Context, userID int64, from, now time.Time) ([]model.NormPoint, error) {
+	changes, err := s.repo.GetNormHistory(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	currentNorm, err := s.repo.GetDailyNorm(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return NormSeries(changes, currentNorm, from, now), nil
+}
+
+// scheduleX переводит дату в координату временной оси
+func scheduleX(date time.Time) float64 {
+	return float64(date.Unix())
+}
+
+func renderSchedule(items []model.MaxRepsHistoryItem, opts ScheduleOptions) (bytes.Buffer, error) {
+	if len(items) == 0 {
+		return bytes.Buffer{}, fmt.Errorf("нет данных для графика")
+	}
+
+	items = DownsampleHistory(items, maxSchedulePoints)
+
+	points := make(plotter.XYs, len(items))
+	record := items[0]
+	for i, item := range items {
+		points[len(items)-1-i] = plotter.XY{X: scheduleX(item.Date), Y: float64(item.MaxReps)}
+		if item.MaxReps > record.MaxReps {
+			record = item
+		}
 	}
 
-	// Создаем график
 	p := plot.New()
-	p.Title.Text = "Количество / Дни"
-	p.X.Label.Text = "Дни фиксации прогресса"
-	p.Y.Label.Text = "Количество отжиманий"
+	p.Title.Text = opts.Title
+	p.X.Label.Text = "Дата теста"
+	p.Y.Label.Text = opts.YLabel
+	p.X.Tick.Marker = plot.TimeTicks{
+		Format: "02.01.06",
+		Time:   func(t float64) time.Time { return time.Unix(int64(t), 0) },
+	}
+	p.Y.Min = 0
+	p.Add(plotter.NewGrid())
 
-	line, _ := plotter.NewLine(points)
-	scatter, _ := plotter.NewScatter(points)
+	// Один тест или все в один день — растягиваем ось, чтобы точка не легла на край
+	first, last := points[0].X, points[len(points)-1].X
+	if last-first < 24*60*60 {
+		p.X.Min = first - 3*24*60*60
+		p.X.Max = last + 3*24*60*60
+	}
 
+	line, scatter, err := plotter.NewLinePoints(points)
+	if err != nil {
+		return bytes.Buffer{}, fmt.Errorf("ошибка построения линии прогресса: %w", err)
+	}
 	p.Add(line, scatter)
+	p.Legend.Add("Максимум за подход", line, scatter)
 
-	// Рендерим в bytes.Buffer
-	var buf bytes.Buffer
-	writerTo, err := p.WriterTo(8*vg.Inch, 4*vg.Inch, "png")
+	if err := addRankThresholds(p, opts.Ranks, record.MaxReps); err != nil {
+		return bytes.Buffer{}, err
+	}
+
+	if len(opts.Norm) > 0 {
+		normPoints := make(plotter.XYs, len(opts.Norm))
+		for i, point := range opts.Norm {
+			normPoints[i] = plotter.XY{X: scheduleX(point.Date), Y: float64(point.Norm)}
+		}
+
+		normLine, err := plotter.NewLine(normPoints)
+		if err != nil {
+			return bytes.Buffer{}, fmt.Errorf("ошибка построения линии нормы: %w", err)
+		}
+		normLine.StepStyle = plotter.PostStep
+		normLine.Color = scheduleNormColor
+		normLine.Dashes = []vg.Length{vg.Points(4), vg.Points(2)}
+		p.Add(normLine)
+		p.Legend.Add("Дневная норма", normLine)
+	}
+
+	recordPoint := plotter.XYs{{X: scheduleX(record.Date), Y: float64(record.MaxReps)}}
+	recordMark, err := plotter.NewScatter(recordPoint)
+	if err != nil {
+		return bytes.Buffer{}, fmt.Errorf("ошибка отметки рекорда: %w", err)
+	}
+	recordMark.GlyphStyle = draw.GlyphStyle{Color: scheduleRecordColor, Radius: vg.Points(5), Shape: draw.PyramidGlyph{}}
+
+	recordLabel, err := plotter.NewLabels(plotter.XYLabels{
+		XYs:    recordPoint,
+		Labels: []string{fmt.Sprintf("Рекорд: %d", record.MaxReps)},
+	})
 	if err != nil {
-		log.Fatal(err)
+		return bytes.Buffer{}, fmt.Errorf("ошибка подписи рекорда: %w", err)
 	}
+	recordLabel.Offset = vg.Point{X: vg.Points(6), Y: vg.Points(4)}
+	p.Add(recordMark, recordLabel)
+
+	p.Legend.Top = true
+	p.Legend.Left = true
 
-	_, err = writerTo.WriteTo(&buf)
+	writerTo, err := p.WriterTo(8*vg.Inch, 4*vg.Inch, "png")
 	if err != nil {
+		return bytes.Buffer{}, fmt.Errorf("ошибка отрисовки графика: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if _, err := writerTo.WriteTo(&buf); err != nil {
 		return bytes.Buffer{}, err
 	}
 
 	return buf, nil
+}
+
+// addRankThresholds рисует пороги пройденных рангов и ближайшего следующего
+func addRankThresholds(p *plot.Plot, ranks []model.RankInfo, record int) error {
+	for _, rank := range ranks {
+		if rank.Threshold <= 0 {
+			continue
+		}
 
+		threshold := float64(rank.Threshold)
+		rule := plotter.NewFunction(func(float64) float64 { return threshold })
+		rule.Color = scheduleRankColor
+		rule.Dashes = []vg.Length{vg.Points(2), vg.Points(3)}
+		p.Add(rule)
+
+		label, err := plotter.NewLabels(plotter.XYLabels{
+			XYs:    plotter.XYs{{X: p.X.Min, Y: threshold}},
+			Labels: []string{rank.Name},
+		})
+		if err != nil {
+			return fmt.Errorf("ошибка подписи ранга: %w", err)
+		}
+		label.TextStyle[0].Color = scheduleRankColor
+		label.Offset = vg.Point{X: vg.Points(2), Y: vg.Points(2)}
+		p.Add(label)
+
+		if rank.Threshold > record {
+			break
+		}
+	}
+	return nil
 }
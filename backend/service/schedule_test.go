@@ -0,0 +1,108 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"trackerbot/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+func TestNormSeries(t *testing.T) {
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.Local)
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.Local)
+
+	t.Run("без изменений — текущая норма на всём графике", func(t *testing.T) {
+		series := NormSeries(nil, 100, from, now)
+
+		assert.Equal(t, []model.NormPoint{{Date: from, Norm: 100}, {Date: now, Norm: 100}}, series)
+	})
+
+	t.Run("изменения внутри графика", func(t *testing.T) {
+		changedAt := time.Date(2026, 2, 1, 0, 0, 0, 0, time.Local)
+		changes := []model.NormChange{{ChangedAt: changedAt, OldNorm: 80, NewNorm: 90}}
+
+		series := NormSeries(changes, 95, from, now)
+
+		assert.Equal(t, []model.NormPoint{
+			{Date: from, Norm: 80},
+			{Date: changedAt, Norm: 90},
+			{Date: now, Norm: 95},
+		}, series)
+	})
+
+	t.Run("изменения до начала графика", func(t *testing.T) {
+		changes := []model.NormChange{{ChangedAt: from.AddDate(0, 0, -10), OldNorm: 60, NewNorm: 70}}
+
+		series := NormSeries(changes, 70, from, now)
+
+		assert.Equal(t, []model.NormPoint{{Date: from, Norm: 70}, {Date: now, Norm: 70}}, series)
+	})
+}
+
+func TestService_NormSeries_ManualChanges(t *testing.T) {
+	mockRepo := new(MockPushupRepository)
+	svc := &pushupService{repo: mockRepo}
+	ctx := context.Background()
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.Local)
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.Local)
+	manualAt := time.Date(2026, 1, 20, 0, 0, 0, 0, time.Local)
+	testAt := time.Date(2026, 2, 10, 0, 0, 0, 0, time.Local)
+
+	mockRepo.On("GetNormHistory", ctx, int64(1)).Return([]model.NormChange{
+		{ChangedAt: manualAt, OldNorm: 60, NewNorm: 75},
+		{ChangedAt: testAt, OldNorm: 75, NewNorm: 90},
+	}, nil).Once()
+	mockRepo.On("GetDailyNorm", ctx, int64(1)).Return(90, nil).Once()
+
+	series, err := svc.normSeries(ctx, 1, from, now)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []model.NormPoint{
+		{Date: from, Norm: 60},
+		{Date: manualAt, Norm: 75},
+		{Date: testAt, Norm: 90},
+		{Date: now, Norm: 90},
+	}, series)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestSendSchedule(t *testing.T) {
+	first := time.Date(2026, 1, 5, 0, 0, 0, 0, time.Local)
+	history := []model.MaxRepsHistoryItem{
+		{Date: first.AddDate(0, 2, 0), MaxReps: 28},
+		{Date: first.AddDate(0, 0, 10), MaxReps: 31},
+		{Date: first, MaxReps: 22},
+	}
+
+	t.Run("по датам", func(t *testing.T) {
+		buf, err := SendSchedule(history, DefaultRanks, nil)
+
+		assert.NoError(t, err)
+		assert.True(t, bytes.HasPrefix(buf.Bytes(), pngSignature))
+	})
+
+	t.Run("с наложенной нормой", func(t *testing.T) {
+		buf, err := SendSchedule(history, DefaultRanks, NormSeries(nil, 90, first, first.AddDate(0, 3, 0)))
+
+		assert.NoError(t, err)
+		assert.True(t, bytes.HasPrefix(buf.Bytes(), pngSignature))
+	})
+
+	t.Run("один тест", func(t *testing.T) {
+		buf, err := SendSchedule(history[2:], DefaultRanks, nil)
+
+		assert.NoError(t, err)
+		assert.True(t, bytes.HasPrefix(buf.Bytes(), pngSignature))
+	})
+
+	t.Run("пустая история — ошибка, а не падение", func(t *testing.T) {
+		_, err := SendSchedule(nil, DefaultRanks, nil)
+		assert.Error(t, err)
+	})
+}
@@ -4,7 +4,10 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"log"
+	"time"
 
+	"trackerbot/config"
 	"trackerbot/model"
 	"trackerbot/repository"
 )
@@ -12,28 +15,97 @@ import (
 type PushupService interface {
 	EnsureUser(ctx context.Context, userID int64, username string) error
 	AddPushups(ctx context.Context, userID int64, count int) (*model.AddPushupsViewModel, error)
+	AddPushupSet(ctx context.Context, userID int64, variant string, count int) (*model.AddPushupsViewModel, error)
+	ConfirmPushups(ctx context.Context, userID int64, variant string, count int) (*model.AddPushupsViewModel, error)
+	AddGTGSet(ctx context.Context, userID int64, count int) (*model.AddPushupsViewModel, error)
+	GetPushupVariants() []model.PushupVariant
+	GetRanks() []model.RankInfo
 	SetDailyNorm(ctx context.Context, userID int64, dailyNorm int) error
 	SetDateCompletionOfDailyNorm(ctx context.Context, userID int64) error
 	GetDailyNorm(ctx context.Context, userID int64) (int, error)
 	UpdateMaxReps(ctx context.Context, userID int64, count int) (*model.MaxRepsViewModel, error)
 	GetMaxRepsHistory(ctx context.Context, userID int64) ([]model.MaxRepsHistoryItem, error)
+	GetMaxRepsHistoryPage(ctx context.Context, userID int64, rangeCode string, page int) (*model.MaxRepsHistoryPage, error)
 	GetMaxRepsRecord(ctx context.Context, userID int64) (model.MaxRepsHistoryItem, error)
 	ResetDailyNorm(ctx context.Context, userID int64) error
 	GetFullStat(ctx context.Context, userID int64) (*model.FullStatViewModel, error)
 	GetUserMaxReps(ctx context.Context, userID int64) (int, error)
 	CheckNormCompletion(ctx context.Context) (bool, string)
-	BuildSchedule(ctx context.Context, userID int64, history []model.MaxRepsHistoryItem) (bytes.Buffer, error)
+	BuildSchedule(ctx context.Context, userID int64, history []model.MaxRepsHistoryItem, withNorm bool) (bytes.Buffer, error)
+	BuildVolumeChart(ctx context.Context, userID int64, days int) (bytes.Buffer, error)
+	BuildHeatmap(ctx context.Context, userID int64) (bytes.Buffer, error)
+	GetStatsCard(ctx context.Context, userID int64) (*model.StatsCard, error)
+	BuildStatsCard(ctx context.Context, userID int64) (*model.StatsCard, bytes.Buffer, error)
+	GetPendingFlags(ctx context.Context) ([]model.FlaggedEntry, error)
+	ReviewFlag(ctx context.Context, flagID int64, exclude bool) error
+	GetExercises(ctx context.Context) ([]model.Exercise, error)
+	GetExercise(ctx context.Context, code string) (model.Exercise, error)
+	AddExerciseReps(ctx context.Context, userID int64, code string, count int) (*model.AddPushupsViewModel, error)
+	UpdateExerciseMax(ctx context.Context, userID int64, code string, count int) (*model.MaxRepsViewModel, error)
+	SetExerciseNorm(ctx context.Context, userID int64, code string, dailyNorm int) error
+	GetExerciseHistory(ctx context.Context, userID int64, code string) ([]model.MaxRepsHistoryItem, error)
+	BuildExerciseSchedule(ctx context.Context, exercise model.Exercise, history []model.MaxRepsHistoryItem) (bytes.Buffer, error)
+	GetMaxTestReminders(ctx context.Context, interval time.Duration) ([]model.MaxTestReminder, error)
+	MarkMaxTestReminded(ctx context.Context, userID int64) error
+	GetWeeklyTargets(ctx context.Context, userID int64) (*model.WeeklyTargetSummary, error)
+	GetAchievements(ctx context.Context, userID int64) ([]model.AchievementStatus, error)
+	GetRankHistory(ctx context.Context, userID int64) ([]model.RankChange, error)
+	GetNormStrategies() []model.NormStrategyInfo
+	GetUserNormStrategy(ctx context.Context, userID int64) (model.NormStrategyInfo, error)
+	SetNormStrategy(ctx context.Context, userID int64, code string) (model.NormStrategyInfo, error)
+	GetAutoNorm(ctx context.Context, userID int64) (bool, error)
+	SetAutoNorm(ctx context.Context, userID int64, enabled bool) error
+	RunAutoNorm(ctx context.Context, cfg config.AutoNormConfig) ([]model.NormAdjustment, error)
+	GetWeeklyGoal(ctx context.Context, userID int64) (model.WeeklyGoal, error)
+	GetWeeklyProgress(ctx context.Context, userID int64) (*model.WeeklyProgress, error)
+	SetWeeklyGoal(ctx context.Context, userID int64, volume, days int) (model.WeeklyGoal, error)
+	DisableWeeklyGoal(ctx context.Context, userID int64) error
+	GetActiveRest(ctx context.Context, userID int64) (*model.RestPeriod, error)
+	StartRest(ctx context.Context, userID int64, kind string, days int) (model.RestPeriod, error)
+	EndRest(ctx context.Context, userID int64) error
+	GetDailyPlan(ctx context.Context, userID int64) (*model.DailyPlan, error)
+	GetPrograms() []model.Program
+	EnrollProgram(ctx context.Context, userID int64, code string) (*model.ProgramWorkout, error)
+	GetProgramWorkout(ctx context.Context, userID int64) (*model.ProgramWorkout, error)
+	CompleteProgramSession(ctx context.Context, userID int64, week, day int, completed bool) (*model.ProgramSessionResult, error)
+	LeaveProgram(ctx context.Context, userID int64) error
+	GetGTG(ctx context.Context, userID int64) (model.GTGPrompt, error)
+	EnableGTG(ctx context.Context, userID int64, window string, intervalMinutes, setSize int) (model.GTGPrompt, error)
+	DisableGTG(ctx context.Context, userID int64) error
+	GetDueGTGPrompts(ctx context.Context, now time.Time) ([]model.GTGPrompt, error)
+	MarkGTGPrompted(ctx context.Context, prompt model.GTGPrompt, now time.Time) error
+	StartWorkout(ctx context.Context, userID int64) (model.WorkoutSession, error)
+	FinishWorkout(ctx context.Context, userID int64) (*model.WorkoutSummary, error)
+	GetMaxTestReadiness(ctx context.Context, userID int64) (model.MaxTestReadiness, error)
+	CheckMaxTestResult(ctx context.Context, userID int64, count int) (model.MaxTestCheck, error)
+	RecordMaxTest(ctx context.Context, userID int64, count, rpe int) (*model.MaxRepsViewModel, error)
+	EditMaxRepsEntry(ctx context.Context, userID int64, recordID int64, count int) (*model.MaxRepsHistoryChange, error)
+	DeleteMaxRepsEntry(ctx context.Context, userID int64, recordID int64) (*model.MaxRepsHistoryChange, error)
+	RecalculateNorm(ctx context.Context, userID int64) (int, error)
+}
+
+// Config — настраиваемые справочники сервиса из config.yml.
+// Незаданные разделы заменяются значениями по умолчанию
+type Config struct {
+	Variants []config.VariantConfig
+	Ranks    []config.RankConfig
+	Norm     config.NormConfig
 }
 
 type pushupService struct {
 	repo     repository.PushupRepository
-
+	variants Variants
+	ranks    Ranks
+	norm     NormFormula
 }
 
 
-func NewPushupService(repo repository.PushupRepository,) PushupService {
+func NewPushupService(repo repository.PushupRepository, cfg Config) PushupService {
 	return &pushupService{
 		repo:     repo,
+		variants: NewVariants(cfg.Variants),
+		ranks:    NewRanks(cfg.Ranks),
+		norm:     NewNormFormula(cfg.Norm),
 	}
 }
 
@@ -42,11 +114,32 @@ func (s *pushupService) EnsureUser(ctx context.Context, userID int64, username s
 	return s.repo.EnsureUser(ctx, userID, username)
 }
 
+// AddPushups добавляет подход обычных отжиманий
 func (s *pushupService) AddPushups(
 	ctx context.Context,
 	userID int64,
 	count int,
 ) (*model.AddPushupsViewModel, error) {
+	return s.AddPushupSet(ctx, userID, model.VariantStandard, count)
+}
+
+// AddPushupSet проверяет правдоподобность подхода и сохраняет его.
+// Подход переводится в эквивалент обычных отжиманий по коэффициенту варианта —
+// именно эквивалент идёт в норму и рейтинг.
+// Если запись выглядит подозрительно, она не сохраняется, а во ViewModel
+// выставляется NeedsConfirmation — пользователь должен подтвердить её через ConfirmPushups.
+func (s *pushupService) AddPushupSet(
+	ctx context.Context,
+	userID int64,
+	variantCode string,
+	count int,
+) (*model.AddPushupsViewModel, error) {
+
+	variant, ok := s.variants.Find(variantCode)
+	if !ok {
+		return nil, fmt.Errorf("неизвестный вариант отжиманий: %s", variantCode)
+	}
+	equivalent := EquivalentPushups(count, variant.Coefficient)
 
 	// --- Получаем дневную норму ---
 	dailyNorm, err := s.repo.GetDailyNorm(ctx, userID)
@@ -54,9 +147,134 @@ func (s *pushupService) AddPushups(
 		return nil, err
 	}
 
+	// --- Проверяем правдоподобность ---
+	plausible, reason, totalToday, err := s.checkPlausibility(ctx, userID, equivalent, dailyNorm)
+	if err != nil {
+		return nil, err
+	}
+
+	if !plausible {
+		return &model.AddPushupsViewModel{
+			AddedCount:        count,
+			Total:             totalToday,
+			DailyNorm:         dailyNorm,
+			NeedsConfirmation: true,
+			Reason:            reason,
+			Variant:           &variant,
+			Equivalent:        equivalent,
+		}, nil
+	}
+
+	return s.savePushups(ctx, userID, variant, count, dailyNorm, false)
+}
+
+// ConfirmPushups сохраняет запись, подтверждённую пользователем после предупреждения.
+// Если запись всё ещё подозрительна, она отправляется на проверку админам.
+func (s *pushupService) ConfirmPushups(
+	ctx context.Context,
+	userID int64,
+	variantCode string,
+	count int,
+) (*model.AddPushupsViewModel, error) {
+	return s.saveCheckedPushups(ctx, userID, variantCode, count, false)
+}
+
+// AddGTGSet сохраняет микроподход из напоминания GTG. Размер подхода предложил бот,
+// поэтому подтверждение не спрашивается — подозрительная запись сразу уходит админам
+func (s *pushupService) AddGTGSet(ctx context.Context, userID int64, count int) (*model.AddPushupsViewModel, error) {
+	return s.saveCheckedPushups(ctx, userID, model.VariantStandard, count, true)
+}
+
+// saveCheckedPushups сохраняет запись без подтверждения, отправляя подозрительную на проверку
+func (s *pushupService) saveCheckedPushups(
+	ctx context.Context,
+	userID int64,
+	variantCode string,
+	count int,
+	gtg bool,
+) (*model.AddPushupsViewModel, error) {
+
+	variant, ok := s.variants.Find(variantCode)
+	if !ok {
+		return nil, fmt.Errorf("неизвестный вариант отжиманий: %s", variantCode)
+	}
+	equivalent := EquivalentPushups(count, variant.Coefficient)
+
+	dailyNorm, err := s.repo.GetDailyNorm(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	plausible, reason, _, err := s.checkPlausibility(ctx, userID, equivalent, dailyNorm)
+	if err != nil {
+		return nil, err
+	}
+
+	vm, err := s.savePushups(ctx, userID, variant, count, dailyNorm, gtg)
+	if err != nil {
+		return nil, err
+	}
+
+	if !plausible {
+		if err := s.repo.AddFlaggedPushups(ctx, userID, equivalent, reason); err != nil {
+			return nil, fmt.Errorf("ошибка сохранения подозрительной записи: %w", err)
+		}
+	}
+
+	return vm, nil
+}
+
+// GetPushupVariants возвращает каталог вариантов отжиманий
+func (s *pushupService) GetPushupVariants() []model.PushupVariant {
+	return s.variants
+}
+
+// GetRanks возвращает лестницу рангов
+func (s *pushupService) GetRanks() []model.RankInfo {
+	return s.ranks
+}
+
+// checkPlausibility собирает данные пользователя и проверяет запись через CheckPlausibility
+func (s *pushupService) checkPlausibility(
+	ctx context.Context,
+	userID int64,
+	count int,
+	dailyNorm int,
+) (bool, string, int, error) {
+
+	totalToday, err := s.repo.GetTodayStat(ctx, userID)
+	if err != nil {
+		return false, "", 0, err
+	}
+
+	maxReps, err := s.repo.GetUserMaxReps(ctx, userID)
+	if err != nil {
+		return false, "", 0, err
+	}
+
+	// Учитываем рекорд из истории — текущий max_reps мог быть понижен новым тестом
+	if record, err := s.repo.GetMaxRepsRecord(ctx, userID); err == nil && record.MaxReps > maxReps {
+		maxReps = record.MaxReps
+	}
+
+	plausible, reason := CheckPlausibility(count, totalToday, maxReps, dailyNorm)
+
+	return plausible, reason, totalToday, nil
+}
+
+func (s *pushupService) savePushups(
+	ctx context.Context,
+	userID int64,
+	variant model.PushupVariant,
+	count int,
+	dailyNorm int,
+	gtg bool,
+) (*model.AddPushupsViewModel, error) {
+
 	// --- Добавляем отжимания ---
+	equivalent := EquivalentPushups(count, variant.Coefficient)
 
-	totalToday, err := s.repo.AddPushups(ctx, userID, count)
+	totalToday, err := s.repo.AddPushupSet(ctx, userID, variant.Code, count, equivalent, gtg)
 	if err != nil {
 		return nil, fmt.Errorf("ошибка сохранения в БД: %w", err)
 	}
@@ -67,6 +285,7 @@ func (s *pushupService) AddPushups(
 	normJustCompleted := totalToday >= dailyNorm
 	if normJustCompleted {
 		_ = s.repo.SetDateCompletionOfDailyNorm(ctx, userID)
+		_ = s.repo.AddNormCompletion(ctx, userID, dailyNorm)
 	}
 
 	// --- Формируем ViewModel ---
@@ -77,13 +296,42 @@ func (s *pushupService) AddPushups(
 		Completed:  normJustCompleted,
 		HasLeader:  hasCompleted,
 		Leader:     firstCompleter,
+		Variant:    &variant,
+		Equivalent: equivalent,
+
+		NewAchievements: s.unlockAchievements(ctx, userID),
+	}
+
+	// --- Прогресс недельной цели ---
+	weekly, err := s.weeklyProgress(ctx, userID)
+	if err != nil {
+		log.Printf("Ошибка расчёта недельной цели: %v", err)
+	}
+	if weekly != nil {
+		// До этого подхода: минус его объём и, если он первый за сегодня, минус тренировочный день
+		daysBefore := weekly.TrainingDays
+		if totalToday == equivalent {
+			daysBefore--
+		}
+		goal := model.WeeklyGoal{Mode: model.GoalModeWeekly, Volume: weekly.Volume, Days: weekly.TargetDays}
+		weekly.JustCompleted = weekly.Completed && !IsWeekCompleted(weekly.Total-equivalent, daysBefore, goal)
+		vm.Weekly = weekly
+	}
+
+	// --- Оставшийся план подходов на сегодня ---
+	if vm.Weekly == nil && !normJustCompleted && dailyNorm > 0 {
+		plan, err := s.dailyPlan(ctx, userID, dailyNorm, totalToday)
+		if err != nil {
+			log.Printf("Ошибка построения плана подходов: %v", err)
+		}
+		vm.Plan = plan
 	}
 
 	return vm, nil
 }
 
 func (s *pushupService) SetDailyNorm(ctx context.Context, userID int64, dailyNorm int) error {
-	return s.repo.SetDailyNorm(ctx, userID, dailyNorm)
+	return s.repo.SetDailyNorm(ctx, userID, dailyNorm, model.NormSourceManual)
 }
 
 func (s *pushupService) SetDateCompletionOfDailyNorm(ctx context.Context, userID int64) error {
@@ -100,7 +348,21 @@ func (s *pushupService) UpdateMaxReps(
 	count int,
 ) (*model.MaxRepsViewModel, error) {
 
-	// 1. Сохраняем max reps и историю
+	// 1. Собираем данные для стратегии нормы до сохранения нового результата
+	strategy, err := s.userNormStrategy(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	normInput, err := s.collectNormInput(ctx, userID, count)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Сохраняем max reps и историю
+	prevMaxReps, err := s.repo.GetUserMaxReps(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
 	if err := s.repo.SetMaxReps(ctx, userID, count); err != nil {
 		return nil, err
 	}
@@ -108,13 +370,13 @@ func (s *pushupService) UpdateMaxReps(
 		return nil, fmt.Errorf("ошибка сохранения в историю: %w", err)
 	}
 
-	// 2. Рассчитываем дневную норму
-	dailyNorm := CalculateDailyNorm(count)
-	if err := s.repo.SetDailyNorm(ctx, userID, dailyNorm); err != nil {
+	// 3. Рассчитываем дневную норму по выбранной стратегии
+	dailyNorm := strategy.Calculate(normInput)
+	if err := s.repo.SetDailyNorm(ctx, userID, dailyNorm, model.NormSourceMaxTest); err != nil {
 		return nil, err
 	}
 
-	// 3. Получаем историю и рекорд
+	// 4. Получаем историю и рекорд
 	history, err := s.repo.GetMaxRepsHistory(ctx, userID)
 	if err != nil {
 		return nil, err
@@ -124,14 +386,33 @@ func (s *pushupService) UpdateMaxReps(
 		return nil, err
 	}
 
-	// 4. Формируем ViewModel
+	// 5. Отмечаем недельные цели и ставим цель на следующую неделю
+	hitTarget, nextTarget, streak, err := s.updateWeeklyTargets(ctx, userID, count, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("ошибка обновления недельной цели: %w", err)
+	}
+
+	// 6. Фиксируем смену ранга
+	rankChange, err := s.recordRankChange(ctx, userID, prevMaxReps, count)
+	if err != nil {
+		return nil, err
+	}
+
+	// 7. Формируем ViewModel
 	vm := &model.MaxRepsViewModel{
-		Count:      count,
-		DailyNorm:  dailyNorm,
-		History:    history,
-		Record:     &record,
-		Rank:       GetUserRank(count),
-		RepsToNext: GetRepsToNextRank(count),
+		Count:        count,
+		DailyNorm:    dailyNorm,
+		History:      history,
+		Record:       &record,
+		Rank:         s.ranks.Title(count),
+		RepsToNext:   s.ranks.RepsToNext(count),
+		NextTarget:   &nextTarget,
+		HitTarget:    hitTarget,
+		TargetStreak: streak,
+
+		NewAchievements: s.unlockAchievements(ctx, userID),
+		RankChange:      rankChange,
+		NormStrategy:    strategy.Info().Name,
 	}
 
 	return vm, nil
@@ -175,6 +456,26 @@ func (s *pushupService) GetFullStat(
 		})
 	}
 
+	// --- Суммы по вариантам ---
+	variantTotals, err := s.repo.GetVariantTotals(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range variantTotals {
+		item.Name = item.Code
+		if variant, ok := s.variants.Find(item.Code); ok {
+			item.Name = variant.Name
+		}
+		vm.VariantTotals = append(vm.VariantTotals, item)
+	}
+
+	// --- Недельная цель ---
+	vm.Weekly, err = s.weeklyProgress(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
 	return vm, nil
 }
 
@@ -200,16 +501,42 @@ func (s *pushupService) CheckNormCompletion(ctx context.Context) (bool, string)
 	return true, username
 }
 
-func (s *pushupService) BuildSchedule(ctx context.Context,
+// GetPendingFlags возвращает подозрительные записи, ожидающие проверки админом
+func (s *pushupService) GetPendingFlags(ctx context.Context) ([]model.FlaggedEntry, error) {
+	return s.repo.GetFlaggedPushups(ctx, model.FlagStatusPending)
+}
+
+// ReviewFlag фиксирует решение админа: exclude=true исключает запись из рейтинга,
+// снимает выполнение нормы, которое держалось на ней, и закрытые ей достижения
+func (s *pushupService) ReviewFlag(ctx context.Context, flagID int64, exclude bool) error {
+	if !exclude {
+		return s.repo.SetFlagStatus(ctx, flagID, model.FlagStatusApproved)
+	}
+
+	userID, err := s.repo.ExcludeFlaggedPushups(ctx, flagID)
+	if err != nil {
+		return err
+	}
+	return s.revokeAchievements(ctx, userID)
+}
+
+// BuildSchedule строит график теста максимума по датам.
+// withNorm накладывает на график историю дневной нормы
+func (s *pushupService) BuildSchedule(
+	ctx context.Context,
 	userID int64,
 	history []model.MaxRepsHistoryItem,
+	withNorm bool,
 ) (bytes.Buffer, error) {
 
-	imageBytes, err := SendSchedule(userID, history)
-	if err != nil {
-		return bytes.Buffer{}, nil
+	if !withNorm || len(history) == 0 {
+		return SendSchedule(history, s.ranks, nil)
 	}
 
-	return imageBytes, nil
+	norms, err := s.normSeries(ctx, userID, history[len(history)-1].Date, time.Now())
+	if err != nil {
+		return bytes.Buffer{}, err
+	}
+	return SendSchedule(history, s.ranks, norms)
 }
 
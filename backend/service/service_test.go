@@ -36,6 +36,19 @@ func (m *MockPushupRepository) AddPushups(ctx context.Context, userID int64, cou
 	return args.Int(0), args.Error(1)
 }
 
+func (m *MockPushupRepository) AddPushupSet(ctx context.Context, userID int64, variant string, count int, equivalent int, gtg bool) (int, error) {
+	args := m.Called(ctx, userID, variant, count, equivalent, gtg)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockPushupRepository) GetVariantTotals(ctx context.Context, userID int64) ([]model.VariantTotal, error) {
+	args := m.Called(ctx, userID)
+	if totals, ok := args.Get(0).([]model.VariantTotal); ok {
+		return totals, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
 func (m *MockPushupRepository) GetFullStat(ctx context.Context, userID int64) (*model.FullStatViewModel, error) {
 	args := m.Called(ctx, userID)
 	if data, ok := args.Get(0).(*model.FullStatViewModel); ok {
@@ -74,8 +87,8 @@ func (m *MockPushupRepository) ResetDailyNorm(ctx context.Context, userID int64)
 	return args.Error(0)
 }
 
-func (m *MockPushupRepository) SetDailyNorm(ctx context.Context, userID int64, dailyNorm int) error {
-	args := m.Called(ctx, userID, dailyNorm)
+func (m *MockPushupRepository) SetDailyNorm(ctx context.Context, userID int64, dailyNorm int, source string) error {
+	args := m.Called(ctx, userID, dailyNorm, source)
 	return args.Error(0)
 }
 
@@ -119,6 +132,371 @@ func (m *MockPushupRepository) GetMaxRepsRecord(ctx context.Context, userID int6
 	return model.MaxRepsHistoryItem{}, args.Error(1)
 }
 
+func (m *MockPushupRepository) AddFlaggedPushups(ctx context.Context, userID int64, count int, reason string) error {
+	args := m.Called(ctx, userID, count, reason)
+	return args.Error(0)
+}
+
+func (m *MockPushupRepository) GetFlaggedPushups(ctx context.Context, status string) ([]model.FlaggedEntry, error) {
+	args := m.Called(ctx, status)
+	if entries, ok := args.Get(0).([]model.FlaggedEntry); ok {
+		return entries, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPushupRepository) SetFlagStatus(ctx context.Context, flagID int64, status string) error {
+	args := m.Called(ctx, flagID, status)
+	return args.Error(0)
+}
+
+func (m *MockPushupRepository) ExcludeFlaggedPushups(ctx context.Context, flagID int64) (int64, error) {
+	args := m.Called(ctx, flagID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockPushupRepository) GetExercises(ctx context.Context) ([]model.Exercise, error) {
+	args := m.Called(ctx)
+	if exercises, ok := args.Get(0).([]model.Exercise); ok {
+		return exercises, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPushupRepository) GetExercise(ctx context.Context, code string) (model.Exercise, error) {
+	args := m.Called(ctx, code)
+	if exercise, ok := args.Get(0).(model.Exercise); ok {
+		return exercise, args.Error(1)
+	}
+	return model.Exercise{}, args.Error(1)
+}
+
+func (m *MockPushupRepository) AddExerciseReps(ctx context.Context, userID int64, code string, count int) (int, error) {
+	args := m.Called(ctx, userID, code, count)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockPushupRepository) GetUserExercise(ctx context.Context, userID int64, code string) (model.UserExercise, error) {
+	args := m.Called(ctx, userID, code)
+	if userExercise, ok := args.Get(0).(model.UserExercise); ok {
+		return userExercise, args.Error(1)
+	}
+	return model.UserExercise{}, args.Error(1)
+}
+
+func (m *MockPushupRepository) SetExerciseMax(ctx context.Context, userID int64, code string, maxReps int, dailyNorm int) error {
+	args := m.Called(ctx, userID, code, maxReps, dailyNorm)
+	return args.Error(0)
+}
+
+func (m *MockPushupRepository) SetExerciseNorm(ctx context.Context, userID int64, code string, dailyNorm int) error {
+	args := m.Called(ctx, userID, code, dailyNorm)
+	return args.Error(0)
+}
+
+func (m *MockPushupRepository) GetExerciseHistory(ctx context.Context, userID int64, code string) ([]model.MaxRepsHistoryItem, error) {
+	args := m.Called(ctx, userID, code)
+	if history, ok := args.Get(0).([]model.MaxRepsHistoryItem); ok {
+		return history, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPushupRepository) GetExerciseRecord(ctx context.Context, userID int64, code string) (model.MaxRepsHistoryItem, error) {
+	args := m.Called(ctx, userID, code)
+	if record, ok := args.Get(0).(model.MaxRepsHistoryItem); ok {
+		return record, args.Error(1)
+	}
+	return model.MaxRepsHistoryItem{}, args.Error(1)
+}
+
+func (m *MockPushupRepository) GetDailyTotals(ctx context.Context, userID int64, from time.Time) ([]model.DailyTotal, error) {
+	args := m.Called(ctx, userID, from)
+	if totals, ok := args.Get(0).([]model.DailyTotal); ok {
+		return totals, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPushupRepository) GetLastMaxRepsUpdate(ctx context.Context, userID int64) (time.Time, error) {
+	args := m.Called(ctx, userID)
+	if date, ok := args.Get(0).(time.Time); ok {
+		return date, args.Error(1)
+	}
+	return time.Time{}, args.Error(1)
+}
+
+func (m *MockPushupRepository) GetMaxTestReminderCandidates(ctx context.Context, testedBefore time.Time) ([]model.MaxTestReminder, error) {
+	args := m.Called(ctx, testedBefore)
+	if candidates, ok := args.Get(0).([]model.MaxTestReminder); ok {
+		return candidates, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPushupRepository) MarkMaxTestReminded(ctx context.Context, userID int64) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (m *MockPushupRepository) GetWeeklyTargets(ctx context.Context, userID int64) ([]model.WeeklyTarget, error) {
+	args := m.Called(ctx, userID)
+	if targets, ok := args.Get(0).([]model.WeeklyTarget); ok {
+		return targets, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPushupRepository) SetWeeklyTarget(ctx context.Context, userID int64, weekStart time.Time, baseMax int, targetMax int) error {
+	args := m.Called(ctx, userID, weekStart, baseMax, targetMax)
+	return args.Error(0)
+}
+
+func (m *MockPushupRepository) UpdateWeeklyTarget(ctx context.Context, targetID int64, status string, achievedMax int) error {
+	args := m.Called(ctx, targetID, status, achievedMax)
+	return args.Error(0)
+}
+
+func (m *MockPushupRepository) AddNormCompletion(ctx context.Context, userID int64, dailyNorm int) error {
+	args := m.Called(ctx, userID, dailyNorm)
+	return args.Error(0)
+}
+
+func (m *MockPushupRepository) GetNormCompletionDates(ctx context.Context, userID int64) ([]time.Time, error) {
+	args := m.Called(ctx, userID)
+	if dates, ok := args.Get(0).([]time.Time); ok {
+		return dates, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPushupRepository) GetAchievementStats(ctx context.Context, userID int64) (model.AchievementStats, error) {
+	args := m.Called(ctx, userID)
+	if stats, ok := args.Get(0).(model.AchievementStats); ok {
+		return stats, args.Error(1)
+	}
+	return model.AchievementStats{}, args.Error(1)
+}
+
+func (m *MockPushupRepository) GetUserAchievements(ctx context.Context, userID int64) ([]model.UserAchievement, error) {
+	args := m.Called(ctx, userID)
+	if achievements, ok := args.Get(0).([]model.UserAchievement); ok {
+		return achievements, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPushupRepository) UnlockAchievement(ctx context.Context, userID int64, code string) (bool, error) {
+	args := m.Called(ctx, userID, code)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockPushupRepository) RevokeAchievement(ctx context.Context, userID int64, code string) error {
+	args := m.Called(ctx, userID, code)
+	return args.Error(0)
+}
+
+func (m *MockPushupRepository) AddRankChange(ctx context.Context, userID int64, change model.RankChange) error {
+	args := m.Called(ctx, userID, change)
+	return args.Error(0)
+}
+
+func (m *MockPushupRepository) GetRankHistory(ctx context.Context, userID int64) ([]model.RankChange, error) {
+	args := m.Called(ctx, userID)
+	if history, ok := args.Get(0).([]model.RankChange); ok {
+		return history, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPushupRepository) GetNormStrategy(ctx context.Context, userID int64) (string, error) {
+	args := m.Called(ctx, userID)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockPushupRepository) SetNormStrategy(ctx context.Context, userID int64, code string) error {
+	args := m.Called(ctx, userID, code)
+	return args.Error(0)
+}
+
+func (m *MockPushupRepository) GetAutoNorm(ctx context.Context, userID int64) (bool, error) {
+	args := m.Called(ctx, userID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockPushupRepository) SetAutoNorm(ctx context.Context, userID int64, enabled bool) error {
+	args := m.Called(ctx, userID, enabled)
+	return args.Error(0)
+}
+
+func (m *MockPushupRepository) GetAutoNormCandidates(ctx context.Context) ([]model.AutoNormCandidate, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]model.AutoNormCandidate), args.Error(1)
+}
+
+func (m *MockPushupRepository) ApplyNormAdjustment(ctx context.Context, adjustment model.NormAdjustment, source string) error {
+	args := m.Called(ctx, adjustment, source)
+	return args.Error(0)
+}
+
+func (m *MockPushupRepository) MarkAutoNormChecked(ctx context.Context, userID int64) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (m *MockPushupRepository) GetWeeklyGoal(ctx context.Context, userID int64) (model.WeeklyGoal, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(model.WeeklyGoal), args.Error(1)
+}
+
+func (m *MockPushupRepository) SetWeeklyGoal(ctx context.Context, userID int64, goal model.WeeklyGoal) error {
+	args := m.Called(ctx, userID, goal)
+	return args.Error(0)
+}
+
+func (m *MockPushupRepository) AddRestPeriod(ctx context.Context, userID int64, period model.RestPeriod) error {
+	args := m.Called(ctx, userID, period)
+	return args.Error(0)
+}
+
+func (m *MockPushupRepository) GetRestPeriods(ctx context.Context, userID int64) ([]model.RestPeriod, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]model.RestPeriod), args.Error(1)
+}
+
+func (m *MockPushupRepository) EndRestPeriods(ctx context.Context, userID int64) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (m *MockPushupRepository) GetProgramEnrollment(ctx context.Context, userID int64) (*model.ProgramEnrollment, error) {
+	args := m.Called(ctx, userID)
+	enrollment, _ := args.Get(0).(*model.ProgramEnrollment)
+	return enrollment, args.Error(1)
+}
+
+func (m *MockPushupRepository) SaveProgramEnrollment(ctx context.Context, userID int64, enrollment model.ProgramEnrollment) error {
+	args := m.Called(ctx, userID, enrollment)
+	return args.Error(0)
+}
+
+func (m *MockPushupRepository) RecordProgramSession(ctx context.Context, userID int64, session model.ProgramSession, next *model.ProgramEnrollment) error {
+	args := m.Called(ctx, userID, session, next)
+	return args.Error(0)
+}
+
+func (m *MockPushupRepository) DeleteProgramEnrollment(ctx context.Context, userID int64) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (m *MockPushupRepository) GetGTGSettings(ctx context.Context, userID int64) (model.GTGSettings, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(model.GTGSettings), args.Error(1)
+}
+
+func (m *MockPushupRepository) SaveGTGSettings(ctx context.Context, userID int64, settings model.GTGSettings) error {
+	args := m.Called(ctx, userID, settings)
+	return args.Error(0)
+}
+
+func (m *MockPushupRepository) GetDueGTGPrompts(ctx context.Context, now time.Time) ([]model.GTGPrompt, error) {
+	args := m.Called(ctx, now)
+	prompts, _ := args.Get(0).([]model.GTGPrompt)
+	return prompts, args.Error(1)
+}
+
+func (m *MockPushupRepository) SetGTGNextPrompt(ctx context.Context, userID int64, next time.Time) error {
+	args := m.Called(ctx, userID, next)
+	return args.Error(0)
+}
+
+func (m *MockPushupRepository) GetOpenWorkoutSession(ctx context.Context, userID int64) (*model.WorkoutSession, error) {
+	args := m.Called(ctx, userID)
+	session, _ := args.Get(0).(*model.WorkoutSession)
+	return session, args.Error(1)
+}
+
+func (m *MockPushupRepository) StartWorkoutSession(ctx context.Context, userID int64) (*model.WorkoutSession, error) {
+	args := m.Called(ctx, userID)
+	session, _ := args.Get(0).(*model.WorkoutSession)
+	return session, args.Error(1)
+}
+
+func (m *MockPushupRepository) GetWorkoutSessionSets(ctx context.Context, sessionID int64) ([]int, error) {
+	args := m.Called(ctx, sessionID)
+	sets, _ := args.Get(0).([]int)
+	return sets, args.Error(1)
+}
+
+func (m *MockPushupRepository) FinishWorkoutSession(ctx context.Context, session model.WorkoutSession) error {
+	args := m.Called(ctx, session)
+	return args.Error(0)
+}
+
+func (m *MockPushupRepository) GetLastWorkoutSession(ctx context.Context, userID int64) (*model.WorkoutSession, error) {
+	args := m.Called(ctx, userID)
+	session, _ := args.Get(0).(*model.WorkoutSession)
+	return session, args.Error(1)
+}
+
+func (m *MockPushupRepository) SetMaxTestRPE(ctx context.Context, userID int64, rpe int) error {
+	args := m.Called(ctx, userID, rpe)
+	return args.Error(0)
+}
+
+func (m *MockPushupRepository) UpdateMaxRepsEntry(ctx context.Context, userID int64, recordID int64, maxReps int) error {
+	args := m.Called(ctx, userID, recordID, maxReps)
+	return args.Error(0)
+}
+
+func (m *MockPushupRepository) DeleteMaxRepsEntry(ctx context.Context, userID int64, recordID int64) error {
+	args := m.Called(ctx, userID, recordID)
+	return args.Error(0)
+}
+
+func (m *MockPushupRepository) RestoreMaxReps(ctx context.Context, userID int64, maxReps int, testedAt *time.Time) error {
+	args := m.Called(ctx, userID, maxReps, testedAt)
+	return args.Error(0)
+}
+
+func (m *MockPushupRepository) GetNormHistory(ctx context.Context, userID int64) ([]model.NormChange, error) {
+	args := m.Called(ctx, userID)
+	changes, _ := args.Get(0).([]model.NormChange)
+	return changes, args.Error(1)
+}
+
+func (m *MockPushupRepository) GetTodaySets(ctx context.Context, userID int64) ([]model.LoggedSet, error) {
+	args := m.Called(ctx, userID)
+	if sets, ok := args.Get(0).([]model.LoggedSet); ok {
+		return sets, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+// expectNormInput настраивает мок для сбора данных стратегии нормы перед тестом максимума
+func expectNormInput(m *MockPushupRepository, userID int64, strategy string, currentNorm int) {
+	m.On("GetNormStrategy", mock.Anything, userID).Return(strategy, nil).Once()
+	m.On("GetDailyNorm", mock.Anything, userID).Return(currentNorm, nil).Once()
+	m.On("GetLastMaxRepsUpdate", mock.Anything, userID).Return(time.Now().AddDate(0, 0, -7), nil).Once()
+	m.On("GetDailyTotals", mock.Anything, userID, mock.Anything).Return(nil, nil).Once()
+	m.On("GetRestPeriods", mock.Anything, userID).Return([]model.RestPeriod(nil), nil).Maybe()
+}
+
+// expectNoAchievements настраивает мок так, что проверка достижений ничего не открывает
+func expectNoAchievements(m *MockPushupRepository, userID int64) {
+	m.On("GetAchievementStats", mock.Anything, userID).Return(model.AchievementStats{}, nil).Maybe()
+	m.On("GetNormCompletionDates", mock.Anything, userID).Return(nil, nil).Maybe()
+	m.On("GetUserAchievements", mock.Anything, userID).Return(nil, nil).Maybe()
+	m.On("GetRestPeriods", mock.Anything, userID).Return([]model.RestPeriod(nil), nil).Maybe()
+	expectDailyGoal(m, userID)
+}
+
+// expectDailyGoal настраивает мок так, что пользователь остаётся в режиме дневной нормы
+func expectDailyGoal(m *MockPushupRepository, userID int64) {
+	m.On("GetWeeklyGoal", mock.Anything, userID).Return(model.WeeklyGoal{Mode: model.GoalModeDaily}, nil).Maybe()
+}
+
 func TestService_EnsureUser(t *testing.T) {
 	mockRepo := new(MockPushupRepository)
 
@@ -127,7 +505,7 @@ func TestService_EnsureUser(t *testing.T) {
 		Return(nil).
 		Once()
 
-	service := NewPushupService(mockRepo)
+	service := NewPushupService(mockRepo, Config{})
 
 	err := service.EnsureUser(context.Background(), 1, "john")
 
@@ -241,11 +619,11 @@ func TestMock_SetDailyNorm(t *testing.T) {
 	mockRepo := new(MockPushupRepository)
 
 	mockRepo.
-		On("SetDailyNorm", mock.Anything, int64(5), 120).
+		On("SetDailyNorm", mock.Anything, int64(5), 120, model.NormSourceManual).
 		Return(nil).
 		Once()
 
-	err := mockRepo.SetDailyNorm(context.Background(), 5, 120)
+	err := mockRepo.SetDailyNorm(context.Background(), 5, 120, model.NormSourceManual)
 
 	assert.NoError(t, err)
 	mockRepo.AssertExpectations(t)
@@ -352,3 +730,323 @@ func TestMock_GetMaxRepsRecord(t *testing.T) {
 	assert.Equal(t, 100, result.MaxReps)
 	mockRepo.AssertExpectations(t)
 }
+
+func TestService_AddPushups_NeedsConfirmation(t *testing.T) {
+	mockRepo := new(MockPushupRepository)
+
+	mockRepo.On("GetDailyNorm", mock.Anything, int64(1)).Return(100, nil).Once()
+	mockRepo.On("GetTodayStat", mock.Anything, int64(1)).Return(0, nil).Once()
+	mockRepo.On("GetUserMaxReps", mock.Anything, int64(1)).Return(20, nil).Once()
+	mockRepo.On("GetMaxRepsRecord", mock.Anything, int64(1)).
+		Return(model.MaxRepsHistoryItem{MaxReps: 20}, nil).Once()
+
+	service := NewPushupService(mockRepo, Config{})
+
+	vm, err := service.AddPushups(context.Background(), 1, 90)
+
+	assert.NoError(t, err)
+	assert.True(t, vm.NeedsConfirmation)
+	assert.NotEmpty(t, vm.Reason)
+	mockRepo.AssertNotCalled(t, "AddPushupSet", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestService_ConfirmPushups_FlagsEntry(t *testing.T) {
+	mockRepo := new(MockPushupRepository)
+
+	mockRepo.On("GetDailyNorm", mock.Anything, int64(1)).Return(100, nil).Once()
+	mockRepo.On("GetTodayStat", mock.Anything, int64(1)).Return(0, nil).Once()
+	// Второй вызов — для плана подходов после сохранения
+	mockRepo.On("GetUserMaxReps", mock.Anything, int64(1)).Return(20, nil).Twice()
+	mockRepo.On("GetMaxRepsRecord", mock.Anything, int64(1)).
+		Return(model.MaxRepsHistoryItem{MaxReps: 20}, nil).Once()
+	mockRepo.On("AddPushupSet", mock.Anything, int64(1), model.VariantStandard, 90, 90, false).Return(90, nil).Once()
+	mockRepo.On("GetTodaySets", mock.Anything, int64(1)).
+		Return([]model.LoggedSet{{Variant: model.VariantStandard, Count: 90}}, nil).Once()
+	mockRepo.On("GetFirstNormCompleter", mock.Anything).Return(int64(0), nil).Once()
+	mockRepo.On("AddFlaggedPushups", mock.Anything, int64(1), 90, mock.AnythingOfType("string")).
+		Return(nil).Once()
+	expectNoAchievements(mockRepo, 1)
+
+	service := NewPushupService(mockRepo, Config{})
+
+	vm, err := service.ConfirmPushups(context.Background(), 1, model.VariantStandard, 90)
+
+	assert.NoError(t, err)
+	assert.False(t, vm.NeedsConfirmation)
+	assert.Equal(t, 90, vm.Total)
+	if assert.NotNil(t, vm.Plan) {
+		assert.Equal(t, []int{10}, vm.Plan.Remaining)
+		assert.Equal(t, 1, vm.Plan.DoneSets())
+	}
+	mockRepo.AssertExpectations(t)
+}
+
+func TestService_ReviewFlag(t *testing.T) {
+	mockRepo := new(MockPushupRepository)
+	day := func(offset int) time.Time { return time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC).AddDate(0, 0, -offset) }
+
+	// После исключения: объём упал ниже тысячи, первым за день пользователь больше не был,
+	// а самая длинная серия — 3 дня
+	mockRepo.On("ExcludeFlaggedPushups", mock.Anything, int64(7)).Return(int64(1), nil).Once()
+	mockRepo.On("GetAchievementStats", mock.Anything, int64(1)).
+		Return(model.AchievementStats{TotalPushups: 900, FirstCompletions: 0, MaxReps: 50}, nil).Once()
+	mockRepo.On("GetNormCompletionDates", mock.Anything, int64(1)).
+		Return([]time.Time{day(0), day(1), day(2), day(4)}, nil).Once()
+	mockRepo.On("GetRestPeriods", mock.Anything, int64(1)).Return([]model.RestPeriod(nil), nil).Once()
+	mockRepo.On("GetUserAchievements", mock.Anything, int64(1)).Return([]model.UserAchievement{
+		{Code: "first_set"}, {Code: "total_1000"}, {Code: "streak_7"}, {Code: "first_finisher"}, {Code: "max_50"},
+	}, nil).Once()
+	mockRepo.On("RevokeAchievement", mock.Anything, int64(1), "total_1000").Return(nil).Once()
+	mockRepo.On("RevokeAchievement", mock.Anything, int64(1), "streak_7").Return(nil).Once()
+	mockRepo.On("RevokeAchievement", mock.Anything, int64(1), "first_finisher").Return(nil).Once()
+	mockRepo.On("SetFlagStatus", mock.Anything, int64(8), model.FlagStatusApproved).Return(nil).Once()
+
+	service := NewPushupService(mockRepo, Config{})
+
+	assert.NoError(t, service.ReviewFlag(context.Background(), 7, true))
+	assert.NoError(t, service.ReviewFlag(context.Background(), 8, false))
+	mockRepo.AssertExpectations(t)
+}
+
+func TestService_AddExerciseReps(t *testing.T) {
+	mockRepo := new(MockPushupRepository)
+
+	squats := model.Exercise{Code: "squats", Name: "Приседания", Unit: model.UnitReps, NormFactor: 3}
+
+	mockRepo.On("GetUserExercise", mock.Anything, int64(1), "squats").
+		Return(model.UserExercise{Exercise: squats, MaxReps: 30, DailyNorm: 90}, nil).Once()
+	mockRepo.On("AddExerciseReps", mock.Anything, int64(1), "squats", 40).Return(100, nil).Once()
+
+	service := NewPushupService(mockRepo, Config{})
+
+	vm, err := service.AddExerciseReps(context.Background(), 1, "squats", 40)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 100, vm.Total)
+	assert.True(t, vm.Completed)
+	assert.Equal(t, "squats", vm.Exercise.Code)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestService_UpdateExerciseMax(t *testing.T) {
+	mockRepo := new(MockPushupRepository)
+
+	plank := model.Exercise{Code: "plank", Name: "Планка", Unit: model.UnitSeconds, NormFactor: 3}
+	history := []model.MaxRepsHistoryItem{{MaxReps: 60}}
+
+	mockRepo.On("GetExercise", mock.Anything, "plank").Return(plank, nil).Once()
+	mockRepo.On("SetExerciseMax", mock.Anything, int64(1), "plank", 60, 180).Return(nil).Once()
+	mockRepo.On("GetExerciseHistory", mock.Anything, int64(1), "plank").Return(history, nil).Once()
+	mockRepo.On("GetExerciseRecord", mock.Anything, int64(1), "plank").
+		Return(model.MaxRepsHistoryItem{MaxReps: 60}, nil).Once()
+
+	service := NewPushupService(mockRepo, Config{})
+
+	vm, err := service.UpdateExerciseMax(context.Background(), 1, "plank", 60)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 180, vm.DailyNorm)
+	assert.Empty(t, vm.Rank)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestService_AddPushupSet_Variant(t *testing.T) {
+	mockRepo := new(MockPushupRepository)
+
+	mockRepo.On("GetDailyNorm", mock.Anything, int64(1)).Return(100, nil).Once()
+	mockRepo.On("GetTodayStat", mock.Anything, int64(1)).Return(0, nil).Once()
+	// Второй вызов — для плана подходов после сохранения
+	mockRepo.On("GetUserMaxReps", mock.Anything, int64(1)).Return(30, nil).Twice()
+	mockRepo.On("GetMaxRepsRecord", mock.Anything, int64(1)).
+		Return(model.MaxRepsHistoryItem{MaxReps: 30}, nil).Once()
+	// 20 алмазных = 30 обычных
+	mockRepo.On("AddPushupSet", mock.Anything, int64(1), "diamond", 20, 30, false).Return(30, nil).Once()
+	mockRepo.On("GetTodaySets", mock.Anything, int64(1)).
+		Return([]model.LoggedSet{{Variant: "diamond", Count: 20}}, nil).Once()
+	mockRepo.On("GetFirstNormCompleter", mock.Anything).Return(int64(0), nil).Once()
+	expectNoAchievements(mockRepo, 1)
+
+	service := NewPushupService(mockRepo, Config{})
+
+	vm, err := service.AddPushupSet(context.Background(), 1, "diamond", 20)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 20, vm.AddedCount)
+	assert.Equal(t, 30, vm.Equivalent)
+	assert.Equal(t, "diamond", vm.Variant.Code)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestService_AddPushupSet_UnknownVariant(t *testing.T) {
+	mockRepo := new(MockPushupRepository)
+	service := NewPushupService(mockRepo, Config{})
+
+	_, err := service.AddPushupSet(context.Background(), 1, "unknown", 20)
+
+	assert.Error(t, err)
+}
+
+func TestService_GetMaxTestReminders_SkipsHeavyDay(t *testing.T) {
+	mockRepo := new(MockPushupRepository)
+
+	candidates := []model.MaxTestReminder{
+		{UserID: 1, MaxReps: 20, DailyNorm: 60},
+		{UserID: 2, MaxReps: 30, DailyNorm: 90},
+	}
+
+	mockRepo.On("GetMaxTestReminderCandidates", mock.Anything, mock.Anything).Return(candidates, nil).Once()
+	// У первого пользователя вчера выполнена норма — тест не предлагаем
+	mockRepo.On("GetDailyTotals", mock.Anything, int64(1), mock.Anything).
+		Return([]model.DailyTotal{{Date: time.Now().AddDate(0, 0, -1), Count: 70}}, nil).Once()
+	mockRepo.On("GetDailyTotals", mock.Anything, int64(2), mock.Anything).
+		Return([]model.DailyTotal{{Date: time.Now().AddDate(0, 0, -1), Count: 20}}, nil).Once()
+
+	service := NewPushupService(mockRepo, Config{})
+
+	reminders, err := service.GetMaxTestReminders(context.Background(), 7*24*time.Hour)
+
+	assert.NoError(t, err)
+	assert.Len(t, reminders, 1)
+	assert.Equal(t, int64(2), reminders[0].UserID)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestService_UpdateMaxReps_WeeklyTarget(t *testing.T) {
+	mockRepo := new(MockPushupRepository)
+
+	thisWeek := WeekStart(time.Now())
+	targets := []model.WeeklyTarget{
+		{ID: 2, WeekStart: thisWeek, BaseMax: 20, TargetMax: 23, Status: model.TargetStatusPending},
+		{ID: 1, WeekStart: thisWeek.AddDate(0, 0, -7), BaseMax: 18, TargetMax: 20, AchievedMax: 20, Status: model.TargetStatusHit},
+	}
+	history := []model.MaxRepsHistoryItem{{MaxReps: 24}, {MaxReps: 20}}
+
+	// 20 → 24: ранг не меняется
+	expectNormInput(mockRepo, 1, NormStrategyACSM, DefaultNormFormula.DailyNorm(20))
+	mockRepo.On("GetUserMaxReps", mock.Anything, int64(1)).Return(20, nil).Once()
+	mockRepo.On("SetMaxReps", mock.Anything, int64(1), 24).Return(nil).Once()
+	mockRepo.On("AddMaxRepsHistory", mock.Anything, int64(1), 24).Return(nil).Once()
+	mockRepo.On("SetDailyNorm", mock.Anything, int64(1), DefaultNormFormula.DailyNorm(24), model.NormSourceMaxTest).Return(nil).Once()
+	mockRepo.On("GetMaxRepsHistory", mock.Anything, int64(1)).Return(history, nil).Once()
+	mockRepo.On("GetMaxRepsRecord", mock.Anything, int64(1)).
+		Return(model.MaxRepsHistoryItem{MaxReps: 24}, nil).Once()
+	mockRepo.On("GetWeeklyTargets", mock.Anything, int64(1)).Return(targets, nil).Once()
+	mockRepo.On("UpdateWeeklyTarget", mock.Anything, int64(2), model.TargetStatusHit, 24).Return(nil).Once()
+	mockRepo.On("SetWeeklyTarget", mock.Anything, int64(1), thisWeek.AddDate(0, 0, 7), 24, 27).Return(nil).Once()
+	expectNoAchievements(mockRepo, 1)
+
+	service := NewPushupService(mockRepo, Config{})
+
+	vm, err := service.UpdateMaxReps(context.Background(), 1, 24)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, vm.HitTarget)
+	assert.Equal(t, 23, vm.HitTarget.TargetMax)
+	assert.Equal(t, 27, vm.NextTarget.TargetMax)
+	assert.Equal(t, 2, vm.TargetStreak)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestService_AddPushupSet_UnlocksAchievements(t *testing.T) {
+	mockRepo := new(MockPushupRepository)
+
+	mockRepo.On("GetDailyNorm", mock.Anything, int64(1)).Return(50, nil).Once()
+	mockRepo.On("GetTodayStat", mock.Anything, int64(1)).Return(20, nil).Once()
+	mockRepo.On("GetUserMaxReps", mock.Anything, int64(1)).Return(30, nil).Once()
+	mockRepo.On("GetMaxRepsRecord", mock.Anything, int64(1)).
+		Return(model.MaxRepsHistoryItem{MaxReps: 30}, nil).Once()
+	mockRepo.On("AddPushupSet", mock.Anything, int64(1), model.VariantStandard, 30, 30, false).Return(50, nil).Once()
+	mockRepo.On("GetFirstNormCompleter", mock.Anything).Return(int64(0), nil).Once()
+	mockRepo.On("SetDateCompletionOfDailyNorm", mock.Anything, int64(1)).Return(nil).Once()
+	mockRepo.On("AddNormCompletion", mock.Anything, int64(1), 50).Return(nil).Once()
+
+	mockRepo.On("GetAchievementStats", mock.Anything, int64(1)).
+		Return(model.AchievementStats{TotalPushups: 1000, FirstCompletions: 1, MaxReps: 30}, nil).Once()
+	mockRepo.On("GetNormCompletionDates", mock.Anything, int64(1)).Return([]time.Time{time.Now()}, nil).Once()
+	mockRepo.On("GetUserAchievements", mock.Anything, int64(1)).
+		Return([]model.UserAchievement{{Code: "first_set"}}, nil).Once()
+	mockRepo.On("GetRestPeriods", mock.Anything, int64(1)).Return([]model.RestPeriod(nil), nil).Once()
+	mockRepo.On("UnlockAchievement", mock.Anything, int64(1), "total_1000").Return(true, nil).Once()
+	// Уже открыто параллельным запросом — повторно не уведомляем
+	mockRepo.On("UnlockAchievement", mock.Anything, int64(1), "first_finisher").Return(false, nil).Once()
+	expectDailyGoal(mockRepo, 1)
+
+	service := NewPushupService(mockRepo, Config{})
+
+	vm, err := service.AddPushupSet(context.Background(), 1, model.VariantStandard, 30)
+
+	assert.NoError(t, err)
+	assert.True(t, vm.Completed)
+	assert.Len(t, vm.NewAchievements, 1)
+	assert.Equal(t, "total_1000", vm.NewAchievements[0].Code)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestService_UpdateMaxReps_RankUp(t *testing.T) {
+	mockRepo := new(MockPushupRepository)
+
+	history := []model.MaxRepsHistoryItem{{MaxReps: 26}, {MaxReps: 23}}
+
+	expectNormInput(mockRepo, 1, NormStrategyACSM, DefaultNormFormula.DailyNorm(23))
+	mockRepo.On("GetUserMaxReps", mock.Anything, int64(1)).Return(23, nil).Once()
+	mockRepo.On("SetMaxReps", mock.Anything, int64(1), 26).Return(nil).Once()
+	mockRepo.On("AddMaxRepsHistory", mock.Anything, int64(1), 26).Return(nil).Once()
+	mockRepo.On("SetDailyNorm", mock.Anything, int64(1), DefaultNormFormula.DailyNorm(26), model.NormSourceMaxTest).Return(nil).Once()
+	mockRepo.On("GetMaxRepsHistory", mock.Anything, int64(1)).Return(history, nil).Once()
+	mockRepo.On("GetMaxRepsRecord", mock.Anything, int64(1)).
+		Return(model.MaxRepsHistoryItem{MaxReps: 26}, nil).Once()
+	mockRepo.On("GetWeeklyTargets", mock.Anything, int64(1)).Return(nil, nil).Once()
+	mockRepo.On("SetWeeklyTarget", mock.Anything, int64(1), mock.Anything, 26, mock.Anything).Return(nil).Once()
+	mockRepo.On("AddRankChange", mock.Anything, int64(1), mock.MatchedBy(func(change model.RankChange) bool {
+		return change.Promoted && change.ToRank == DefaultRanks.Title(26) && change.FromRank == DefaultRanks.Title(23)
+	})).Return(nil).Once()
+	mockRepo.On("GetUsername", mock.Anything, int64(1)).Return("ivan", nil).Once()
+	expectNoAchievements(mockRepo, 1)
+
+	service := NewPushupService(mockRepo, Config{})
+
+	vm, err := service.UpdateMaxReps(context.Background(), 1, 26)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, vm.RankChange)
+	assert.True(t, vm.RankChange.Promoted)
+	assert.Equal(t, "ivan", vm.RankChange.Username)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestService_UpdateMaxReps_UsesUserStrategy(t *testing.T) {
+	mockRepo := new(MockPushupRepository)
+
+	expectNormInput(mockRepo, 1, NormStrategyPercent, 60)
+	mockRepo.On("GetUserMaxReps", mock.Anything, int64(1)).Return(20, nil).Once()
+	mockRepo.On("SetMaxReps", mock.Anything, int64(1), 22).Return(nil).Once()
+	mockRepo.On("AddMaxRepsHistory", mock.Anything, int64(1), 22).Return(nil).Once()
+	// 22 × 300% = 66 → 65
+	mockRepo.On("SetDailyNorm", mock.Anything, int64(1), 65, model.NormSourceMaxTest).Return(nil).Once()
+	mockRepo.On("GetMaxRepsHistory", mock.Anything, int64(1)).Return([]model.MaxRepsHistoryItem{{MaxReps: 22}}, nil).Once()
+	mockRepo.On("GetMaxRepsRecord", mock.Anything, int64(1)).
+		Return(model.MaxRepsHistoryItem{MaxReps: 22}, nil).Once()
+	mockRepo.On("GetWeeklyTargets", mock.Anything, int64(1)).Return(nil, nil).Once()
+	mockRepo.On("SetWeeklyTarget", mock.Anything, int64(1), mock.Anything, 22, mock.Anything).Return(nil).Once()
+	expectNoAchievements(mockRepo, 1)
+
+	service := NewPushupService(mockRepo, Config{})
+
+	vm, err := service.UpdateMaxReps(context.Background(), 1, 22)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 65, vm.DailyNorm)
+	assert.Equal(t, "📐 Процент от максимума", vm.NormStrategy)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestService_SetNormStrategy_Unknown(t *testing.T) {
+	mockRepo := new(MockPushupRepository)
+	service := NewPushupService(mockRepo, Config{})
+
+	_, err := service.SetNormStrategy(context.Background(), 1, "magic")
+
+	assert.Error(t, err)
+	mockRepo.AssertNotCalled(t, "SetNormStrategy", mock.Anything, mock.Anything, mock.Anything)
+}
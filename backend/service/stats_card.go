@@ -0,0 +1,182 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image/color"
+	"time"
+
+	"trackerbot/model"
+
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/font"
+	"gonum.org/v1/plot/text"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+)
+
+// StatsCardDays — за сколько дней на карточке рисуется мини-график
+const StatsCardDays = 30
+
+// Размеры и цвета карточки статистики
+const (
+	statsCardWidth  = vg.Length(600)
+	statsCardHeight = vg.Length(315)
+	statsCardMargin = vg.Length(32)
+)
+
+var (
+	statsCardBackground = color.RGBA{R: 30, G: 42, B: 56, A: 255}
+	statsCardAccent     = color.RGBA{R: 64, G: 196, B: 99, A: 255}
+	statsCardMuted      = color.RGBA{R: 160, G: 174, B: 192, A: 255}
+	statsCardSparkFill  = color.NRGBA{R: 64, G: 196, B: 99, A: 70}
+)
+
+// Sparkline раскладывает суммы по дням на days последних дней, включая сегодняшний
+func Sparkline(totals []model.DailyTotal, days int, now time.Time) []int {
+	from := dateOnly(now).AddDate(0, 0, 1-days)
+
+	values := make([]int, days)
+	for _, day := range totals {
+		index := int(dateOnly(day.Date).Sub(from).Hours() / 24)
+		if index >= 0 && index < days {
+			values[index] += day.Count
+		}
+	}
+	return values
+}
+
+// SendStatsCard рисует карточку статистики: имя, ранг, максимум, сумма за всё время,
+// серия и мини-график последних дней
+func SendStatsCard(card model.StatsCard) (bytes.Buffer, error) {
+	canvas, err := draw.NewFormattedCanvas(statsCardWidth, statsCardHeight, "png")
+	if err != nil {
+		return bytes.Buffer{}, fmt.Errorf("ошибка создания холста: %w", err)
+	}
+	c := draw.New(canvas)
+	c.FillPolygon(statsCardBackground, []vg.Point{
+		{X: 0, Y: 0}, {X: statsCardWidth, Y: 0}, {X: statsCardWidth, Y: statsCardHeight}, {X: 0, Y: statsCardHeight},
+	})
+
+	style := func(size vg.Length, clr color.Color) draw.TextStyle {
+		return draw.TextStyle{
+			Color:   clr,
+			Font:    font.From(plot.DefaultFont, size),
+			Handler: plot.DefaultTextHandler,
+			YAlign:  text.YTop,
+		}
+	}
+
+	top := statsCardHeight - statsCardMargin
+	c.FillText(style(14, statsCardAccent), vg.Point{X: statsCardMargin, Y: top}, "PushUp Tracker")
+	c.FillText(style(28, color.White), vg.Point{X: statsCardMargin, Y: top - 26}, card.Username)
+	c.FillText(style(16, statsCardMuted), vg.Point{X: statsCardMargin, Y: top - 64}, card.Rank)
+
+	metrics := []struct {
+		value string
+		label string
+	}{
+		{fmt.Sprintf("%d", card.MaxReps), "максимум за подход"},
+		{fmt.Sprintf("%d", card.TotalAllTime), "отжиманий всего"},
+		{fmt.Sprintf("%d дн.", card.Streak), "серия нормы"},
+	}
+	column := (statsCardWidth - 2*statsCardMargin) / vg.Length(len(metrics))
+	for i, metric := range metrics {
+		x := statsCardMargin + column*vg.Length(i)
+		c.FillText(style(30, color.White), vg.Point{X: x, Y: top - 104}, metric.value)
+		c.FillText(style(11, statsCardMuted), vg.Point{X: x, Y: top - 142}, metric.label)
+	}
+
+	drawSparkline(c, card.Sparkline, vg.Rectangle{
+		Min: vg.Point{X: statsCardMargin, Y: statsCardMargin},
+		Max: vg.Point{X: statsCardWidth - statsCardMargin, Y: statsCardMargin + 60},
+	})
+	c.FillText(style(9, statsCardMuted),
+		vg.Point{X: statsCardMargin, Y: statsCardMargin - 6},
+		fmt.Sprintf("последние %d дней", len(card.Sparkline)))
+
+	var buf bytes.Buffer
+	if _, err := canvas.WriteTo(&buf); err != nil {
+		return bytes.Buffer{}, err
+	}
+	return buf, nil
+}
+
+// drawSparkline рисует линию значений с заливкой внутри области area
+func drawSparkline(c draw.Canvas, values []int, area vg.Rectangle) {
+	if len(values) < 2 {
+		return
+	}
+
+	peak := 1
+	for _, value := range values {
+		peak = max(peak, value)
+	}
+
+	step := area.Size().X / vg.Length(len(values)-1)
+	points := make([]vg.Point, len(values))
+	for i, value := range values {
+		points[i] = vg.Point{
+			X: area.Min.X + step*vg.Length(i),
+			Y: area.Min.Y + area.Size().Y*vg.Length(value)/vg.Length(peak),
+		}
+	}
+
+	fill := append([]vg.Point{{X: area.Min.X, Y: area.Min.Y}}, points...)
+	fill = append(fill, vg.Point{X: area.Max.X, Y: area.Min.Y})
+	c.FillPolygon(statsCardSparkFill, fill)
+	c.StrokeLines(draw.LineStyle{Color: statsCardAccent, Width: vg.Points(2)}, points)
+}
+
+// GetStatsCard собирает данные карточки статистики пользователя
+func (s *pushupService) GetStatsCard(ctx context.Context, userID int64) (*model.StatsCard, error) {
+	now := time.Now()
+
+	username, err := s.repo.GetUsername(ctx, userID)
+	if err != nil || username == "" {
+		username = fmt.Sprintf("User%d", userID)
+	}
+	maxReps, err := s.repo.GetUserMaxReps(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	stat, err := s.repo.GetFullStat(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	totals, err := s.repo.GetDailyTotals(ctx, userID, dateOnly(now).AddDate(0, 0, 1-StatsCardDays))
+	if err != nil {
+		return nil, err
+	}
+	completions, err := s.repo.GetNormCompletionDates(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	rest, err := s.repo.GetRestPeriods(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.StatsCard{
+		Username:     username,
+		Rank:         s.ranks.Name(maxReps),
+		MaxReps:      maxReps,
+		TotalAllTime: stat.TotalAllTime,
+		Streak:       CalculateNormStreak(completions, rest, now),
+		Sparkline:    Sparkline(totals, StatsCardDays, now),
+	}, nil
+}
+
+// BuildStatsCard рисует карточку статистики пользователя и возвращает данные, по которым она нарисована
+func (s *pushupService) BuildStatsCard(ctx context.Context, userID int64) (*model.StatsCard, bytes.Buffer, error) {
+	card, err := s.GetStatsCard(ctx, userID)
+	if err != nil {
+		return nil, bytes.Buffer{}, err
+	}
+	image, err := SendStatsCard(*card)
+	if err != nil {
+		return nil, bytes.Buffer{}, err
+	}
+	return card, image, nil
+}
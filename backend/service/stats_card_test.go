@@ -0,0 +1,79 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"trackerbot/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSparkline(t *testing.T) {
+	now := time.Date(2026, 3, 10, 18, 0, 0, 0, time.UTC)
+	totals := []model.DailyTotal{
+		{Date: dateOnly(now).AddDate(0, 0, -10), Count: 50}, // раньше окна
+		{Date: dateOnly(now).AddDate(0, 0, -2), Count: 30},
+		{Date: dateOnly(now), Count: 20},
+	}
+
+	assert.Equal(t, []int{0, 30, 0, 20}, Sparkline(totals, 4, now))
+}
+
+func TestGetStatsCard(t *testing.T) {
+	mockRepo := new(MockPushupRepository)
+	svc := NewPushupService(mockRepo, Config{})
+
+	ctx := context.Background()
+	userID := int64(1)
+	today := dateOnly(time.Now())
+
+	mockRepo.On("GetUsername", ctx, userID).Return("", nil).Once()
+	mockRepo.On("GetUserMaxReps", ctx, userID).Return(32, nil).Once()
+	mockRepo.On("GetFullStat", ctx, userID).Return(&model.FullStatViewModel{TotalAllTime: 4200}, nil).Once()
+	mockRepo.On("GetDailyTotals", ctx, userID, mock.Anything).
+		Return([]model.DailyTotal{{Date: today, Count: 120}}, nil).Once()
+	mockRepo.On("GetNormCompletionDates", ctx, userID).
+		Return([]time.Time{today, today.AddDate(0, 0, -1)}, nil).Once()
+	mockRepo.On("GetRestPeriods", ctx, userID).Return([]model.RestPeriod{}, nil).Once()
+
+	card, err := svc.GetStatsCard(ctx, userID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "User1", card.Username)
+	assert.Equal(t, DefaultRanks.Name(32), card.Rank)
+	assert.Equal(t, 4200, card.TotalAllTime)
+	assert.Equal(t, 2, card.Streak)
+	assert.Len(t, card.Sparkline, StatsCardDays)
+	assert.Equal(t, 120, card.Sparkline[StatsCardDays-1])
+	mockRepo.AssertExpectations(t)
+}
+
+func TestSendStatsCard(t *testing.T) {
+	sparkline := make([]int, StatsCardDays)
+	for i := range sparkline {
+		sparkline[i] = (i * 37) % 150
+	}
+
+	buf, err := SendStatsCard(model.StatsCard{
+		Username:     "pushup_fan",
+		Rank:         "Рыцарь света",
+		MaxReps:      32,
+		TotalAllTime: 15400,
+		Streak:       12,
+		Sparkline:    sparkline,
+	})
+
+	assert.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(buf.Bytes(), pngSignature))
+	if path := os.Getenv("DUMP_CARD"); path != "" {
+		_ = os.WriteFile(path, buf.Bytes(), 0o644)
+	}
+
+	_, err = SendStatsCard(model.StatsCard{Username: "new"})
+	assert.NoError(t, err)
+}
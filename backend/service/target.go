@@ -0,0 +1,196 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"trackerbot/model"
+)
+
+// WeekStart возвращает понедельник ISO-недели, в которую попадает t
+func WeekStart(t time.Time) time.Time {
+	day := dateOnly(t)
+	offset := (int(day.Weekday()) + 6) % 7 // понедельник = 0
+	return day.AddDate(0, 0, -offset)
+}
+
+// NewWeeklyTarget формирует цель на следующую неделю после теста с результатом maxReps
+func NewWeeklyTarget(maxReps int, now time.Time) model.WeeklyTarget {
+	return model.WeeklyTarget{
+		WeekStart: WeekStart(now).AddDate(0, 0, 7),
+		BaseMax:   maxReps,
+		TargetMax: maxReps + CalculateNextTarget(maxReps),
+		Status:    model.TargetStatusPending,
+	}
+}
+
+// EvaluateWeeklyTarget возвращает статус цели после теста с результатом count.
+// Цель засчитывается, если результат достигнут не раньше её недели.
+// Если неделя закончилась, а цель не достигнута, — она провалена.
+// Аргументы:
+//
+//	target - текущая цель
+//	count  - результат теста (отрицательный, если теста не было)
+//	now    - текущее время
+func EvaluateWeeklyTarget(target model.WeeklyTarget, count int, now time.Time) string {
+	if target.Status != model.TargetStatusPending {
+		return target.Status
+	}
+
+	thisWeek := WeekStart(now)
+	if target.WeekStart.After(thisWeek) {
+		return model.TargetStatusPending
+	}
+
+	if count >= target.TargetMax || target.AchievedMax >= target.TargetMax {
+		return model.TargetStatusHit
+	}
+
+	if target.WeekStart.Before(thisWeek) {
+		return model.TargetStatusMissed
+	}
+
+	return model.TargetStatusPending
+}
+
+// CalculateTargetStreaks считает текущую и лучшую серии выполненных целей подряд.
+// Цели передаются от самой поздней недели к самой ранней, незавершённые пропускаются.
+func CalculateTargetStreaks(targets []model.WeeklyTarget) (current, best int) {
+	run := 0
+	currentDone := false
+
+	for _, target := range targets {
+		switch target.Status {
+		case model.TargetStatusHit:
+			run++
+		case model.TargetStatusMissed:
+			if !currentDone {
+				current = run
+				currentDone = true
+			}
+			run = 0
+		default:
+			continue
+		}
+
+		if run > best {
+			best = run
+		}
+	}
+
+	if !currentDone {
+		current = run
+	}
+
+	return current, best
+}
+
+// resolveWeeklyTargets пересчитывает статусы незавершённых целей с учётом результата count
+// и сохраняет изменения. Возвращает цель, выполненную этим тестом (если есть).
+func (s *pushupService) resolveWeeklyTargets(
+	ctx context.Context,
+	targets []model.WeeklyTarget,
+	count int,
+	now time.Time,
+) (*model.WeeklyTarget, error) {
+
+	var hit *model.WeeklyTarget
+
+	for i := range targets {
+		target := &targets[i]
+		if target.Status != model.TargetStatusPending {
+			continue
+		}
+
+		status := EvaluateWeeklyTarget(*target, count, now)
+		achieved := target.AchievedMax
+		if count > achieved && !target.WeekStart.After(WeekStart(now)) {
+			achieved = count
+		}
+
+		if status == target.Status && achieved == target.AchievedMax {
+			continue
+		}
+
+		if err := s.repo.UpdateWeeklyTarget(ctx, target.ID, status, achieved); err != nil {
+			return nil, err
+		}
+
+		target.Status = status
+		target.AchievedMax = achieved
+		if status == model.TargetStatusHit && count >= 0 {
+			hit = target
+		}
+	}
+
+	return hit, nil
+}
+
+// updateWeeklyTargets отмечает цели по результату теста и ставит цель на следующую неделю
+func (s *pushupService) updateWeeklyTargets(
+	ctx context.Context,
+	userID int64,
+	count int,
+	now time.Time,
+) (hit *model.WeeklyTarget, next model.WeeklyTarget, streak int, err error) {
+
+	targets, err := s.repo.GetWeeklyTargets(ctx, userID)
+	if err != nil {
+		return nil, next, 0, err
+	}
+
+	hit, err = s.resolveWeeklyTargets(ctx, targets, count, now)
+	if err != nil {
+		return nil, next, 0, err
+	}
+
+	next = NewWeeklyTarget(count, now)
+	if err := s.repo.SetWeeklyTarget(ctx, userID, next.WeekStart, next.BaseMax, next.TargetMax); err != nil {
+		return nil, next, 0, err
+	}
+
+	streak, _ = CalculateTargetStreaks(targets)
+	return hit, next, streak, nil
+}
+
+// GetWeeklyTargets возвращает сводку по недельным целям: текущая и следующая цели,
+// завершённые недели и серию выполненных целей
+func (s *pushupService) GetWeeklyTargets(ctx context.Context, userID int64) (*model.WeeklyTargetSummary, error) {
+	now := time.Now()
+
+	targets, err := s.repo.GetWeeklyTargets(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Истёкшие недели отмечаем как проваленные
+	if _, err := s.resolveWeeklyTargets(ctx, targets, -1, now); err != nil {
+		return nil, err
+	}
+
+	summary := &model.WeeklyTargetSummary{}
+	thisWeek := WeekStart(now)
+
+	for i := range targets {
+		target := targets[i]
+
+		switch {
+		case target.WeekStart.After(thisWeek):
+			summary.Next = &target
+		case target.WeekStart.Equal(thisWeek) && target.Status == model.TargetStatusPending:
+			summary.Current = &target
+		default:
+			summary.Resolved = append(summary.Resolved, target)
+		}
+
+		switch target.Status {
+		case model.TargetStatusHit:
+			summary.Hits++
+		case model.TargetStatusMissed:
+			summary.Misses++
+		}
+	}
+
+	summary.Streak, summary.BestStreak = CalculateTargetStreaks(targets)
+	return summary, nil
+}
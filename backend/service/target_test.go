@@ -0,0 +1,95 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"trackerbot/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWeekStart(t *testing.T) {
+	monday := time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		date time.Time
+	}{
+		{"Monday", time.Date(2026, 3, 9, 8, 0, 0, 0, time.UTC)},
+		{"Wednesday", time.Date(2026, 3, 11, 12, 0, 0, 0, time.UTC)},
+		{"Sunday", time.Date(2026, 3, 15, 23, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, monday, WeekStart(tt.date))
+		})
+	}
+}
+
+func TestNewWeeklyTarget(t *testing.T) {
+	now := time.Date(2026, 3, 11, 12, 0, 0, 0, time.UTC)
+
+	target := NewWeeklyTarget(20, now)
+
+	assert.Equal(t, time.Date(2026, 3, 16, 0, 0, 0, 0, time.UTC), target.WeekStart)
+	assert.Equal(t, 20, target.BaseMax)
+	assert.Equal(t, 20+CalculateNextTarget(20), target.TargetMax)
+	assert.Equal(t, model.TargetStatusPending, target.Status)
+}
+
+func TestEvaluateWeeklyTarget(t *testing.T) {
+	now := time.Date(2026, 3, 11, 12, 0, 0, 0, time.UTC)
+	thisWeek := WeekStart(now)
+
+	pending := func(weekStart time.Time) model.WeeklyTarget {
+		return model.WeeklyTarget{WeekStart: weekStart, TargetMax: 25, Status: model.TargetStatusPending}
+	}
+
+	tests := []struct {
+		name   string
+		target model.WeeklyTarget
+		count  int
+		want   string
+	}{
+		{"HitThisWeek", pending(thisWeek), 25, model.TargetStatusHit},
+		{"BelowThisWeek", pending(thisWeek), 24, model.TargetStatusPending},
+		{"FutureWeek", pending(thisWeek.AddDate(0, 0, 7)), 30, model.TargetStatusPending},
+		{"ExpiredWithoutTest", pending(thisWeek.AddDate(0, 0, -7)), -1, model.TargetStatusMissed},
+		{"LateHitCounts", pending(thisWeek.AddDate(0, 0, -7)), 26, model.TargetStatusHit},
+		{"AlreadyResolved", model.WeeklyTarget{Status: model.TargetStatusMissed}, 100, model.TargetStatusMissed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, EvaluateWeeklyTarget(tt.target, tt.count, now))
+		})
+	}
+}
+
+func TestCalculateTargetStreaks(t *testing.T) {
+	hit := model.WeeklyTarget{Status: model.TargetStatusHit}
+	miss := model.WeeklyTarget{Status: model.TargetStatusMissed}
+	pending := model.WeeklyTarget{Status: model.TargetStatusPending}
+
+	tests := []struct {
+		name        string
+		targets     []model.WeeklyTarget
+		wantCurrent int
+		wantBest    int
+	}{
+		{"Empty", nil, 0, 0},
+		{"AllHits", []model.WeeklyTarget{pending, hit, hit, hit}, 3, 3},
+		{"BrokenStreak", []model.WeeklyTarget{hit, miss, hit, hit, hit}, 1, 3},
+		{"LastMissed", []model.WeeklyTarget{miss, hit, hit}, 0, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current, best := CalculateTargetStreaks(tt.targets)
+			assert.Equal(t, tt.wantCurrent, current)
+			assert.Equal(t, tt.wantBest, best)
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"math"
+
+	"trackerbot/config"
+	"trackerbot/model"
+)
+
+// Variants — каталог вариантов отжиманий
+type Variants []model.PushupVariant
+
+// DefaultPushupVariants используются, если в config.yml не задан раздел variants
+var DefaultPushupVariants = Variants{
+	{Code: model.VariantStandard, Name: "⚪ Обычные", Coefficient: 1.0},
+	{Code: "knee", Name: "🦵 С колен", Coefficient: 0.5},
+	{Code: "wide", Name: "↔️ Широкие", Coefficient: 1.1},
+	{Code: "decline", Name: "📐 С ногами на возвышении", Coefficient: 1.3},
+	{Code: "diamond", Name: "💎 Алмазные", Coefficient: 1.5},
+}
+
+// NewVariants собирает каталог вариантов из конфигурации;
+// если раздел variants не задан, возвращает DefaultPushupVariants.
+// Обычные отжимания всегда присутствуют в каталоге с коэффициентом 1.0.
+func NewVariants(variants []config.VariantConfig) Variants {
+	if len(variants) == 0 {
+		return DefaultPushupVariants
+	}
+
+	catalog := Variants{DefaultPushupVariants[0]}
+	for _, v := range variants {
+		if v.Code == model.VariantStandard {
+			catalog[0].Name = v.Name
+			continue
+		}
+		catalog = append(catalog, model.PushupVariant{
+			Code:        v.Code,
+			Name:        v.Name,
+			Coefficient: v.Coefficient,
+		})
+	}
+
+	return catalog
+}
+
+// Find возвращает вариант по коду
+func (variants Variants) Find(code string) (model.PushupVariant, bool) {
+	for _, v := range variants {
+		if v.Code == code {
+			return v, true
+		}
+	}
+	return model.PushupVariant{}, false
+}
+
+// EquivalentPushups переводит количество отжиманий варианта в обычные отжимания.
+// Любой непустой подход засчитывается минимум как одно отжимание.
+func EquivalentPushups(count int, coefficient float64) int {
+	if count <= 0 {
+		return 0
+	}
+
+	equivalent := int(math.Round(float64(count) * coefficient))
+	if equivalent < 1 {
+		return 1
+	}
+	return equivalent
+}
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"testing"
+
+	"trackerbot/config"
+	"trackerbot/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEquivalentPushups(t *testing.T) {
+	tests := []struct {
+		count       int
+		coefficient float64
+		want        int
+	}{
+		{0, 1.5, 0},
+		{10, 1.0, 10},
+		{20, 1.5, 30},
+		{15, 0.5, 8},
+		{1, 0.1, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run("", func(t *testing.T) {
+			assert.Equal(t, tt.want, EquivalentPushups(tt.count, tt.coefficient))
+		})
+	}
+}
+
+func TestNewVariants(t *testing.T) {
+	variants := NewVariants([]config.VariantConfig{
+		{Code: "archer", Name: "🏹 Лучник", Coefficient: 2},
+	})
+
+	standard, ok := variants.Find(model.VariantStandard)
+	assert.True(t, ok)
+	assert.Equal(t, 1.0, standard.Coefficient)
+
+	archer, ok := variants.Find("archer")
+	assert.True(t, ok)
+	assert.Equal(t, 2.0, archer.Coefficient)
+
+	_, ok = variants.Find("diamond")
+	assert.False(t, ok)
+
+	_, ok = NewVariants(nil).Find("diamond")
+	assert.True(t, ok)
+}
+
+func TestNewPushupService_Config(t *testing.T) {
+	custom := NewPushupService(new(MockPushupRepository), Config{
+		Variants: []config.VariantConfig{{Code: "archer", Name: "🏹 Лучник", Coefficient: 2}},
+		Ranks:    []config.RankConfig{{Threshold: 0, Emoji: "🐣", Name: "Новичок"}},
+	})
+	defaults := NewPushupService(new(MockPushupRepository), Config{})
+
+	assert.Len(t, custom.GetPushupVariants(), 2)
+	assert.Len(t, custom.GetRanks(), 1)
+	assert.Equal(t, []model.PushupVariant(DefaultPushupVariants), defaults.GetPushupVariants())
+	assert.Equal(t, []model.RankInfo(DefaultRanks), defaults.GetRanks())
+}
@@ -0,0 +1,185 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image/color"
+	"time"
+
+	"trackerbot/model"
+
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+)
+
+// MaxVolumeChartDays — самый длинный период графика объёма по дням
+const MaxVolumeChartDays = 365
+
+var (
+	volumeCompletedColor = color.RGBA{R: 76, G: 175, B: 80, A: 255}
+	volumeMissedColor    = color.RGBA{R: 176, G: 190, B: 197, A: 255}
+)
+
+// normOn возвращает норму, действовавшую к концу дня date (series — старые первыми)
+func normOn(series []model.NormPoint, date time.Time) int {
+	day := dateOnly(date)
+	norm := 0
+	for _, point := range series {
+		if dateOnly(point.Date).After(day) {
+			break
+		}
+		norm = point.Norm
+	}
+	return norm
+}
+
+// DailyVolumes раскладывает суммы по каждому дню полуинтервала [from, to):
+// дни без отжиманий получают ноль, дни отдыха — нулевую норму.
+// Выполненными считаются дни из completions (norm_completions): там записана норма,
+// действовавшая в момент выполнения, а восстановленная по истории может с ней расходиться
+func DailyVolumes(
+	totals []model.DailyTotal,
+	norms []model.NormPoint,
+	completions []time.Time,
+	rest []model.RestPeriod,
+	from, to time.Time,
+) []model.DailyVolume {
+
+	counts := make(map[time.Time]int, len(totals))
+	for _, day := range totals {
+		counts[dateOnly(day.Date)] += day.Count
+	}
+	completed := make(map[time.Time]bool, len(completions))
+	for _, date := range completions {
+		completed[dateOnly(date)] = true
+	}
+
+	var days []model.DailyVolume
+	for day := dateOnly(from); day.Before(dateOnly(to)); day = day.AddDate(0, 0, 1) {
+		volume := model.DailyVolume{Date: day, Count: counts[day]}
+		if !IsRestDay(rest, day) {
+			volume.Norm = normOn(norms, day)
+		}
+		volume.Completed = completed[day]
+		days = append(days, volume)
+	}
+	return days
+}
+
+// SendVolumeChart строит столбцы отжиманий по дням со ступенчатой линией нормы.
+// Дни с выполненной нормой выделены цветом
+func SendVolumeChart(days []model.DailyVolume) (bytes.Buffer, error) {
+	if len(days) == 0 {
+		return bytes.Buffer{}, fmt.Errorf("нет данных для графика")
+	}
+
+	completed := make(plotter.Values, len(days))
+	missed := make(plotter.Values, len(days))
+	norm := make(plotter.XYs, 0, len(days)+1)
+	for i, day := range days {
+		if day.Completed {
+			completed[i] = float64(day.Count)
+		} else {
+			missed[i] = float64(day.Count)
+		}
+		norm = append(norm, plotter.XY{X: float64(i) - 0.5, Y: float64(day.Norm)})
+	}
+	norm = append(norm, plotter.XY{X: float64(len(days)) - 0.5, Y: float64(days[len(days)-1].Norm)})
+
+	p := plot.New()
+	p.Title.Text = fmt.Sprintf("Отжимания по дням: %s — %s",
+		days[0].Date.Format("02.01.2006"), days[len(days)-1].Date.Format("02.01.2006"))
+	p.Y.Label.Text = "Отжиманий за день"
+	p.Y.Min = 0
+	p.X.Tick.Marker = volumeTicks(days)
+	p.Add(plotter.NewGrid())
+
+	width := vg.Points(400 / float64(len(days)))
+	for _, series := range []struct {
+		values plotter.Values
+		color  color.Color
+		label  string
+	}{
+		{missed, volumeMissedColor, "Норма не выполнена"},
+		{completed, volumeCompletedColor, "Норма выполнена"},
+	} {
+		bars, err := plotter.NewBarChart(series.values, width)
+		if err != nil {
+			return bytes.Buffer{}, fmt.Errorf("ошибка построения столбцов: %w", err)
+		}
+		bars.Color = series.color
+		bars.LineStyle.Width = 0
+		p.Add(bars)
+		p.Legend.Add(series.label, bars)
+	}
+
+	normLine, err := plotter.NewLine(norm)
+	if err != nil {
+		return bytes.Buffer{}, fmt.Errorf("ошибка построения линии нормы: %w", err)
+	}
+	normLine.StepStyle = plotter.PostStep
+	normLine.Color = scheduleNormColor
+	normLine.Width = vg.Points(1.5)
+	p.Add(normLine)
+	p.Legend.Add("Дневная норма", normLine)
+
+	p.Legend.Top = true
+	p.Legend.Left = true
+
+	writerTo, err := p.WriterTo(8*vg.Inch, 4*vg.Inch, "png")
+	if err != nil {
+		return bytes.Buffer{}, fmt.Errorf("ошибка отрисовки графика: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if _, err := writerTo.WriteTo(&buf); err != nil {
+		return bytes.Buffer{}, err
+	}
+	return buf, nil
+}
+
+// volumeTicks подписывает ось дат так, чтобы помещалось не больше десятка подписей
+func volumeTicks(days []model.DailyVolume) plot.ConstantTicks {
+	step := max((len(days)+9)/10, 1)
+
+	ticks := make(plot.ConstantTicks, 0, len(days))
+	for i, day := range days {
+		tick := plot.Tick{Value: float64(i)}
+		if i%step == 0 {
+			tick.Label = day.Date.Format("02.01")
+		}
+		ticks = append(ticks, tick)
+	}
+	return ticks
+}
+
+// BuildVolumeChart строит график объёма за последние days дней, включая сегодня
+func (s *pushupService) BuildVolumeChart(ctx context.Context, userID int64, days int) (bytes.Buffer, error) {
+	if days <= 0 || days > MaxVolumeChartDays {
+		return bytes.Buffer{}, fmt.Errorf("период графика должен быть от 1 до %d дней", MaxVolumeChartDays)
+	}
+
+	now := time.Now()
+	from := dateOnly(now).AddDate(0, 0, 1-days)
+
+	totals, err := s.repo.GetDailyTotals(ctx, userID, from)
+	if err != nil {
+		return bytes.Buffer{}, err
+	}
+	norms, err := s.normSeries(ctx, userID, from, now)
+	if err != nil {
+		return bytes.Buffer{}, err
+	}
+	completions, err := s.repo.GetNormCompletionDates(ctx, userID)
+	if err != nil {
+		return bytes.Buffer{}, err
+	}
+	rest, err := s.repo.GetRestPeriods(ctx, userID)
+	if err != nil {
+		return bytes.Buffer{}, err
+	}
+
+	return SendVolumeChart(DailyVolumes(totals, norms, completions, rest, from, dateOnly(now).AddDate(0, 0, 1)))
+}
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"bytes"
+	"os"
+	"testing"
+	"time"
+
+	"trackerbot/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDailyVolumes(t *testing.T) {
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	day := func(offset int) time.Time { return from.AddDate(0, 0, offset) }
+
+	totals := []model.DailyTotal{
+		{Date: day(0), Count: 100},
+		{Date: day(2), Count: 60},
+		{Date: day(3), Count: 130},
+	}
+	norms := []model.NormPoint{
+		{Date: from, Norm: 80},
+		{Date: day(3).Add(2 * time.Hour), Norm: 120},
+	}
+	// День 2 выполнен по норме, действовавшей тогда (60), хотя в восстановленной истории — 80;
+	// день 3 по сумме выше нормы, но выполнения в norm_completions нет
+	completions := []time.Time{day(2), day(0)}
+	rest := []model.RestPeriod{{Kind: model.RestKindDay, StartDate: day(1), EndDate: day(1)}}
+
+	days := DailyVolumes(totals, norms, completions, rest, from, day(4))
+
+	assert.Len(t, days, 4)
+	assert.Equal(t, model.DailyVolume{Date: day(0), Count: 100, Norm: 80, Completed: true}, days[0])
+	assert.Equal(t, model.DailyVolume{Date: day(1), Count: 0, Norm: 0}, days[1])
+	assert.Equal(t, model.DailyVolume{Date: day(2), Count: 60, Norm: 80, Completed: true}, days[2])
+	assert.Equal(t, model.DailyVolume{Date: day(3), Count: 130, Norm: 120}, days[3])
+}
+
+func TestSendVolumeChart(t *testing.T) {
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	totals := make([]model.DailyTotal, 0, 30)
+	var completions []time.Time
+	for i := 0; i < 30; i += 2 {
+		totals = append(totals, model.DailyTotal{Date: from.AddDate(0, 0, i), Count: 50 + 3*i})
+		if 50+3*i >= 90 {
+			completions = append(completions, from.AddDate(0, 0, i))
+		}
+	}
+
+	days := DailyVolumes(totals, []model.NormPoint{{Date: from, Norm: 90}}, completions, nil, from, from.AddDate(0, 0, 30))
+	buf, err := SendVolumeChart(days)
+
+	assert.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(buf.Bytes(), pngSignature))
+	if path := os.Getenv("DUMP_CHART"); path != "" {
+		_ = os.WriteFile(path, buf.Bytes(), 0o644)
+	}
+
+	_, err = SendVolumeChart(nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,196 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"trackerbot/model"
+)
+
+const (
+	MaxWeeklyGoalVolume = 10000 // Верхняя граница недельного объёма
+	WeeklyStreakWeeks   = 52    // Сколько недель истории учитывается в серии
+)
+
+// IsWeekCompleted сообщает, выполнена ли недельная цель:
+// набран объём и (если задано) нужное число тренировочных дней
+func IsWeekCompleted(total, trainingDays int, goal model.WeeklyGoal) bool {
+	if total < goal.Volume {
+		return false
+	}
+	return goal.Days == 0 || trainingDays >= goal.Days
+}
+
+// restAdjustedGoal уменьшает недельную цель пропорционально дням отдыха в неделе
+func restAdjustedGoal(goal model.WeeklyGoal, restDays int) model.WeeklyGoal {
+	if restDays <= 0 {
+		return goal
+	}
+
+	activeDays := max(7-restDays, 0)
+	goal.Volume = (goal.Volume*activeDays + 6) / 7
+	goal.Days = min(goal.Days, activeDays)
+	return goal
+}
+
+// weekSummary — сумма и число тренировочных дней за одну ISO-неделю
+type weekSummary struct {
+	total        int
+	trainingDays int
+}
+
+// summarizeWeeks группирует суммы по дням в ISO-недели (ключ — понедельник)
+func summarizeWeeks(totals []model.DailyTotal) map[time.Time]weekSummary {
+	weeks := make(map[time.Time]weekSummary)
+	for _, day := range totals {
+		if day.Count <= 0 {
+			continue
+		}
+		start := WeekStart(day.Date)
+		week := weeks[start]
+		week.total += day.Count
+		week.trainingDays++
+		weeks[start] = week
+	}
+	return weeks
+}
+
+// CalculateWeekStreak считает, сколько ISO-недель подряд выполнялась цель.
+// Текущая неделя ещё не закончилась: если цель пока не выполнена,
+// серия считается с прошлой недели и не прерывается.
+// Дни отдыха уменьшают цель недели, а неделя целиком в отпуске пропускается
+func CalculateWeekStreak(totals []model.DailyTotal, goal model.WeeklyGoal, rest []model.RestPeriod, now time.Time) int {
+	weeks := summarizeWeeks(totals)
+
+	completed := func(week time.Time) bool {
+		summary := weeks[week]
+		weekGoal := restAdjustedGoal(goal, CountRestDays(rest, week, week.AddDate(0, 0, 7)))
+		return IsWeekCompleted(summary.total, summary.trainingDays, weekGoal)
+	}
+
+	week := WeekStart(now)
+	if !completed(week) {
+		week = week.AddDate(0, 0, -7)
+	}
+
+	streak := 0
+	for i := 0; i < WeeklyStreakWeeks; i++ {
+		restDays := CountRestDays(rest, week, week.AddDate(0, 0, 7))
+		switch {
+		case restDays >= 7 && weeks[week].total == 0:
+			// Неделя отпуска серию не прерывает и не продлевает
+		case completed(week):
+			streak++
+		default:
+			return streak
+		}
+		week = week.AddDate(0, 0, -7)
+	}
+
+	return streak
+}
+
+// CalculateWeeklyProgress считает прогресс текущей ISO-недели.
+// Аргументы:
+//
+//	totals - суммы по дням (достаточно истории за WeeklyStreakWeeks недель)
+//	goal   - недельная цель пользователя
+//	rest   - дни отдыха и отпуска
+//	now    - текущее время
+//
+// Возвращает:
+//
+//	прогресс недели (цель уменьшена на дни отдыха) вместе с серией выполненных недель
+func CalculateWeeklyProgress(
+	totals []model.DailyTotal,
+	goal model.WeeklyGoal,
+	rest []model.RestPeriod,
+	now time.Time,
+) model.WeeklyProgress {
+
+	start := WeekStart(now)
+	current := summarizeWeeks(totals)[start]
+	elapsed := int(dateOnly(now).Sub(start).Hours() / 24)
+	restDays := CountRestDays(rest, start, start.AddDate(0, 0, 7))
+	weekGoal := restAdjustedGoal(goal, restDays)
+
+	return model.WeeklyProgress{
+		WeekStart:    start,
+		Total:        current.total,
+		Volume:       weekGoal.Volume,
+		TrainingDays: current.trainingDays,
+		TargetDays:   weekGoal.Days,
+		RestDays:     restDays,
+		DaysLeft:     7 - elapsed,
+		Completed:    IsWeekCompleted(current.total, current.trainingDays, weekGoal),
+		Streak:       CalculateWeekStreak(totals, goal, rest, now),
+	}
+}
+
+// weeklyProgress возвращает прогресс недели или nil, если пользователь в режиме дневной нормы
+func (s *pushupService) weeklyProgress(ctx context.Context, userID int64) (*model.WeeklyProgress, error) {
+	goal, err := s.repo.GetWeeklyGoal(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !goal.IsWeekly() {
+		return nil, nil
+	}
+
+	now := time.Now()
+	totals, err := s.repo.GetDailyTotals(ctx, userID, WeekStart(now).AddDate(0, 0, -7*WeeklyStreakWeeks))
+	if err != nil {
+		return nil, err
+	}
+
+	rest, err := s.repo.GetRestPeriods(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	progress := CalculateWeeklyProgress(totals, goal, rest, now)
+	return &progress, nil
+}
+
+// GetWeeklyGoal возвращает режим цели пользователя
+func (s *pushupService) GetWeeklyGoal(ctx context.Context, userID int64) (model.WeeklyGoal, error) {
+	return s.repo.GetWeeklyGoal(ctx, userID)
+}
+
+// GetWeeklyProgress возвращает прогресс текущей недели (nil в режиме дневной нормы)
+func (s *pushupService) GetWeeklyProgress(ctx context.Context, userID int64) (*model.WeeklyProgress, error) {
+	return s.weeklyProgress(ctx, userID)
+}
+
+// SetWeeklyGoal переводит пользователя на недельную цель
+// Аргументы:
+//
+//	volume - объём за неделю
+//	days   - желаемое число тренировочных дней (0 — не важно)
+func (s *pushupService) SetWeeklyGoal(ctx context.Context, userID int64, volume, days int) (model.WeeklyGoal, error) {
+	if volume <= 0 || volume > MaxWeeklyGoalVolume {
+		return model.WeeklyGoal{}, fmt.Errorf("недельный объём должен быть от 1 до %d", MaxWeeklyGoalVolume)
+	}
+	if days < 0 || days > 7 {
+		return model.WeeklyGoal{}, fmt.Errorf("число тренировочных дней должно быть от 0 до 7")
+	}
+
+	goal := model.WeeklyGoal{Mode: model.GoalModeWeekly, Volume: volume, Days: days}
+	if err := s.repo.SetWeeklyGoal(ctx, userID, goal); err != nil {
+		return model.WeeklyGoal{}, err
+	}
+	return goal, nil
+}
+
+// DisableWeeklyGoal возвращает пользователя к дневной норме.
+// Параметры недельной цели сохраняются, чтобы их можно было включить снова
+func (s *pushupService) DisableWeeklyGoal(ctx context.Context, userID int64) error {
+	goal, err := s.repo.GetWeeklyGoal(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	goal.Mode = model.GoalModeDaily
+	return s.repo.SetWeeklyGoal(ctx, userID, goal)
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"trackerbot/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCalculateWeekStreak(t *testing.T) {
+	// Среда
+	now := time.Date(2026, 3, 18, 12, 0, 0, 0, time.UTC)
+	goal := model.WeeklyGoal{Mode: model.GoalModeWeekly, Volume: 300, Days: 3}
+
+	// day возвращает день недели weeksAgo недель назад (0 — понедельник)
+	day := func(weeksAgo, weekday, count int) model.DailyTotal {
+		return model.DailyTotal{Date: WeekStart(now).AddDate(0, 0, -7*weeksAgo+weekday), Count: count}
+	}
+
+	tests := []struct {
+		name   string
+		totals []model.DailyTotal
+		want   int
+	}{
+		{"Empty", nil, 0},
+		{"CurrentWeekInProgress", []model.DailyTotal{
+			day(0, 0, 50),
+			day(1, 0, 100), day(1, 2, 100), day(1, 4, 100),
+			day(2, 1, 150), day(2, 3, 150), day(2, 5, 50),
+		}, 2},
+		{"CurrentWeekDone", []model.DailyTotal{
+			day(0, 0, 100), day(0, 1, 100), day(0, 2, 100),
+			day(1, 0, 100), day(1, 2, 100), day(1, 4, 100),
+		}, 2},
+		{"NotEnoughDays", []model.DailyTotal{
+			day(1, 0, 200), day(1, 2, 200),
+		}, 0},
+		{"GapBreaksStreak", []model.DailyTotal{
+			day(1, 0, 100), day(1, 2, 100), day(1, 4, 100),
+			day(3, 0, 100), day(3, 2, 100), day(3, 4, 100),
+		}, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, CalculateWeekStreak(tt.totals, goal, nil, now))
+		})
+	}
+}
+
+func TestCalculateWeekStreak_Vacation(t *testing.T) {
+	now := time.Date(2026, 3, 18, 12, 0, 0, 0, time.UTC)
+	goal := model.WeeklyGoal{Mode: model.GoalModeWeekly, Volume: 280}
+	monday := func(weeksAgo int) time.Time { return WeekStart(now).AddDate(0, 0, -7*weeksAgo) }
+
+	totals := []model.DailyTotal{
+		{Date: monday(1), Count: 300},
+		// Неделя 2 — целиком отпуск, тренировок нет
+		{Date: monday(3), Count: 120}, // 3 дня отдыха: цель недели 160
+		{Date: monday(3).AddDate(0, 0, 1), Count: 50},
+	}
+	rest := []model.RestPeriod{
+		{Kind: model.RestKindVacation, StartDate: monday(2), EndDate: monday(2).AddDate(0, 0, 6)},
+		{Kind: model.RestKindVacation, StartDate: monday(3).AddDate(0, 0, 4), EndDate: monday(3).AddDate(0, 0, 6)},
+	}
+
+	assert.Equal(t, 2, CalculateWeekStreak(totals, goal, rest, now))
+	assert.Equal(t, 1, CalculateWeekStreak(totals, goal, nil, now))
+}
+
+func TestCalculateWeeklyProgress(t *testing.T) {
+	// Среда: пн, вт, ср — осталось 5 дней вместе с сегодняшним
+	now := time.Date(2026, 3, 18, 12, 0, 0, 0, time.UTC)
+	goal := model.WeeklyGoal{Mode: model.GoalModeWeekly, Volume: 300, Days: 3}
+
+	totals := []model.DailyTotal{
+		{Date: time.Date(2026, 3, 13, 0, 0, 0, 0, time.UTC), Count: 500}, // прошлая неделя
+		{Date: time.Date(2026, 3, 16, 0, 0, 0, 0, time.UTC), Count: 120},
+		{Date: time.Date(2026, 3, 18, 0, 0, 0, 0, time.UTC), Count: 80},
+	}
+
+	progress := CalculateWeeklyProgress(totals, goal, nil, now)
+
+	assert.Equal(t, time.Date(2026, 3, 16, 0, 0, 0, 0, time.UTC), progress.WeekStart)
+	assert.Equal(t, 200, progress.Total)
+	assert.Equal(t, 2, progress.TrainingDays)
+	assert.Equal(t, 5, progress.DaysLeft)
+	assert.False(t, progress.Completed)
+}
+
+func TestService_AddPushupSet_WeeklyGoal(t *testing.T) {
+	mockRepo := new(MockPushupRepository)
+	goal := model.WeeklyGoal{Mode: model.GoalModeWeekly, Volume: 100}
+	today := time.Now()
+
+	mockRepo.On("GetDailyNorm", mock.Anything, int64(1)).Return(50, nil).Once()
+	mockRepo.On("GetTodayStat", mock.Anything, int64(1)).Return(0, nil).Once()
+	mockRepo.On("GetUserMaxReps", mock.Anything, int64(1)).Return(30, nil).Once()
+	mockRepo.On("GetMaxRepsRecord", mock.Anything, int64(1)).
+		Return(model.MaxRepsHistoryItem{MaxReps: 30}, nil).Once()
+	mockRepo.On("AddPushupSet", mock.Anything, int64(1), model.VariantStandard, 30, 30, false).Return(30, nil).Once()
+	mockRepo.On("GetFirstNormCompleter", mock.Anything).Return(int64(0), nil).Once()
+	mockRepo.On("GetAchievementStats", mock.Anything, int64(1)).Return(model.AchievementStats{}, nil).Maybe()
+	mockRepo.On("GetNormCompletionDates", mock.Anything, int64(1)).Return(nil, nil).Maybe()
+	mockRepo.On("GetUserAchievements", mock.Anything, int64(1)).Return(nil, nil).Maybe()
+	mockRepo.On("GetWeeklyGoal", mock.Anything, int64(1)).Return(goal, nil)
+	mockRepo.On("GetRestPeriods", mock.Anything, int64(1)).Return([]model.RestPeriod(nil), nil)
+	mockRepo.On("GetDailyTotals", mock.Anything, int64(1), mock.Anything).Return([]model.DailyTotal{
+		{Date: WeekStart(today).AddDate(0, 0, -21), Count: 120},
+		{Date: WeekStart(today).AddDate(0, 0, -14), Count: 130},
+		{Date: WeekStart(today).AddDate(0, 0, -7), Count: 150},
+		{Date: today, Count: 110},
+	}, nil)
+	// Четыре недели подряд открывают недельное достижение, а не дневные серии
+	mockRepo.On("UnlockAchievement", mock.Anything, int64(1), "week_streak_4").Return(true, nil).Once()
+
+	service := NewPushupService(mockRepo, Config{})
+
+	vm, err := service.AddPushupSet(context.Background(), 1, model.VariantStandard, 30)
+
+	assert.NoError(t, err)
+	if assert.NotNil(t, vm.Weekly) {
+		assert.Equal(t, 110, vm.Weekly.Total)
+		assert.True(t, vm.Weekly.JustCompleted)
+		assert.Equal(t, 4, vm.Weekly.Streak)
+	}
+	if assert.Len(t, vm.NewAchievements, 1) {
+		assert.Equal(t, "week_streak_4", vm.NewAchievements[0].Code)
+	}
+	mockRepo.AssertNotCalled(t, "UnlockAchievement", mock.Anything, int64(1), "streak_7")
+}
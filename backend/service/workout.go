@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"trackerbot/model"
+)
+
+// SummarizeWorkout подводит итоги тренировки по её подходам
+// Аргументы:
+//
+//	session  - идущая тренировка
+//	sets     - подходы тренировки в порядке записи (в обычных отжиманиях)
+//	now      - время завершения
+//	previous - прошлая тренировка для сравнения (может быть nil)
+func SummarizeWorkout(
+	session model.WorkoutSession,
+	sets []int,
+	now time.Time,
+	previous *model.WorkoutSession,
+) model.WorkoutSummary {
+
+	session.FinishedAt = &now
+	session.Sets = len(sets)
+	session.Total = sumSets(sets)
+	session.BestSet = 0
+	for _, reps := range sets {
+		session.BestSet = max(session.BestSet, reps)
+	}
+
+	summary := model.WorkoutSummary{
+		Session:  session,
+		Duration: now.Sub(session.StartedAt),
+		Previous: previous,
+	}
+	if session.Sets > 0 {
+		summary.Average = float64(session.Total) / float64(session.Sets)
+	}
+
+	return summary
+}
+
+// IsWorkoutExpired сообщает, что тренировку забыли завершить: с начала прошло больше WorkoutSessionTimeout
+func IsWorkoutExpired(session model.WorkoutSession, now time.Time) bool {
+	return now.Sub(session.StartedAt) > model.WorkoutSessionTimeout
+}
+
+// closeExpiredWorkout закрывает забытую тренировку на последнем подходе (или на старте, если подходов нет)
+func (s *pushupService) closeExpiredWorkout(
+	ctx context.Context,
+	session model.WorkoutSession,
+	previous *model.WorkoutSession,
+) (*model.WorkoutSummary, error) {
+
+	sets, err := s.repo.GetWorkoutSessionSets(ctx, session.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	finishedAt := session.StartedAt
+	if session.LastSetAt != nil {
+		finishedAt = *session.LastSetAt
+	}
+
+	summary := SummarizeWorkout(session, sets, finishedAt, previous)
+	summary.AutoClosed = true
+	if err := s.repo.FinishWorkoutSession(ctx, summary.Session); err != nil {
+		return nil, err
+	}
+
+	return &summary, nil
+}
+
+// StartWorkout начинает тренировку: следующие подходы будут привязаны к ней.
+// Забытая тренировка закрывается автоматически
+func (s *pushupService) StartWorkout(ctx context.Context, userID int64) (model.WorkoutSession, error) {
+	open, err := s.repo.GetOpenWorkoutSession(ctx, userID)
+	if err != nil {
+		return model.WorkoutSession{}, err
+	}
+	if open != nil && IsWorkoutExpired(*open, time.Now()) {
+		if _, err := s.closeExpiredWorkout(ctx, *open, nil); err != nil {
+			return model.WorkoutSession{}, err
+		}
+		open = nil
+	}
+	if open != nil {
+		return model.WorkoutSession{}, workoutRunningError(*open)
+	}
+
+	session, err := s.repo.StartWorkoutSession(ctx, userID)
+	if err != nil {
+		return model.WorkoutSession{}, err
+	}
+	if session == nil {
+		// Тренировку успели открыть параллельным нажатием
+		open, err := s.repo.GetOpenWorkoutSession(ctx, userID)
+		if err != nil {
+			return model.WorkoutSession{}, err
+		}
+		if open == nil {
+			return model.WorkoutSession{}, fmt.Errorf("тренировка уже идёт — завершите её кнопкой «⏹ Завершить»")
+		}
+		return model.WorkoutSession{}, workoutRunningError(*open)
+	}
+
+	return *session, nil
+}
+
+// workoutRunningError сообщает, что у пользователя уже идёт тренировка
+func workoutRunningError(open model.WorkoutSession) error {
+	return fmt.Errorf(
+		"тренировка уже идёт с %s — завершите её кнопкой «⏹ Завершить»",
+		open.StartedAt.Format("15:04"),
+	)
+}
+
+// FinishWorkout завершает тренировку, сохраняет и возвращает её итоги
+// вместе с прошлой тренировкой для сравнения
+func (s *pushupService) FinishWorkout(ctx context.Context, userID int64) (*model.WorkoutSummary, error) {
+	open, err := s.repo.GetOpenWorkoutSession(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if open == nil {
+		return nil, fmt.Errorf("нет начатой тренировки — нажмите «▶️ Начать тренировку»")
+	}
+
+	// Прошлую тренировку берём до сохранения текущей
+	previous, err := s.repo.GetLastWorkoutSession(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if IsWorkoutExpired(*open, now) {
+		return s.closeExpiredWorkout(ctx, *open, previous)
+	}
+
+	sets, err := s.repo.GetWorkoutSessionSets(ctx, open.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := SummarizeWorkout(*open, sets, now, previous)
+	if err := s.repo.FinishWorkoutSession(ctx, summary.Session); err != nil {
+		return nil, err
+	}
+
+	return &summary, nil
+}
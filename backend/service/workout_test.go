@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"trackerbot/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSummarizeWorkout(t *testing.T) {
+	started := time.Date(2026, 3, 10, 18, 0, 0, 0, time.Local)
+	now := started.Add(32 * time.Minute)
+
+	summary := SummarizeWorkout(model.WorkoutSession{ID: 7, StartedAt: started}, []int{15, 20, 12, 13}, now, nil)
+
+	assert.Equal(t, 32*time.Minute, summary.Duration)
+	assert.Equal(t, 4, summary.Session.Sets)
+	assert.Equal(t, 60, summary.Session.Total)
+	assert.Equal(t, 20, summary.Session.BestSet)
+	assert.Equal(t, 15.0, summary.Average)
+	assert.Equal(t, now, *summary.Session.FinishedAt)
+	assert.Nil(t, summary.Previous)
+}
+
+func TestSummarizeWorkout_NoSets(t *testing.T) {
+	started := time.Date(2026, 3, 10, 18, 0, 0, 0, time.Local)
+
+	summary := SummarizeWorkout(model.WorkoutSession{StartedAt: started}, nil, started.Add(time.Minute), nil)
+
+	assert.Equal(t, 0, summary.Session.Sets)
+	assert.Equal(t, 0.0, summary.Average)
+}
+
+func TestService_StartWorkout_AlreadyOpen(t *testing.T) {
+	mockRepo := new(MockPushupRepository)
+	svc := NewPushupService(mockRepo, Config{})
+	ctx := context.Background()
+
+	mockRepo.On("GetOpenWorkoutSession", ctx, int64(1)).
+		Return(&model.WorkoutSession{ID: 3, StartedAt: time.Now()}, nil).Once()
+
+	_, err := svc.StartWorkout(ctx, 1)
+
+	assert.Error(t, err)
+	mockRepo.AssertNotCalled(t, "StartWorkoutSession", mock.Anything, mock.Anything)
+}
+
+func TestService_StartWorkout_ConcurrentStart(t *testing.T) {
+	mockRepo := new(MockPushupRepository)
+	svc := NewPushupService(mockRepo, Config{})
+	ctx := context.Background()
+
+	started := time.Date(2026, 3, 10, 18, 5, 0, 0, time.Local)
+
+	mockRepo.On("GetOpenWorkoutSession", ctx, int64(1)).Return(nil, nil).Once()
+	mockRepo.On("StartWorkoutSession", ctx, int64(1)).Return(nil, nil).Once()
+	mockRepo.On("GetOpenWorkoutSession", ctx, int64(1)).
+		Return(&model.WorkoutSession{ID: 3, StartedAt: started}, nil).Once()
+
+	_, err := svc.StartWorkout(ctx, 1)
+
+	assert.EqualError(t, err, "тренировка уже идёт с 18:05 — завершите её кнопкой «⏹ Завершить»")
+	mockRepo.AssertExpectations(t)
+}
+
+func TestService_FinishWorkout(t *testing.T) {
+	mockRepo := new(MockPushupRepository)
+	svc := NewPushupService(mockRepo, Config{})
+	ctx := context.Background()
+
+	open := &model.WorkoutSession{ID: 3, StartedAt: time.Now().Add(-20 * time.Minute)}
+	previous := &model.WorkoutSession{ID: 2, Sets: 3, Total: 40, BestSet: 15}
+
+	mockRepo.On("GetOpenWorkoutSession", ctx, int64(1)).Return(open, nil).Once()
+	mockRepo.On("GetWorkoutSessionSets", ctx, int64(3)).Return([]int{15, 15, 20}, nil).Once()
+	mockRepo.On("GetLastWorkoutSession", ctx, int64(1)).Return(previous, nil).Once()
+	mockRepo.On("FinishWorkoutSession", ctx, mock.MatchedBy(func(s model.WorkoutSession) bool {
+		return s.ID == 3 && s.Sets == 3 && s.Total == 50 && s.BestSet == 20 && s.FinishedAt != nil
+	})).Return(nil).Once()
+
+	summary, err := svc.FinishWorkout(ctx, 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, previous, summary.Previous)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestService_FinishWorkout_ClosesExpiredOnLastSet(t *testing.T) {
+	mockRepo := new(MockPushupRepository)
+	svc := NewPushupService(mockRepo, Config{})
+	ctx := context.Background()
+
+	// Начали вчера, последний подход — через 25 минут после начала, «Завершить» нажали сегодня
+	started := time.Now().Add(-26 * time.Hour)
+	lastSet := started.Add(25 * time.Minute)
+	open := &model.WorkoutSession{ID: 3, StartedAt: started, LastSetAt: &lastSet}
+
+	mockRepo.On("GetOpenWorkoutSession", ctx, int64(1)).Return(open, nil).Once()
+	mockRepo.On("GetLastWorkoutSession", ctx, int64(1)).Return(nil, nil).Once()
+	mockRepo.On("GetWorkoutSessionSets", ctx, int64(3)).Return([]int{15, 20}, nil).Once()
+	mockRepo.On("FinishWorkoutSession", ctx, mock.MatchedBy(func(s model.WorkoutSession) bool {
+		return s.ID == 3 && s.FinishedAt != nil && s.FinishedAt.Equal(lastSet)
+	})).Return(nil).Once()
+
+	summary, err := svc.FinishWorkout(ctx, 1)
+
+	assert.NoError(t, err)
+	assert.True(t, summary.AutoClosed)
+	assert.Equal(t, 25*time.Minute, summary.Duration)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestService_StartWorkout_ClosesExpired(t *testing.T) {
+	mockRepo := new(MockPushupRepository)
+	svc := NewPushupService(mockRepo, Config{})
+	ctx := context.Background()
+
+	open := &model.WorkoutSession{ID: 3, StartedAt: time.Now().Add(-model.WorkoutSessionTimeout - time.Minute)}
+
+	mockRepo.On("GetOpenWorkoutSession", ctx, int64(1)).Return(open, nil).Once()
+	mockRepo.On("GetWorkoutSessionSets", ctx, int64(3)).Return(nil, nil).Once()
+	mockRepo.On("FinishWorkoutSession", ctx, mock.MatchedBy(func(s model.WorkoutSession) bool {
+		return s.ID == 3 && s.FinishedAt != nil && s.FinishedAt.Equal(open.StartedAt)
+	})).Return(nil).Once()
+	mockRepo.On("StartWorkoutSession", ctx, int64(1)).Return(&model.WorkoutSession{ID: 4}, nil).Once()
+
+	session, err := svc.StartWorkout(ctx, 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), session.ID)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestService_FinishWorkout_NotStarted(t *testing.T) {
+	mockRepo := new(MockPushupRepository)
+	svc := NewPushupService(mockRepo, Config{})
+	ctx := context.Background()
+
+	mockRepo.On("GetOpenWorkoutSession", ctx, int64(1)).Return(nil, nil).Once()
+
+	_, err := svc.FinishWorkout(ctx, 1)
+
+	assert.Error(t, err)
+	mockRepo.AssertExpectations(t)
+}